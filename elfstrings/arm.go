@@ -0,0 +1,261 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"sort"
+	"strings"
+)
+
+// armRegion is part of an executable section which is all in one
+// instruction set, either ARM or Thumb
+type armRegion struct {
+	start uint64
+	end   uint64
+	thumb bool
+}
+
+// readerARMRegions will split the section into the ARM and Thumb regions,
+// using the $a, $t and $d mapping symbols or otherwise the low bit of the
+// function symbols, the literal pools marked as data are left out
+func (r *ElfReader) readerARMRegions(s *elf.Section) []armRegion {
+	type mark struct {
+		addr  uint64
+		thumb bool
+		data  bool
+	}
+
+	var marks []mark
+	var mapped bool
+
+	syms, _ := r.ExecReader.Symbols()
+	for _, sym := range syms {
+		if sym.Value < s.Addr || sym.Value > s.Addr+s.Size {
+			continue
+		}
+
+		switch {
+		case sym.Name == "$a" || strings.HasPrefix(sym.Name, "$a."):
+			marks = append(marks, mark{addr: sym.Value})
+			mapped = true
+		case sym.Name == "$t" || strings.HasPrefix(sym.Name, "$t."):
+			marks = append(marks, mark{addr: sym.Value, thumb: true})
+			mapped = true
+		case sym.Name == "$d" || strings.HasPrefix(sym.Name, "$d."):
+			marks = append(marks, mark{addr: sym.Value, data: true})
+			mapped = true
+		}
+	}
+
+	if !mapped {
+		for _, fn := range r.readerFunctionSymbols() {
+			if fn.Value&^1 >= s.Addr && fn.Value&^1 < s.Addr+s.Size {
+				marks = append(marks, mark{addr: fn.Value &^ 1, thumb: fn.Value&1 != 0})
+			}
+		}
+	}
+
+	sort.SliceStable(marks, func(i, j int) bool {
+		return marks[i].addr < marks[j].addr
+	})
+
+	// without any symbols the entry point says which set is used
+	thumb := r.ExecReader.Entry&1 != 0
+	if len(marks) == 0 || marks[0].addr > s.Addr {
+		marks = append([]mark{{addr: s.Addr, thumb: thumb}}, marks...)
+	}
+
+	var regions []armRegion
+	for i, m := range marks {
+		end := s.Addr + s.Size
+		if i+1 < len(marks) {
+			end = marks[i+1].addr
+		}
+
+		if m.data || end <= m.addr {
+			continue
+		}
+
+		regions = append(regions, armRegion{start: m.addr, end: end, thumb: m.thumb})
+	}
+
+	return regions
+}
+
+// readerFunctionSymbols will return the function symbols of the binary
+// with their values as they are, the low bit marks Thumb functions on ARM
+func (r *ElfReader) readerFunctionSymbols() []elf.Symbol {
+	var funcs []elf.Symbol
+
+	for _, load := range []func() ([]elf.Symbol, error){r.ExecReader.Symbols, r.ExecReader.DynamicSymbols} {
+		syms, err := load()
+		if err != nil {
+			continue
+		}
+
+		for _, sym := range syms {
+			if elf.ST_TYPE(sym.Info) == elf.STT_FUNC && sym.Value != 0 {
+				funcs = append(funcs, sym)
+			}
+		}
+	}
+
+	return funcs
+}
+
+// utilXrefsARM will decode the ARM or Thumb code at addr and pass each
+// address that it references to add. Addresses are loaded from literal
+// pools relative to the pc, which hold either the address itself or in
+// position independent code an offset which is then added to the pc,
+// or are built from a movw and movt pair
+func utilXrefsARM(code []byte, addr uint64, thumb bool, mem *memory, add func(from uint64, to uint64)) {
+	if thumb {
+		utilXrefsThumb(code, addr, mem, add)
+		return
+	}
+
+	var regs pairs
+
+	order := mem.order
+	for off := 0; off+4 <= len(code); off += 4 {
+		w := order.Uint32(code[off:])
+		pc := addr + uint64(off)
+		rd := (w >> 12) & 15
+
+		switch {
+		case w&0x0f7f0000 == 0x051f0000:
+			// ldr rt, [pc, #imm]
+			target := pc + 8 + uint64(w&0xfff)
+			if w&(1<<23) == 0 {
+				target = pc + 8 - uint64(w&0xfff)
+			}
+
+			utilARMLiteral(pc, target, rd, &regs, mem, add)
+			continue
+		case w&0x0fef0ff0 == 0x008f0000:
+			// add rd, pc, rm
+			if offset, ok := regs.get(w&15, pc); ok {
+				target := (pc + 8 + offset) & 0xffffffff
+				add(pc, target)
+				regs.set(rd, target, pc)
+				continue
+			}
+		case w&0x0fff0000 == 0x028f0000, w&0x0fff0000 == 0x024f0000:
+			// adr rd, label, which is an add or sub of the pc
+			imm := uint64(utilRotateRight(w&0xff, 2*((w>>8)&15)))
+			target := pc + 8 + imm
+			if w&0x0fff0000 == 0x024f0000 {
+				target = pc + 8 - imm
+			}
+
+			add(pc, target&0xffffffff)
+		case w&0x0ff00000 == 0x03000000:
+			// movw rd, #imm16
+			regs.set(rd, uint64((w>>4)&0xf000|w&0xfff), pc)
+			continue
+		case w&0x0ff00000 == 0x03400000:
+			// movt rd, #imm16
+			if low, ok := regs.get(rd, pc); ok {
+				target := low&0xffff | uint64((w>>4)&0xf000|w&0xfff)<<16
+				add(pc, target)
+				regs.set(rd, target, pc)
+				continue
+			}
+		}
+
+		// most instructions write to the register in bits 12-15, but
+		// the stores only read from it
+		if w&0x0c000000 == 0x04000000 && w&(1<<20) == 0 {
+			continue
+		}
+
+		regs.clear(rd)
+	}
+}
+
+// utilXrefsThumb will decode the Thumb code at addr
+func utilXrefsThumb(code []byte, addr uint64, mem *memory, add func(from uint64, to uint64)) {
+	var regs pairs
+
+	order := mem.order
+	for off := 0; off+2 <= len(code); {
+		h := uint32(order.Uint16(code[off:]))
+		pc := addr + uint64(off)
+
+		// the pc reads as the instruction plus four, word aligned
+		// for literal loads
+		aligned := (pc + 4) &^ 3
+
+		if h>>11 >= 0x1d && off+4 <= len(code) {
+			h2 := uint32(order.Uint16(code[off+2:]))
+			rd := (h2 >> 8) & 15
+
+			switch {
+			case h&0xff7f == 0xf85f:
+				// ldr.w rt, [pc, #imm12]
+				target := aligned + uint64(h2&0xfff)
+				if h&(1<<7) == 0 {
+					target = aligned - uint64(h2&0xfff)
+				}
+
+				utilARMLiteral(pc, target, h2>>12, &regs, mem, add)
+			case h&0xfbf0 == 0xf240 && h2&0x8000 == 0:
+				// movw rd, #imm16
+				regs.set(rd, uint64(utilThumbImm16(h, h2)), pc)
+			case h&0xfbf0 == 0xf2c0 && h2&0x8000 == 0:
+				// movt rd, #imm16
+				if low, ok := regs.get(rd, pc); ok {
+					target := low&0xffff | uint64(utilThumbImm16(h, h2))<<16
+					add(pc, target)
+					regs.set(rd, target, pc)
+				}
+			}
+
+			off += 4
+			continue
+		}
+
+		switch {
+		case h&0xf800 == 0x4800:
+			// ldr rt, [pc, #imm8]
+			utilARMLiteral(pc, aligned+uint64(h&0xff)*4, (h>>8)&7, &regs, mem, add)
+		case h&0xff78 == 0x4478:
+			// add rdn, pc
+			rdn := h&7 | (h>>4)&8
+			if offset, ok := regs.get(rdn, pc); ok {
+				target := (pc + 4 + offset) & 0xffffffff
+				add(pc, target)
+				regs.set(rdn, target, pc)
+			}
+		case h&0xf800 == 0xa000:
+			// adr rd, label
+			add(pc, aligned+uint64(h&0xff)*4)
+		}
+
+		off += 2
+	}
+}
+
+// utilARMLiteral will follow a literal load from the pool at target into
+// rt, the word loaded is either an address or an offset from the pc which
+// is added to it later, so it is remembered in case it is the latter
+func utilARMLiteral(pc uint64, target uint64, rt uint32, regs *pairs, mem *memory, add func(from uint64, to uint64)) {
+	word, ok := mem.load(target, 4)
+	if !ok {
+		regs.clear(rt)
+		return
+	}
+
+	add(pc, word)
+	regs.set(rt, word, pc)
+}
+
+// utilThumbImm16 will decode the 16 bit immediate of a movw or movt
+func utilThumbImm16(h uint32, h2 uint32) uint32 {
+	return (h&15)<<12 | (h>>10&1)<<11 | (h2>>12&7)<<8 | h2&0xff
+}
+
+// utilRotateRight will rotate the 32 bit value right
+func utilRotateRight(v uint32, n uint32) uint32 {
+	n &= 31
+	return v>>n | v<<(32-n)
+}
@@ -0,0 +1,247 @@
+package elfstrings
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// utilXrefsARM64 will decode the AArch64 code at addr and pass each address
+// that it references to add. Addresses are built from an adrp of the page
+// followed by an add or a load of the offset into it, or are read from a
+// literal load relative to the instruction
+func utilXrefsARM64(code []byte, addr uint64, mem *memory, add func(from uint64, to uint64)) {
+	var regs pairs
+
+	for off := 0; off+4 <= len(code); off += 4 {
+		// instructions are always little endian, whatever the data is
+		w := binary.LittleEndian.Uint32(code[off:])
+		pc := addr + uint64(off)
+		rd := w & 31
+		rn := (w >> 5) & 31
+
+		switch {
+		case w&0x9f000000 == 0x90000000:
+			// adrp xd, page
+			imm := utilSignExtend(uint64((w>>5)&0x7ffff)<<2|uint64((w>>29)&3), 21)
+			regs.set(rd, (pc&^0xfff)+imm<<12, pc)
+			continue
+		case w&0x9f000000 == 0x10000000:
+			// adr xd, label
+			imm := utilSignExtend(uint64((w>>5)&0x7ffff)<<2|uint64((w>>29)&3), 21)
+			add(pc, pc+imm)
+		case w&0x7f800000 == 0x11000000:
+			// add xd, xn, #imm{, lsl #12}
+			if base, ok := regs.get(rn, pc); ok {
+				imm := uint64((w >> 10) & 0xfff)
+				if w&(1<<22) != 0 {
+					imm <<= 12
+				}
+
+				add(pc, base+imm)
+				regs.set(rd, base+imm, pc)
+				continue
+			}
+		case w&0x3b000000 == 0x39000000:
+			// ldr and str with an unsigned offset, scaled by the size
+			if base, ok := regs.get(rn, pc); ok {
+				scale := w >> 30
+				if w&(1<<26) != 0 && w&(1<<23) != 0 {
+					scale = 4
+				}
+
+				target := base + uint64((w>>10)&0xfff)<<scale
+				add(pc, target)
+
+				// a load of a pointer, such as from the GOT
+				if w&(1<<22) != 0 && w&(1<<26) == 0 && scale >= 2 {
+					if ptr, ok := mem.load(target, 1<<scale); ok {
+						add(pc, ptr)
+					}
+				}
+			}
+
+			// stores do not write to the register
+			if w&(1<<22) == 0 {
+				continue
+			}
+		case w&0x3b000000 == 0x18000000:
+			// ldr xt, label
+			target := pc + utilSignExtend(uint64((w>>5)&0x7ffff)<<2, 21)
+			add(pc, target)
+
+			size := 4
+			if w&(1<<30) != 0 {
+				size = 8
+			}
+
+			if w&(1<<26) == 0 {
+				if ptr, ok := mem.load(target, size); ok {
+					add(pc, ptr)
+				}
+			}
+		}
+
+		// nearly every instruction writing to a register has it in the
+		// lowest bits, so whatever was held there is forgotten
+		regs.clear(rd)
+	}
+}
+
+// utilSignExtend will sign extend the value from the given number of bits
+func utilSignExtend(v uint64, bits uint) uint64 {
+	shift := 64 - bits
+	return uint64(int64(v<<shift) >> shift)
+}
+
+// utilBitMask will decode the bitmask immediate of a logical instruction
+// from its n, immr and imms fields, a run of ones rotated right by immr
+// and repeated across the register. false is returned for the reserved
+// encodings
+func utilBitMask(n uint32, immr uint32, imms uint32, is64 bool) (uint64, bool) {
+	// the size of the element is given by the highest bit of n:~imms
+	length := bits.Len32(n<<6|^imms&0x3f) - 1
+	if length < 1 || !is64 && n != 0 {
+		return 0, false
+	}
+
+	size := uint(1) << uint(length)
+	levels := uint32(size - 1)
+	if imms&levels == levels {
+		return 0, false
+	}
+
+	mask := uint64(1)<<size - 1
+	if size == 64 {
+		mask = ^uint64(0)
+	}
+
+	elem := uint64(1)<<(imms&levels+1) - 1
+	if r := uint(immr & levels); r != 0 {
+		elem = (elem>>r | elem<<(size-r)) & mask
+	}
+
+	var val uint64
+	for i := uint(0); i < 64; i += size {
+		val |= elem << i
+	}
+
+	if !is64 {
+		val &= 0xffffffff
+	}
+
+	return val, true
+}
+
+// utilStackARM64 will follow the stores to the stack in the AArch64 code at
+// addr of the registers that were just loaded with an immediate, passing
+// them to the block. The immediates are built with movz and movk, or are
+// the zero register. The block ends at every branch, and whenever the
+// stack or frame pointer moves as the offsets no longer line up
+func utilStackARM64(code []byte, addr uint64, block *stackBlock) {
+	// known are the registers loaded with an immediate, the zero
+	// register is always known
+	var known pairs
+
+	value := func(reg uint32, pc uint64) (uint64, bool) {
+		if reg == 31 {
+			return 0, true
+		}
+
+		return known.get(reg, pc)
+	}
+
+	reset := func() {
+		block.flush()
+		known = pairs{}
+	}
+
+	for off := 0; off+4 <= len(code); off += 4 {
+		w := binary.LittleEndian.Uint32(code[off:])
+		pc := addr + uint64(off)
+		rd := w & 31
+		rn := int((w >> 5) & 31)
+		is64 := w&(1<<31) != 0
+
+		switch {
+		case w&0x7c000000 == 0x14000000, w&0xfe000000 == 0xd6000000,
+			w&0xff000010 == 0x54000000, w&0x7c000000 == 0x34000000:
+			// b, bl, br, blr, ret, b.cond, cbz, cbnz, tbz and tbnz
+			reset()
+			continue
+		case w&0x7f800000 == 0x52800000:
+			// movz rd, #imm16, lsl #shift
+			shift := ((w >> 21) & 3) * 16
+			known.set(rd, uint64((w>>5)&0xffff)<<shift, pc)
+			continue
+		case w&0x7f800000 == 0x12800000:
+			// movn rd, #imm16, lsl #shift
+			shift := ((w >> 21) & 3) * 16
+			val := ^(uint64((w>>5)&0xffff) << shift)
+			if !is64 {
+				val &= 0xffffffff
+			}
+
+			known.set(rd, val, pc)
+			continue
+		case w&0x7f800000 == 0x72800000:
+			// movk rd, #imm16, lsl #shift
+			if val, ok := known.get(rd, pc); ok {
+				shift := ((w >> 21) & 3) * 16
+				val = val&^(0xffff<<shift) | uint64((w>>5)&0xffff)<<shift
+				known.set(rd, val, pc)
+				continue
+			}
+		case w&0x3b200400 == 0x38000400, w&0x3b800000 == 0x28800000, w&0x3b800000 == 0x29800000:
+			// the stores and loads which write back to their base
+			if rn == 29 || rn == 31 {
+				reset()
+				continue
+			}
+		case w&0x3b000000 == 0x39000000 && w&(1<<22) == 0 && w&(1<<26) == 0:
+			// str rt, [rn, #imm] with an unsigned offset scaled by the size
+			size := 1 << (w >> 30)
+			utilStackStoreARM64(block, rn, int64((w>>10)&0xfff)*int64(size), rd, size, value, pc)
+			continue
+		case w&0x3b200c00 == 0x38000000 && w&(1<<22) == 0 && w&(1<<26) == 0:
+			// stur rt, [rn, #simm9]
+			size := 1 << (w >> 30)
+			utilStackStoreARM64(block, rn, int64(utilSignExtend(uint64((w>>12)&0x1ff), 9)), rd, size, value, pc)
+			continue
+		case w&0x7fc00000 == 0x29000000:
+			// stp rt, rt2, [rn, #imm] of 32 or 64 bit registers
+			size := 4
+			if is64 {
+				size = 8
+			}
+
+			off := int64(utilSignExtend(uint64((w>>15)&0x7f), 7)) * int64(size)
+			utilStackStoreARM64(block, rn, off, rd, size, value, pc)
+			utilStackStoreARM64(block, rn, off+int64(size), (w>>10)&31, size, value, pc)
+			continue
+		case w&0x3a000000 == 0x28000000 && w&(1<<22) != 0:
+			// ldp rt, rt2, which also writes to the second register
+			known.clear((w >> 10) & 31)
+		case w&0x1f000000 == 0x11000000 && (rd == 29 || rd == 31):
+			// add and sub of the stack or frame pointer
+			reset()
+			continue
+		}
+
+		known.clear(rd)
+	}
+}
+
+// utilStackStoreARM64 will pass a store of the register rt to the stack
+// to the block, as long as its value is known
+func utilStackStoreARM64(block *stackBlock, rn int, off int64, rt uint32, size int, value func(uint32, uint64) (uint64, bool), pc uint64) {
+	if rn != 29 && rn != 31 {
+		return
+	}
+
+	if val, ok := value(rt, pc); ok {
+		block.store(rn, off, val, size, pc)
+		return
+	}
+
+	block.clobber(rn, off, size)
+}
@@ -0,0 +1,315 @@
+package elfstrings
+
+import (
+	"bytes"
+	"debug/elf"
+	"sort"
+	"strings"
+)
+
+// The limits of the brute force, which hold back the many runs that
+// any key turns random bytes into
+const (
+	// bruteMinLength is the shortest string that is brute forced,
+	// shorter runs are too often readable by chance
+	bruteMinLength = 8
+	// bruteMinScore is the lowest readability score of a brute forced
+	// string, whatever the minimum score of the options is
+	bruteMinScore = 0.55
+	// bruteMinGain is how much more readable the string must be than
+	// the bytes it was decoded from, so that text is not taken to be
+	// encoded with a key that only changes its case
+	bruteMinGain = 0.15
+	// bruteMaxPlain is the highest readability score of the bytes that
+	// a string is decoded from, those more readable are already shown
+	// as they are and only turn into other words with a key
+	bruteMaxPlain = 0.45
+	// bruteMaxPeriod is the longest pattern that the bytes of a string
+	// are checked for repeating, as a table of repeating values turns
+	// into a repeating string with any key, and with a repeating key
+	// that has the same period into what looks like one
+	bruteMaxPeriod = 8
+	// bruteMaxRepeat is the share of the bytes which may repeat the
+	// pattern before they are taken to be a table
+	bruteMaxRepeat = 0.5
+	// bruteMaxSymbols is the share of a string which may be symbols
+	// other than those of paths and addresses, which are most of what
+	// a key turns random bytes into
+	bruteMaxSymbols = 0.15
+)
+
+// bruteCribs are the plaintexts that are looked for to find the keys
+// of repeating XOR, as they are in most strings worth hiding
+var bruteCribs = [][]byte{
+	[]byte("http"),
+	[]byte("/bin/"),
+	[]byte(".so"),
+}
+
+// bruteCandidate is a run of the section which decodes to a string
+type bruteCandidate struct {
+	off      uint64
+	raw      []byte
+	decoding Decoding
+	filtered *FilteredString
+}
+
+// ReaderBruteForce will try to decode the section with every single byte
+// XOR, ADD and ROL key, and with the short repeating XOR keys that turn
+// its bytes into a crib such as "http". The runs which decode to readable
+// strings are returned along with the key and transform which decoded
+// them, the most readable decoding of each run is kept
+func (r *ElfReader) ReaderBruteForce(s *elf.Section, opts *Options) []StringRecord {
+	var records []StringRecord
+
+	sect := r.ReaderReadSection(s)
+	if sect == nil {
+		return nil
+	}
+
+	var candidates []bruteCandidate
+	add := func(c *bruteCandidate) {
+		if c != nil {
+			candidates = append(candidates, *c)
+		}
+	}
+
+	for _, transform := range []Transform{TransformXOR, TransformADD, TransformROL} {
+		keys := 255
+		if transform == TransformROL {
+			keys = 7
+		}
+
+		for key := 1; key <= keys; key++ {
+			table := utilBruteTable(transform, byte(key))
+			decoding := Decoding{Transform: transform, Key: []byte{byte(key)}}
+
+			start := 0
+			for i := 0; i <= len(sect); i++ {
+				if i < len(sect) && sect[i] != 0 && UtilIsPrintable(table[sect[i]]) {
+					continue
+				}
+
+				terminated := func(j int) bool {
+					return j < 0 || j >= len(sect) || utilIsTerminator(sect[j], table[sect[j]])
+				}
+
+				if i-start >= bruteMinLength && terminated(start-1) && terminated(i) {
+					raw := make([]byte, i-start)
+					for j := range raw {
+						raw[j] = table[sect[start+j]]
+					}
+
+					add(utilBruteCandidate(sect[start:i], raw, uint64(start), decoding, opts))
+				}
+
+				start = i + 1
+			}
+		}
+	}
+
+	for _, crib := range bruteCribs {
+		for size := 2; size <= len(crib); size++ {
+			for i := 0; i+len(crib) <= len(sect); i++ {
+				add(utilBruteRepeating(sect, i, crib, size, opts))
+			}
+		}
+	}
+
+	for _, c := range utilBruteBest(candidates) {
+		rec := r.readerRecord(s, c.off, c.raw, c.filtered)
+		rec.Decodings = []Decoding{c.decoding}
+		records = append(records, rec)
+	}
+
+	records = UtilLimitRecords(records, opts)
+	if opts.Xrefs {
+		r.readerAttachXrefs(records)
+	}
+
+	return records
+}
+
+// utilBruteTable will build the table which undoes the transform
+// with the key for every byte
+func utilBruteTable(transform Transform, key byte) [256]byte {
+	var table [256]byte
+
+	for i := range table {
+		b := byte(i)
+		switch transform {
+		case TransformXOR:
+			table[i] = b ^ key
+		case TransformADD:
+			table[i] = b - key
+		case TransformROL:
+			table[i] = b>>key | b<<(8-key)
+		}
+	}
+
+	return table
+}
+
+// utilBruteRepeating will find the repeating XOR key of the size which
+// turns the bytes at off into the crib, and decode the run around them
+// with it. nil is returned when there is no such key, or when the key
+// is one that the single byte keys have already tried
+func utilBruteRepeating(sect []byte, off int, crib []byte, size int, opts *Options) *bruteCandidate {
+	key := make([]byte, size)
+	for j := range crib {
+		k := sect[off+j] ^ crib[j]
+		if k == 0 {
+			return nil
+		}
+
+		if j < size {
+			key[j] = k
+		} else if key[j%size] != k {
+			return nil
+		}
+	}
+
+	// a key which repeats within itself is a shorter key
+	for period := 1; period < size; period++ {
+		if size%period == 0 && bytes.Equal(key[period:], key[:size-period]) {
+			return nil
+		}
+	}
+
+	at := func(i int) byte {
+		return sect[i] ^ key[((i-off)%size+size)%size]
+	}
+
+	start, end := off, off+len(crib)
+	for start > 0 && sect[start-1] != 0 && UtilIsPrintable(at(start-1)) {
+		start--
+	}
+
+	for end < len(sect) && sect[end] != 0 && UtilIsPrintable(at(end)) {
+		end++
+	}
+
+	if start > 0 && !utilIsTerminator(sect[start-1], at(start-1)) {
+		return nil
+	}
+
+	if end < len(sect) && !utilIsTerminator(sect[end], at(end)) {
+		return nil
+	}
+
+	// a key as long as the crib could turn any bytes into it, so it
+	// must go on to decode a few more of its repeats
+	if end-start < bruteMinLength || end-start < len(crib)+2*size {
+		return nil
+	}
+
+	raw := make([]byte, end-start)
+	for j := range raw {
+		raw[j] = at(start + j)
+	}
+
+	// the key is turned to start at the first byte of the string
+	rotated := make([]byte, size)
+	for j := range rotated {
+		rotated[j] = key[((start-off+j)%size+size)%size]
+	}
+
+	return utilBruteCandidate(sect[start:end], raw, uint64(start), Decoding{Transform: TransformXOR, Key: rotated}, opts)
+}
+
+// utilIsTerminator will check whether the byte ends a string, either as
+// a terminator which was left as it is or one which was encoded too
+func utilIsTerminator(orig byte, decoded byte) bool {
+	return orig == 0 || decoded == 0
+}
+
+// utilBruteCandidate will run the decoded bytes through the filters,
+// returning nil when they are not readable enough, or when the bytes
+// they were decoded from are readable or hardly less readable
+func utilBruteCandidate(orig []byte, raw []byte, off uint64, decoding Decoding, opts *Options) *bruteCandidate {
+	// the score is far cheaper than the filters which most fail
+	score := UtilScore(string(raw)).Total
+	if score < bruteMinScore {
+		return nil
+	}
+
+	if utilIsRepeating(orig) || utilIsRepeating(raw) || utilSymbolShare(raw) > bruteMaxSymbols {
+		return nil
+	}
+
+	plain := UtilScore(string(orig)).Total
+	if plain >= bruteMaxPlain || score-plain < bruteMinGain {
+		return nil
+	}
+
+	f := UtilFilterString(raw, opts)
+	if f == nil {
+		return nil
+	}
+
+	return &bruteCandidate{off: off, raw: raw, decoding: decoding, filtered: f}
+}
+
+// utilIsRepeating will check whether most of the bytes repeat those a
+// short distance before them, as they do in a table rather than text
+func utilIsRepeating(buf []byte) bool {
+	for period := 1; period <= bruteMaxPeriod && period < len(buf); period++ {
+		same := 0
+		for i := period; i < len(buf); i++ {
+			if buf[i] == buf[i-period] {
+				same++
+			}
+		}
+
+		if float64(same) > bruteMaxRepeat*float64(len(buf)-period) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// utilSymbolShare will return the share of the bytes which are neither
+// letters, digits, spaces nor the separators of paths and addresses
+func utilSymbolShare(buf []byte) float64 {
+	symbols := 0
+	for _, b := range buf {
+		switch {
+		case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9':
+		case strings.IndexByte(" /.:-_", b) >= 0:
+		default:
+			symbols++
+		}
+	}
+
+	return float64(symbols) / float64(len(buf))
+}
+
+// utilBruteBest will keep the most readable of the candidates which
+// overlap, ordered by where they are in the section
+func utilBruteBest(candidates []bruteCandidate) []bruteCandidate {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].filtered.Score.Total > candidates[j].filtered.Score.Total
+	})
+
+	var best []bruteCandidate
+	for _, c := range candidates {
+		overlaps := false
+		for _, b := range best {
+			if c.off < b.off+uint64(len(b.raw)) && b.off < c.off+uint64(len(c.raw)) {
+				overlaps = true
+				break
+			}
+		}
+
+		if !overlaps {
+			best = append(best, c)
+		}
+	}
+
+	sort.Slice(best, func(i, j int) bool {
+		return best[i].off < best[j].off
+	})
+
+	return best
+}
@@ -0,0 +1,202 @@
+package elfstrings
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// codepage is a legacy encoding and the script that text
+// written in it is expected to be in
+type codepage struct {
+	enc    encoding.Encoding
+	script string
+}
+
+var codepages = map[Encoding]codepage{
+	EncodingShiftJIS:    {japanese.ShiftJIS, "cjk"},
+	EncodingGBK:         {simplifiedchinese.GBK, "cjk"},
+	EncodingKOI8R:       {charmap.KOI8R, "cyrillic"},
+	EncodingWindows1250: {charmap.Windows1250, "latin"},
+	EncodingWindows1251: {charmap.Windows1251, "cyrillic"},
+	EncodingWindows1252: {charmap.Windows1252, "latin"},
+	EncodingWindows1253: {charmap.Windows1253, "greek"},
+	EncodingWindows1254: {charmap.Windows1254, "latin"},
+	EncodingWindows1255: {charmap.Windows1255, "hebrew"},
+	EncodingWindows1256: {charmap.Windows1256, "arabic"},
+	EncodingWindows1257: {charmap.Windows1257, "latin"},
+	EncodingWindows1258: {charmap.Windows1258, "latin"},
+}
+
+// UtilDecodeString will detect the encoding of a NUL terminated string,
+// it is either plain ASCII, valid UTF-8 or one of the legacy encodings
+// given, falling back to EncodingBinary when it cannot be decoded
+func UtilDecodeString(raw []byte, legacy []Encoding) (string, Encoding) {
+	ascii := true
+	for _, b := range raw {
+		if b >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+
+	if ascii {
+		return string(raw), EncodingASCII
+	}
+
+	if utf8.Valid(raw) {
+		return string(raw), EncodingUTF8
+	}
+
+	if str, enc, ok := UtilDecodeLegacy(raw, legacy); ok {
+		return str, enc
+	}
+
+	return string(raw), EncodingBinary
+}
+
+// UtilDecodeLegacy will heuristically decode the string using each of
+// the legacy encodings, picking the one where the text reads the most
+// like the script the encoding is used for
+func UtilDecodeLegacy(raw []byte, legacy []Encoding) (string, Encoding, bool) {
+	var best string
+	var bestEnc Encoding
+	var bestScore float64
+
+	for _, enc := range legacy {
+		cp, ok := codepages[enc]
+		if !ok {
+			continue
+		}
+
+		out, err := cp.enc.NewDecoder().Bytes(raw)
+		if err != nil {
+			continue
+		}
+
+		str := string(out)
+
+		score := utilScriptScore(str, cp.script)
+		if score > bestScore {
+			best, bestEnc, bestScore = str, enc, score
+		}
+	}
+
+	if bestScore == 0 {
+		return "", EncodingBinary, false
+	}
+
+	return best, bestEnc, true
+}
+
+// utilScriptScore will score how much the text reads like the script,
+// zero if it does not at all. Every character outside of ASCII has to be
+// a printable letter or punctuation of the script, and the text has to be
+// mostly letters. As most text is in lowercase, decodings with more
+// lowercase letters and fewer case changes in the middle of a word score
+// higher, which is what tells apart codepages such as KOI8-R and
+// Windows-1251. Scripts without case only get part of that credit, and
+// CJK text with kana gets a little more, as kana are a sign of Shift-JIS
+func utilScriptScore(str string, script string) float64 {
+	var total, letters, ascii, wide, lower, other, flips, switches, repeats int
+	var prev rune
+	var kana bool
+
+	for _, r := range str {
+		total++
+
+		if r == utf8.RuneError || !unicode.IsPrint(r) {
+			return 0
+		}
+
+		// half-width katakana are what most random bytes
+		// decode to in Shift-JIS, so they count as symbols
+		if !unicode.IsLetter(r) || r >= 0xff61 && r <= 0xff9f {
+			if r == ' ' {
+				letters++
+			} else if r >= utf8.RuneSelf {
+				other++
+			}
+
+			prev = 0
+			continue
+		}
+
+		letters++
+
+		if unicode.IsUpper(r) && unicode.IsLower(prev) {
+			flips++
+		}
+
+		if prev != 0 && (prev < utf8.RuneSelf) != (r < utf8.RuneSelf) {
+			switches++
+		}
+
+		if r == prev && r >= utf8.RuneSelf {
+			repeats++
+		}
+
+		prev = r
+
+		if r < utf8.RuneSelf {
+			ascii++
+			continue
+		}
+
+		if UtilRuneScript(r) != script {
+			return 0
+		}
+
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana) {
+			kana = true
+		}
+
+		wide++
+		if unicode.IsLower(r) {
+			lower++
+		}
+	}
+
+	// punctuation and symbols amongst the letters, or the same letter
+	// over and over, are signs that the bytes are not text at all
+	if wide < 3 || other*4 > wide || letters*10 < total*7 || repeats > 1 {
+		return 0
+	}
+
+	switch script {
+	case "latin":
+		// accented letters are the minority in any latin text
+		if wide*3 > ascii {
+			return 0
+		}
+	case "cjk":
+		if wide*2 < ascii {
+			return 0
+		}
+	}
+
+	// outside of latin, words do not switch between ASCII and the script
+	if script != "latin" && switches != 0 {
+		return 0
+	}
+
+	if lower == 0 {
+		score := 1.75
+		if kana {
+			score += 0.25
+		}
+
+		return score
+	}
+
+	score := 1 + float64(lower-flips)/float64(wide)
+	if score <= 1 {
+		return 0
+	}
+
+	return score
+}
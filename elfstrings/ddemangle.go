@@ -0,0 +1,726 @@
+package elfstrings
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dMaxDepth is how deep the types of a D symbol may nest, as the back
+// references could otherwise refer to themselves forever
+const dMaxDepth = 256
+
+// dBasicTypes are the types of a D symbol named by a single letter
+var dBasicTypes = map[byte]string{
+	'v': "void", 'g': "byte", 'h': "ubyte", 's': "short", 't': "ushort", 'i': "int",
+	'k': "uint", 'l': "long", 'm': "ulong", 'f': "float", 'd': "double", 'e': "real",
+	'o': "ifloat", 'p': "idouble", 'j': "ireal", 'q': "cfloat", 'r': "cdouble", 'c': "creal",
+	'b': "bool", 'a': "char", 'u': "wchar", 'w': "dchar", 'n': "typeof(null)",
+}
+
+// dCallConventions are the calling conventions of the function types, D
+// itself is written as nothing
+var dCallConventions = map[byte]string{
+	'F': "", 'U': "extern(C) ", 'W': "extern(Windows) ", 'R': "extern(C++) ", 'Y': "extern(Objective-C) ",
+}
+
+// dFunctionAttrs are the attributes of the function types, after an N
+var dFunctionAttrs = map[byte]string{
+	'a': "pure ", 'b': "nothrow ", 'c': "ref ", 'd': "@property ", 'e': "@trusted ",
+	'f': "@safe ", 'i': "@nogc ", 'j': "return ", 'l': "scope ", 'm': "@live ",
+}
+
+// dSpecialNames are the names of the symbols that the compiler generates
+// for the types and modules, which are written as what they are for
+var dSpecialNames = map[string]string{
+	"__initZ": "initializer for ", "__vtblZ": "vtable for ", "__ClassZ": "ClassInfo for ",
+	"__InterfaceZ": "Interface for ", "__ModuleInfoZ": "ModuleInfo for ",
+}
+
+// errDSymbol is returned for the symbols which are not mangled by D
+var errDSymbol = errors.New("not a D symbol")
+
+// dDemangler is the state of demangling a D symbol, the back references
+// are to the offsets within the whole of it
+type dDemangler struct {
+	sym   string
+	pos   int
+	depth int
+	opts  *DemangleOptions
+}
+
+// UtilDemangleD will demangle a D symbol the way the D runtime does, as
+// the qualified name with the parameters of the functions along it. The
+// type of the symbol, which is the return type for a function, is left out
+func UtilDemangleD(symbol string, opts *DemangleOptions) (string, error) {
+	if symbol == "_Dmain" {
+		return "D main", nil
+	}
+
+	if !strings.HasPrefix(symbol, "_D") {
+		return "", errDSymbol
+	}
+
+	if opts == nil {
+		opts = &DemangleOptions{}
+	}
+
+	d := &dDemangler{sym: symbol, pos: 2, opts: opts}
+
+	text, special, ok := d.qualified(true)
+	if !ok {
+		return "", errDSymbol
+	}
+
+	// the symbols which the compiler generates end where their type would
+	if special == "" && d.pos < len(d.sym) {
+		if d.sym[d.pos] == 'Z' {
+			d.pos++
+		} else if _, ok := d.typ(); !ok {
+			return "", errDSymbol
+		}
+	}
+
+	if d.pos != len(d.sym) {
+		return "", errDSymbol
+	}
+
+	if opts.SimplifyStd {
+		text = utilSimplifyD(text)
+	}
+
+	return special + text, nil
+}
+
+// utilSimplifyD will write the strings of D by their aliases
+func utilSimplifyD(text string) string {
+	return strings.NewReplacer("immutable(char)[]", "string", "immutable(wchar)[]", "wstring",
+		"immutable(dchar)[]", "dstring").Replace(text)
+}
+
+// peek will return the next byte, zero at the end
+func (d *dDemangler) peek(off int) byte {
+	if d.pos+off < len(d.sym) {
+		return d.sym[d.pos+off]
+	}
+
+	return 0
+}
+
+// number will read a decimal number
+func (d *dDemangler) number() (int, bool) {
+	start := d.pos
+	for d.pos < len(d.sym) && d.sym[d.pos] >= '0' && d.sym[d.pos] <= '9' {
+		d.pos++
+	}
+
+	n, err := strconv.Atoi(d.sym[start:d.pos])
+	if err != nil || n < 0 || n > len(d.sym) {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// backref will read the back reference after a Q, a number in base 26
+// whose last digit is lowercase, and return the offset it refers to
+func (d *dDemangler) backref() (int, bool) {
+	q := d.pos
+	d.pos++
+
+	val := 0
+	for d.pos < len(d.sym) {
+		c := d.sym[d.pos]
+		d.pos++
+
+		switch {
+		case c >= 'a' && c <= 'z':
+			val = val*26 + int(c-'a')
+			if val <= 0 || val > q {
+				return 0, false
+			}
+
+			return q - val, true
+		case c >= 'A' && c <= 'Z':
+			val = val*26 + int(c-'A')
+			if val > len(d.sym) {
+				return 0, false
+			}
+		default:
+			return 0, false
+		}
+	}
+
+	return 0, false
+}
+
+// isSymbolName will check whether a symbol name comes next, a number, a
+// template instance or the back reference to a number
+func (d *dDemangler) isSymbolName() bool {
+	c := d.peek(0)
+	if c >= '0' && c <= '9' || strings.HasPrefix(d.sym[d.pos:], "__T") || strings.HasPrefix(d.sym[d.pos:], "__U") {
+		return true
+	}
+
+	if c != 'Q' {
+		return false
+	}
+
+	pos := d.pos
+	defer func() { d.pos = pos }()
+
+	ref, ok := d.backref()
+
+	return ok && d.sym[ref] >= '0' && d.sym[ref] <= '9'
+}
+
+// qualified will read the names of a qualified name, along with the
+// parameters of the functions that they are nested in. top is set for
+// the name of the symbol itself, whose last function keeps the modifiers
+// of its this. special is what a symbol generated by the compiler is for
+func (d *dDemangler) qualified(top bool) (text string, special string, ok bool) {
+	var names []string
+
+	for {
+		// the anonymous symbols are skipped over
+		for d.peek(0) == '0' {
+			d.pos++
+		}
+
+		name, ok := d.identifier()
+		if !ok {
+			return "", "", false
+		}
+
+		if prefix, isSpecial := dSpecialNames[name]; isSpecial {
+			return strings.Join(names, "."), prefix, true
+		}
+
+		if c := d.peek(0); c == 'M' || dCallConventions[c] != "" || c == 'F' {
+			start := d.pos
+
+			var mods string
+			if c == 'M' {
+				d.pos++
+				mods = d.modifiers()
+			}
+
+			_, _, params, ok := d.function()
+			if !ok || d.pos >= len(d.sym) {
+				d.pos = start
+			} else {
+				if !d.opts.NoParams {
+					name += "(" + params + ")"
+					if top {
+						name += mods
+					}
+				}
+			}
+		}
+
+		names = append(names, name)
+
+		if !d.isSymbolName() {
+			return strings.Join(names, "."), "", true
+		}
+	}
+}
+
+// identifier will read a name, which is a number and as many characters,
+// a template instance or a back reference to a name
+func (d *dDemangler) identifier() (string, bool) {
+	if d.peek(0) == 'Q' {
+		ref, ok := d.backref()
+		if !ok {
+			return "", false
+		}
+
+		pos := d.pos
+		d.pos = ref
+
+		defer func() { d.pos = pos }()
+
+		n, ok := d.number()
+		if !ok || n == 0 || d.pos+n > len(d.sym) {
+			return "", false
+		}
+
+		return d.sym[d.pos : d.pos+n], true
+	}
+
+	if strings.HasPrefix(d.sym[d.pos:], "__T") || strings.HasPrefix(d.sym[d.pos:], "__U") {
+		return d.template(-1)
+	}
+
+	n, ok := d.number()
+	if !ok || n == 0 || d.pos+n > len(d.sym) {
+		return "", false
+	}
+
+	name := d.sym[d.pos : d.pos+n]
+	if n >= 5 && (strings.HasPrefix(name, "__T") || strings.HasPrefix(name, "__U")) {
+		return d.template(n)
+	}
+
+	// the fake parents which keep the names of declarations unique
+	if len(name) >= 4 && strings.HasPrefix(name, "__S") && strings.Trim(name[3:], "0123456789") == "" {
+		d.pos += n
+		return d.identifier()
+	}
+
+	for special := range dSpecialNames {
+		if strings.HasPrefix(d.sym[d.pos:], special) && len(special) == n+1 {
+			d.pos += n + 1
+			return special, true
+		}
+	}
+
+	d.pos += n
+
+	return name, true
+}
+
+// template will read a template instance, its name and then its arguments
+// up to a Z. size is the length which it was prefixed by, -1 for none
+func (d *dDemangler) template(size int) (string, bool) {
+	start := d.pos
+	d.pos += 3
+
+	name, ok := d.identifier()
+	if !ok {
+		return "", false
+	}
+
+	var args []string
+	for {
+		switch d.peek(0) {
+		case 0:
+			return "", false
+		case 'Z':
+			d.pos++
+
+			if size >= 0 && d.pos-start != size {
+				return "", false
+			}
+
+			if d.opts.NoTemplateArgs {
+				return name, true
+			}
+
+			return name + "!(" + strings.Join(args, ", ") + ")", true
+		case 'H':
+			d.pos++
+			continue
+		}
+
+		arg, ok := d.templateArg()
+		if !ok {
+			return "", false
+		}
+
+		args = append(args, arg)
+	}
+}
+
+// templateArg will read an argument of a template instance, which is a
+// type, a value or a symbol
+func (d *dDemangler) templateArg() (string, bool) {
+	c := d.peek(0)
+	d.pos++
+
+	switch c {
+	case 'T':
+		return d.typ()
+	case 'V':
+		kind := d.peek(0)
+		if kind == 'Q' {
+			pos := d.pos
+			ref, ok := d.backref()
+			d.pos = pos
+
+			if !ok {
+				return "", false
+			}
+
+			kind = d.sym[ref]
+		}
+
+		if _, ok := d.typ(); !ok {
+			return "", false
+		}
+
+		return d.value(kind)
+	case 'S':
+		if _, ok := d.number(); !ok && d.peek(0) != 'Q' && d.peek(0) != '_' {
+			return "", false
+		}
+
+		if strings.HasPrefix(d.sym[d.pos:], "_D") {
+			d.pos += 2
+
+			text, _, ok := d.qualified(false)
+			if ok && d.pos < len(d.sym) && d.sym[d.pos] != 'Z' {
+				_, ok = d.typ()
+			}
+
+			return text, ok
+		}
+
+		text, _, ok := d.qualified(false)
+
+		return text, ok
+	case 'X':
+		n, ok := d.number()
+		if !ok || d.pos+n > len(d.sym) {
+			return "", false
+		}
+
+		d.pos += n
+
+		return d.sym[d.pos-n : d.pos], true
+	}
+
+	return "", false
+}
+
+// value will read a value of a template argument of the given type
+func (d *dDemangler) value(kind byte) (string, bool) {
+	c := d.peek(0)
+
+	switch {
+	case c == 'n':
+		d.pos++
+		return "null", true
+	case c == 'N':
+		d.pos++
+		text, ok := d.integer(kind)
+		return "-" + text, ok
+	case c == 'i':
+		d.pos++
+		return d.integer(kind)
+	case c >= '0' && c <= '9':
+		return d.integer(kind)
+	case c == 'a' || c == 'w' || c == 'd':
+		d.pos++
+		return d.stringValue(c)
+	}
+
+	return "", false
+}
+
+// integer will read an integer value, which is written as a character
+// or a boolean for those types, and with the suffix of its type
+func (d *dDemangler) integer(kind byte) (string, bool) {
+	start := d.pos
+	for d.pos < len(d.sym) && d.sym[d.pos] >= '0' && d.sym[d.pos] <= '9' {
+		d.pos++
+	}
+
+	digits := d.sym[start:d.pos]
+	val, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return "", false
+	}
+
+	switch kind {
+	case 'a', 'u', 'w':
+		if kind == 'a' && val >= 0x20 && val < 0x7f {
+			return "'" + string(rune(val)) + "'", true
+		}
+
+		escape := map[byte]string{'a': `\x%02x`, 'u': `\u%04x`, 'w': `\U%08x`}[kind]
+
+		return "'" + fmt.Sprintf(escape, val) + "'", true
+	case 'b':
+		return strconv.FormatBool(val != 0), true
+	case 'h', 't', 'k':
+		return digits + "u", true
+	case 'l':
+		return digits + "L", true
+	case 'm':
+		return digits + "uL", true
+	}
+
+	return digits, true
+}
+
+// stringValue will read a string value, its length in code units and then
+// each of them in hexadecimal
+func (d *dDemangler) stringValue(kind byte) (string, bool) {
+	n, ok := d.number()
+	if !ok || d.peek(0) != '_' || d.pos+1+2*n > len(d.sym) {
+		return "", false
+	}
+
+	d.pos++
+
+	var out strings.Builder
+	out.WriteByte('"')
+
+	for i := 0; i < n; i++ {
+		b, err := strconv.ParseUint(d.sym[d.pos:d.pos+2], 16, 8)
+		if err != nil {
+			return "", false
+		}
+
+		switch c := byte(b); {
+		case c == '\t':
+			out.WriteString(`\t`)
+		case c == '\n':
+			out.WriteString(`\n`)
+		case c == '\r':
+			out.WriteString(`\r`)
+		case c == '\f':
+			out.WriteString(`\f`)
+		case c == '\v':
+			out.WriteString(`\v`)
+		case c >= ' ' && c < 0x7f:
+			out.WriteByte(c)
+		default:
+			out.WriteString(`\x` + d.sym[d.pos:d.pos+2])
+		}
+
+		d.pos += 2
+	}
+
+	out.WriteByte('"')
+	if kind != 'a' {
+		out.WriteByte(kind)
+	}
+
+	return out.String(), true
+}
+
+// modifiers will read the modifiers of the this of a member function
+func (d *dDemangler) modifiers() string {
+	var mods string
+
+	for {
+		switch {
+		case d.peek(0) == 'x':
+			mods += " const"
+		case d.peek(0) == 'y':
+			mods += " immutable"
+		case d.peek(0) == 'O':
+			mods += " shared"
+		case d.peek(0) == 'N' && d.peek(1) == 'g':
+			mods += " inout"
+			d.pos++
+		default:
+			return mods
+		}
+
+		d.pos++
+	}
+}
+
+// function will read a function type up to its return type, which is the
+// calling convention, the attributes and the parameters
+func (d *dDemangler) function() (call string, attrs string, params string, ok bool) {
+	call, isCall := dCallConventions[d.peek(0)]
+	if !isCall {
+		return "", "", "", false
+	}
+
+	d.pos++
+
+	for d.peek(0) == 'N' {
+		attr, isAttr := dFunctionAttrs[d.peek(1)]
+		if !isAttr {
+			break
+		}
+
+		attrs += attr
+		d.pos += 2
+	}
+
+	var list []string
+	for {
+		switch d.peek(0) {
+		case 0:
+			return "", "", "", false
+		case 'X':
+			d.pos++
+			return call, attrs, strings.Join(list, ", ") + "...", true
+		case 'Y':
+			d.pos++
+			return call, attrs, strings.Join(append(list, "..."), ", "), true
+		case 'Z':
+			d.pos++
+			return call, attrs, strings.Join(list, ", "), true
+		}
+
+		param, ok := d.parameter()
+		if !ok {
+			return "", "", "", false
+		}
+
+		list = append(list, param)
+	}
+}
+
+// parameter will read a parameter of a function, its storage classes and
+// then its type
+func (d *dDemangler) parameter() (string, bool) {
+	var storage string
+
+	for {
+		switch c := d.peek(0); {
+		case c == 'I':
+			storage += "in "
+		case c == 'J':
+			storage += "out "
+		case c == 'K':
+			storage += "ref "
+		case c == 'L':
+			storage += "lazy "
+		case c == 'M':
+			storage += "scope "
+		case c == 'N' && d.peek(1) == 'k':
+			storage += "return "
+			d.pos++
+		default:
+			typ, ok := d.typ()
+			return storage + typ, ok
+		}
+
+		d.pos++
+	}
+}
+
+// typ will read a type
+func (d *dDemangler) typ() (string, bool) {
+	d.depth++
+	defer func() { d.depth-- }()
+
+	if d.depth > dMaxDepth || d.pos >= len(d.sym) {
+		return "", false
+	}
+
+	c := d.sym[d.pos]
+	if name, ok := dBasicTypes[c]; ok {
+		d.pos++
+		return name, true
+	}
+
+	if _, ok := dCallConventions[c]; ok {
+		call, attrs, params, ok := d.function()
+		if !ok {
+			return "", false
+		}
+
+		ret, ok := d.typ()
+
+		return call + ret + "(" + params + ") " + attrs + "function", ok
+	}
+
+	d.pos++
+
+	switch c {
+	case 'x', 'y', 'O':
+		inner, ok := d.typ()
+		return map[byte]string{'x': "const", 'y': "immutable", 'O': "shared"}[c] + "(" + inner + ")", ok
+	case 'N':
+		next := d.peek(0)
+		d.pos++
+
+		switch next {
+		case 'g':
+			inner, ok := d.typ()
+			return "inout(" + inner + ")", ok
+		case 'h':
+			inner, ok := d.typ()
+			return "__vector(" + inner + ")", ok
+		case 'n':
+			return "typeof(*null)", true
+		}
+	case 'A':
+		inner, ok := d.typ()
+		return inner + "[]", ok
+	case 'G':
+		n, ok := d.number()
+		if !ok {
+			return "", false
+		}
+
+		inner, ok := d.typ()
+
+		return inner + "[" + strconv.Itoa(n) + "]", ok
+	case 'H':
+		key, ok := d.typ()
+		if !ok {
+			return "", false
+		}
+
+		val, ok := d.typ()
+
+		return val + "[" + key + "]", ok
+	case 'P':
+		// the pointers to functions are the function types themselves
+		if _, ok := dCallConventions[d.peek(0)]; ok {
+			return d.typ()
+		}
+
+		inner, ok := d.typ()
+
+		return inner + "*", ok
+	case 'D':
+		mods := d.modifiers()
+
+		call, attrs, params, ok := d.function()
+		if !ok {
+			return "", false
+		}
+
+		ret, ok := d.typ()
+
+		return call + ret + "(" + params + ")" + mods + " " + attrs + "delegate", ok
+	case 'I', 'C', 'S', 'E', 'T':
+		text, _, ok := d.qualified(false)
+		return text, ok
+	case 'B':
+		n, ok := d.number()
+		if !ok {
+			return "", false
+		}
+
+		var elems []string
+		for i := 0; i < n; i++ {
+			param, ok := d.parameter()
+			if !ok {
+				return "", false
+			}
+
+			elems = append(elems, param)
+		}
+
+		return "Tuple!(" + strings.Join(elems, ", ") + ")", true
+	case 'Q':
+		d.pos--
+
+		ref, ok := d.backref()
+		if !ok || ref < 2 {
+			return "", false
+		}
+
+		pos := d.pos
+		d.pos = ref
+		text, ok := d.typ()
+		d.pos = pos
+
+		return text, ok
+	case 'z':
+		next := d.peek(0)
+		d.pos++
+
+		switch next {
+		case 'i':
+			return "cent", true
+		case 'k':
+			return "ucent", true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,71 @@
+package elfstrings
+
+import "testing"
+
+// The expected names are those that c++filt gives with -s dlang
+func TestDemangleD(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   string
+	}{
+		{"_Dmain", "D main"},
+		{"_D8demangle4testFZv", "demangle.test()"},
+		{"_D8demangle4testFaZv", "demangle.test(char)"},
+		{"_D8demangle4testFiZi", "demangle.test(int)"},
+		{"_D8demangle4testFAyaZv", "demangle.test(immutable(char)[])"},
+		{"_D8demangle4testFPiZv", "demangle.test(int*)"},
+		{"_D8demangle4testFHiiZv", "demangle.test(int[int])"},
+		{"_D8demangle4testFG4iZv", "demangle.test(int[4])"},
+		{"_D8demangle4testFAAaZv", "demangle.test(char[][])"},
+		{"_D8demangle4testFxiZv", "demangle.test(const(int))"},
+		{"_D8demangle4testFOiZv", "demangle.test(shared(int))"},
+		{"_D8demangle4testFNgiZv", "demangle.test(inout(int))"},
+		{"_D8demangle4testFiiZv", "demangle.test(int, int)"},
+		{"_D8demangle4testFiXv", "demangle.test(int...)"},
+		{"_D8demangle4testFiYv", "demangle.test(int, ...)"},
+		{"_D8demangle4testFkmZv", "demangle.test(uint, ulong)"},
+		{"_D8demangle4testFC6ObjectZv", "demangle.test(Object)"},
+		{"_D8demangle4testFS8demangle4testZv", "demangle.test(demangle.test)"},
+		{"_D8demangle4testFDFiZvZv", "demangle.test(void(int) delegate)"},
+		{"_D8demangle4testFPFiZvZv", "demangle.test(void(int) function)"},
+		{"_D8demangle__T4testTiZ4testFiZv", "demangle.test!(int).test(int)"},
+		{"_D8demangle__T4testVii123Z4testFZv", "demangle.test!(123).test()"},
+		{"_D8demangle4test6__initZ", "initializer for demangle.test"},
+		{"_D8demangle4test6__vtblZ", "vtable for demangle.test"},
+		{"_D8demangle4test7__ClassZ", "ClassInfo for demangle.test"},
+		{"_D8demangle4testFMiZv", "demangle.test(scope int)"},
+		{"_D8demangle4testFKiZv", "demangle.test(ref int)"},
+		{"_D8demangle4testFJiZv", "demangle.test(out int)"},
+		{"_D8demangle4testFLiZv", "demangle.test(lazy int)"},
+		{"_D8demangle4testFNkiZv", "demangle.test(return int)"},
+		{"_D3std6format__T6formatTaTiZ6formatFNaNfxAaiZAya", "std.format.format!(char, int).format(const(char[]), int)"},
+		{"_D4core6thread6Thread5startMFZC4core6thread6Thread", "core.thread.Thread.start()"},
+		{"_D8demangle4testQfFZv", "demangle.test.test()"},
+	}
+
+	for _, tt := range tests {
+		got, err := UtilDemangleD(tt.symbol, nil)
+		if err != nil {
+			t.Errorf("UtilDemangleD(%q) failed: %v", tt.symbol, err)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("UtilDemangleD(%q) = %q, want %q", tt.symbol, got, tt.want)
+		}
+	}
+}
+
+func TestDemangleDMalformed(t *testing.T) {
+	for _, symbol := range []string{
+		"_D3std5stdio6writeFNbNiNfZv",
+		"_D8demangle4testFi",
+		"_D8demangle4testQzFZv",
+		"_D99demangle",
+		"_D",
+	} {
+		if got, err := UtilDemangleD(symbol, nil); err == nil {
+			t.Errorf("UtilDemangleD(%q) = %q, want an error", symbol, got)
+		}
+	}
+}
@@ -0,0 +1,645 @@
+package elfstrings
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ianlancetaylor/demangle"
+)
+
+// Language to emulate an enum of the languages whose symbols are demangled
+type Language int32
+
+// Languages of the mangling schemes which are detected
+const (
+	// LanguageNone is a symbol which is not mangled, or not known to be
+	LanguageNone Language = iota
+	// LanguageCPP is a symbol in the Itanium C++ mangling
+	LanguageCPP
+	// LanguageRust is a symbol in the legacy or v0 Rust mangling
+	LanguageRust
+	// LanguageSwift is a symbol in the mangling of Swift 4.2 and later
+	LanguageSwift
+	// LanguageD is a symbol in the D mangling
+	LanguageD
+	// LanguageJava is a symbol of Java compiled by GCJ, which is in the
+	// Itanium mangling with the types of Java
+	LanguageJava
+	// LanguageGo is a symbol in the naming of the Go linker
+	LanguageGo
+	// LanguageOCaml is a symbol in the OCaml mangling
+	LanguageOCaml
+	languageEnd
+)
+
+var languageNames = map[Language]string{
+	LanguageNone:  "none",
+	LanguageCPP:   "c++",
+	LanguageRust:  "rust",
+	LanguageSwift: "swift",
+	LanguageD:     "d",
+	LanguageJava:  "java",
+	LanguageGo:    "go",
+	LanguageOCaml: "ocaml",
+}
+
+// String will return the name of the language
+func (l Language) String() string {
+	if name, ok := languageNames[l]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("language(%d)", int32(l))
+}
+
+// DemangleOptions controls how much of a demangled symbol is shown
+type DemangleOptions struct {
+	// NoParams leaves out the parameters of functions, along with the
+	// types that they return
+	NoParams bool
+	// NoTemplateArgs leaves out the arguments of templates and generics
+	NoTemplateArgs bool
+	// SimplifyStd spells the types of the standard libraries the way
+	// that they are written in the source, std::string rather than
+	// std::basic_string<char, std::char_traits<char>, std::allocator<char> >
+	// and without the default arguments of the containers
+	SimplifyStd bool
+	// GoNames also demangles the names of the Go linker, which read much
+	// like any other text, such as main.main or os.Exit, so it is only
+	// set for the binaries that were built by Go
+	GoNames bool
+}
+
+// errMangled is returned for the symbols which are not mangled in any of
+// the schemes that are known
+var errMangled = errors.New("not a mangled symbol")
+
+var (
+	// tokenStartRegex matches where a mangled symbol starts within a
+	// longer string, after anything that cannot be a part of it
+	tokenStartRegex = regexp.MustCompile(`(?:^|[^\w$.])(_{1,2}Z[A-Z0-9]|_R[A-Z]|_?\$[sSe][0-9A-Za-z]|_D\d|_Dmain|caml[A-Z]|N\d+[A-Za-z_])`)
+	// tokenRegex matches the characters of a mangled symbol
+	tokenRegex = regexp.MustCompile(`^[\w$.]+`)
+	// typeinfoNameRegex matches the names of the nested classes that the
+	// typeinfo of C++ holds, which are mangled without the _Z
+	typeinfoNameRegex = regexp.MustCompile(`^N(\d+[A-Za-z_]\w*?)+E$`)
+	// ocamlRegex matches the symbols of OCaml, the name of the compilation
+	// unit after caml and then the names within it
+	ocamlRegex = regexp.MustCompile(`^caml[A-Z][A-Za-z0-9_']*(__|\$|\.)[A-Za-z_$]`)
+	// ocamlOperatorChars are the characters of the operators of OCaml,
+	// which are escaped in its symbols
+	ocamlOperatorChars = "!$%&*+-./:<=>?@^|~"
+	// ocamlStampRegex matches the stamp which tells apart the names of
+	// OCaml which are the same
+	ocamlStampRegex = regexp.MustCompile(`_\d+(_code)?$`)
+	// rustHashRegex matches the end of a legacy Rust symbol, its hash as
+	// the last of the names and any suffix that LLVM added after it
+	rustHashRegex = regexp.MustCompile(`17h[0-9a-f]{16}E(\.[\w.$]*)?$`)
+	// goRegexes match the names of the Go linker, which are those of
+	// the packages and the functions, methods and types within them
+	goRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`^(go|type)[:.]\S|^gclocals·`),
+		regexp.MustCompile(`^[\w.~%-]+(/[\w.~%-]+)+\.[\w(*)\[\]·.,{}%-]*[A-Za-z_(]`),
+		regexp.MustCompile(`^[a-z][a-z0-9_]*\.(\(\*?[A-Za-z_]\w*(\[[^\]]*\])?\)\.[A-Za-z_]|[A-Za-z_]\w*\.func\d|[A-Za-z_]\w*\[)`),
+		regexp.MustCompile(`^(runtime|main|internal|sync|syscall|reflect|os|fmt|net|strings|strconv|unicode|errors|io|time|sort|bytes|math|crypto|encoding)\.[A-Za-z_]`),
+	}
+	// goSourceRegex matches the names of source files, which the symbols
+	// of the C toolchain look like the names of the Go linker by
+	goSourceRegex = regexp.MustCompile(`\.(c|h|cc|cpp|cxx|S|s|o|go|rs|asm)$`)
+	// cxxInlineNamespaces are the inline namespaces of the C++ standard
+	// libraries, which the types are written without
+	cxxInlineNamespaces = map[string]bool{"std::__cxx11": true, "std::__1": true, "std::__ndk1": true}
+	// cxxDefaultArgs are the default arguments of the templates of the C++
+	// standard library, which are left out when they come last
+	cxxDefaultArgs = []string{"std::allocator", "std::char_traits", "std::less", "std::equal_to", "std::hash", "std::default_delete"}
+	// cxxStringTypes are the names of the strings for each character type
+	cxxStringTypes = map[string]string{"char": "", "wchar_t": "w", "char8_t": "u8", "char16_t": "u16", "char32_t": "u32"}
+	// cxxStreamTypes are the streams of the C++ standard library, which
+	// are templates on the type of their characters
+	cxxStreamTypes = []string{"ios", "istream", "ostream", "iostream", "ifstream", "ofstream", "fstream",
+		"istringstream", "ostringstream", "stringstream", "streambuf", "stringbuf", "filebuf"}
+	// rustPrelude are the paths of the types of the Rust standard library
+	// which are in its prelude, and so are written without them
+	rustPrelude = strings.NewReplacer(
+		"alloc::string::String", "String", "alloc::vec::Vec", "Vec", "alloc::boxed::Box", "Box",
+		"core::option::Option", "Option", "core::result::Result", "Result", "alloc::sync::Arc", "Arc",
+		"alloc::rc::Rc", "Rc", "alloc::borrow::Cow", "Cow", "std::collections::hash::map::HashMap", "HashMap",
+	)
+)
+
+// UtilDetectLanguage will detect the scheme that the symbol was mangled in
+// from its prefix, LanguageNone is returned when it is in none of them.
+// The names of the Go linker are only detected when goNames is set, as
+// they are written much as any other text is
+func UtilDetectLanguage(symbol string, goNames bool) Language {
+	switch {
+	case utilIsRustSymbol(symbol):
+		return LanguageRust
+	case strings.HasPrefix(symbol, "_Z") || strings.HasPrefix(symbol, "__Z") || strings.HasPrefix(symbol, "_GLOBAL_"):
+		if utilIsJavaSymbol(symbol) {
+			return LanguageJava
+		}
+
+		return LanguageCPP
+	case utilIsSwiftSymbol(symbol):
+		return LanguageSwift
+	case symbol == "_Dmain" || len(symbol) > 2 && strings.HasPrefix(symbol, "_D") && symbol[2] >= '0' && symbol[2] <= '9':
+		return LanguageD
+	case ocamlRegex.MatchString(symbol):
+		return LanguageOCaml
+	case goNames && utilIsGoSymbol(symbol):
+		return LanguageGo
+	}
+
+	return LanguageNone
+}
+
+// UtilDemangleSymbol will detect the scheme that the symbol was mangled in
+// and demangle it with the matching demangler, returning the language it
+// was mangled by along with it. opts may be nil for the default options
+func UtilDemangleSymbol(symbol string, opts *DemangleOptions) (string, Language, error) {
+	if opts == nil {
+		opts = &DemangleOptions{}
+	}
+
+	lang := UtilDetectLanguage(symbol, opts.GoNames)
+
+	var text string
+	var err error
+
+	switch lang {
+	case LanguageRust:
+		text, err = UtilDemangleRust(symbol)
+
+		// a C++ name may end with what only looks like the hash
+		if err != nil && !strings.HasPrefix(symbol, "_R") && !strings.HasPrefix(symbol, "__R") {
+			lang = LanguageCPP
+			text, err = utilDemangleItanium(symbol, false, opts)
+			break
+		}
+
+		if err == nil && opts.NoTemplateArgs {
+			text = utilStripGenerics(text, '<', '>')
+		}
+
+		if err == nil && opts.SimplifyStd {
+			text = rustPrelude.Replace(text)
+		}
+	case LanguageCPP, LanguageJava:
+		text, err = utilDemangleItanium(symbol, lang == LanguageJava, opts)
+	case LanguageSwift:
+		text, err = UtilDemangleSwift(symbol, opts)
+	case LanguageD:
+		text, err = UtilDemangleD(symbol, opts)
+	case LanguageOCaml:
+		text, err = utilDemangleOCaml(symbol)
+	case LanguageGo:
+		text, err = utilDemangleGo(symbol, opts)
+	default:
+		err = errMangled
+	}
+
+	if err != nil {
+		return "", LanguageNone, err
+	}
+
+	return text, lang, nil
+}
+
+// utilIsRustSymbol will check whether the symbol is in the v0 mangling of
+// Rust, or in the legacy mangling which ends with the hash of the symbol
+func utilIsRustSymbol(symbol string) bool {
+	for _, prefix := range []string{"_R", "__R"} {
+		if strings.HasPrefix(symbol, prefix) && len(symbol) > len(prefix) {
+			return symbol[len(prefix)] >= 'A' && symbol[len(prefix)] <= 'Z'
+		}
+	}
+
+	if !strings.HasPrefix(symbol, "_ZN") && !strings.HasPrefix(symbol, "__ZN") {
+		return false
+	}
+
+	// the hash is the last of the names, as h and 16 hexadecimal digits,
+	// which only a suffix of LLVM such as .llvm.1234 may follow. The
+	// names before it may hold dots, as :: is escaped as ..
+	return rustHashRegex.MatchString(symbol)
+}
+
+// utilIsJavaSymbol will check whether the Itanium symbol is of Java, from
+// the packages of the Java runtime, its arrays, or the return type that
+// follows the names of its methods where C++ has the parameters
+func utilIsJavaSymbol(symbol string) bool {
+	for _, root := range []string{"_ZN4java", "_ZN5javax", "_ZN3gnu3gcj", "_ZN3gnu4java"} {
+		if strings.HasPrefix(symbol, root) {
+			return true
+		}
+	}
+
+	if strings.Contains(symbol, "6JArray") {
+		return true
+	}
+
+	if !strings.HasPrefix(symbol, "_ZN") {
+		return false
+	}
+
+	sym := symbol[3:]
+	for len(sym) != 0 && sym[0] >= '0' && sym[0] <= '9' {
+		n := 0
+		for n < len(sym) && sym[n] >= '0' && sym[n] <= '9' {
+			n++
+		}
+
+		size, err := strconv.Atoi(sym[:n])
+		if err != nil || n+size > len(sym) {
+			return false
+		}
+
+		sym = sym[n+size:]
+	}
+
+	return strings.HasPrefix(sym, "EJ")
+}
+
+// utilIsGoSymbol will check whether the symbol is a name of the Go linker,
+// which unlike the other schemes is written much as it is in the source
+func utilIsGoSymbol(symbol string) bool {
+	if strings.ContainsAny(symbol, " @\t") || goSourceRegex.MatchString(symbol) {
+		return false
+	}
+
+	for _, re := range goRegexes {
+		if re.MatchString(symbol) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// utilDemangleItanium will demangle the Itanium symbol as C++, or as Java
+// when it was compiled by GCJ
+func utilDemangleItanium(symbol string, java bool, opts *DemangleOptions) (string, error) {
+	var options []demangle.Option
+	if opts.NoParams {
+		options = append(options, demangle.NoParams)
+	}
+
+	if opts.NoTemplateArgs {
+		options = append(options, demangle.NoTemplateParams)
+	}
+
+	ast, err := demangle.ToAST(strings.TrimPrefix(symbol, "_"), options...)
+	if err != nil && strings.HasPrefix(symbol, "__Z") {
+		return "", err
+	} else if err != nil {
+		ast, err = demangle.ToAST(symbol, options...)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if java {
+		return utilJavaString(ast, opts), nil
+	}
+
+	if opts.SimplifyStd {
+		if simple := ast.Copy(utilSimplifyCxx, func(demangle.AST) bool { return false }); simple != nil {
+			ast = simple
+		}
+	}
+
+	return demangle.ASTToString(ast, options...), nil
+}
+
+// utilCxxName will return the qualified name that the node names, such as
+// std::vector, empty when it is not a name
+func utilCxxName(a demangle.AST) string {
+	switch n := a.(type) {
+	case *demangle.Name:
+		return n.Name
+	case *demangle.Qualified:
+		scope, name := utilCxxName(n.Scope), utilCxxName(n.Name)
+		if scope != "" && name != "" {
+			return scope + "::" + name
+		}
+	}
+
+	return ""
+}
+
+// utilSimplifyCxx will rewrite the node of a C++ symbol the way it is
+// written in the source, nil is returned when it is left as it is. The
+// nodes are rewritten from the leaves up, so the arguments of a template
+// are simple by the time the template is
+func utilSimplifyCxx(a demangle.AST) demangle.AST {
+	switch n := a.(type) {
+	case *demangle.Qualified:
+		if cxxInlineNamespaces[utilCxxName(n.Scope)] {
+			return &demangle.Qualified{Scope: &demangle.Name{Name: "std"}, Name: n.Name, LocalName: n.LocalName}
+		}
+	case *demangle.Template:
+		name := strings.Replace(utilCxxName(n.Name), "::__cxx11", "", 1)
+		if !strings.HasPrefix(name, "std::") || len(n.Args) == 0 {
+			return nil
+		}
+
+		char := demangle.ASTToString(n.Args[0])
+		traits := "std::char_traits<" + char + ">"
+
+		if prefix, ok := cxxStringTypes[char]; ok && name == "std::basic_string" {
+			if len(n.Args) == 3 && demangle.ASTToString(n.Args[1]) == traits &&
+				demangle.ASTToString(n.Args[2]) == "std::allocator<"+char+">" {
+				return &demangle.Name{Name: "std::" + prefix + "string"}
+			}
+		}
+
+		for _, stream := range cxxStreamTypes {
+			if name != "std::basic_"+stream || len(n.Args) > 3 || (char != "char" && char != "wchar_t") {
+				continue
+			}
+
+			if len(n.Args) == 1 || demangle.ASTToString(n.Args[1]) == traits {
+				return &demangle.Name{Name: "std::" + cxxStringTypes[char] + stream}
+			}
+		}
+
+		args := n.Args
+		for len(args) > 1 && utilIsDefaultArg(args[len(args)-1]) {
+			args = args[:len(args)-1]
+		}
+
+		if len(args) != len(n.Args) {
+			return &demangle.Template{Name: n.Name, Args: args}
+		}
+	}
+
+	return nil
+}
+
+// utilIsDefaultArg will check whether the argument of a template of the
+// C++ standard library is one of the defaults that its containers have
+func utilIsDefaultArg(a demangle.AST) bool {
+	tmpl, ok := a.(*demangle.Template)
+	if !ok {
+		return false
+	}
+
+	name := utilCxxName(tmpl.Name)
+	for _, arg := range cxxDefaultArgs {
+		if name == arg {
+			return true
+		}
+	}
+
+	return false
+}
+
+// javaTypes are the names of the Java types that GCJ mangles as the C++
+// types of the same size
+var javaTypes = map[string]string{
+	"bool": "boolean", "wchar_t": "char", "char": "byte", "long long": "long",
+}
+
+// utilJavaString will print the symbol of GCJ the way that Java names it,
+// with dots between the names, the arrays written with brackets and the
+// type that a method returns after its parameters
+func utilJavaString(a demangle.AST, opts *DemangleOptions) string {
+	switch n := a.(type) {
+	case *demangle.Typed:
+		text := utilJavaString(n.Name, opts)
+		if fn, ok := n.Type.(*demangle.FunctionType); ok && !opts.NoParams {
+			var args []string
+			for _, arg := range fn.Args {
+				args = append(args, utilJavaString(arg, opts))
+			}
+
+			text += "(" + strings.Join(args, ", ") + ")"
+			if fn.Return != nil {
+				text += utilJavaString(fn.Return, opts)
+			}
+		}
+
+		return text
+	case *demangle.Qualified:
+		text := utilJavaString(n.Scope, opts) + "." + utilJavaString(n.Name, opts)
+		if opts.SimplifyStd {
+			text = strings.TrimPrefix(text, "java.lang.")
+		}
+
+		return text
+	case *demangle.Constructor:
+		return utilJavaString(n.Name, opts)
+	case *demangle.PointerType:
+		return utilJavaString(n.Base, opts)
+	case *demangle.Template:
+		if utilCxxName(n.Name) == "JArray" && len(n.Args) == 1 {
+			return utilJavaString(n.Args[0], opts) + "[]"
+		}
+	case *demangle.BuiltinType:
+		if name, ok := javaTypes[n.Name]; ok {
+			return name
+		}
+
+		return n.Name
+	}
+
+	return demangle.ASTToString(a)
+}
+
+// utilStripGenerics will remove the generic arguments from the demangled
+// text, those which are between open and close just after a name. The
+// brackets which do not follow a name, such as those around the qualified
+// paths of Rust, are kept
+func utilStripGenerics(text string, open, close byte) string {
+	var out strings.Builder
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		prev := byte(0)
+		if i > 0 {
+			prev = text[i-1]
+		}
+
+		isName := prev == '_' || prev == ':' || prev >= '0' && prev <= '9' || prev >= 'A' && prev <= 'Z' || prev >= 'a' && prev <= 'z'
+		if c != open || !isName {
+			out.WriteByte(c)
+			continue
+		}
+
+		depth := 0
+		for ; i < len(text); i++ {
+			if text[i] == open {
+				depth++
+			} else if text[i] == close {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+	}
+
+	return strings.Replace(out.String(), "::::", "::", -1)
+}
+
+// utilDemangleOCaml will demangle an OCaml symbol, the name of its
+// compilation unit and the names within it are joined by dots, and the
+// characters which were escaped as $ and their hexadecimal are restored
+func utilDemangleOCaml(symbol string) (string, error) {
+	sym := ocamlStampRegex.ReplaceAllString(strings.TrimPrefix(symbol, "caml"), "")
+
+	var out strings.Builder
+	for i := 0; i < len(sym); i++ {
+		switch {
+		case strings.HasPrefix(sym[i:], "__"):
+			out.WriteByte('.')
+			i++
+		case sym[i] == '$':
+			// only the punctuation of operators is escaped, the $ of the
+			// older compilers separates the names
+			b, err := strconv.ParseUint(sym[i+1:utilMin(i+3, len(sym))], 16, 8)
+			if err == nil && i+3 <= len(sym) && strings.ContainsRune(ocamlOperatorChars, rune(b)) {
+				out.WriteByte(byte(b))
+				i += 2
+			} else {
+				out.WriteByte('.')
+			}
+		default:
+			out.WriteByte(sym[i])
+		}
+	}
+
+	return out.String(), nil
+}
+
+// utilDemangleGo will demangle a name of the Go linker, where the dots of
+// the import paths were escaped as %2e along with the characters which are
+// not printable, and the oldest toolchains separated the names with ·
+func utilDemangleGo(symbol string, opts *DemangleOptions) (string, error) {
+	var out strings.Builder
+
+	for i := 0; i < len(symbol); i++ {
+		if symbol[i] == '%' && i+3 <= len(symbol) {
+			if b, err := strconv.ParseUint(symbol[i+1:i+3], 16, 8); err == nil {
+				out.WriteByte(byte(b))
+				i += 2
+				continue
+			}
+		}
+
+		out.WriteByte(symbol[i])
+	}
+
+	text := strings.Replace(out.String(), "·", ".", -1)
+	if opts.NoTemplateArgs {
+		text = utilStripGenerics(text, '[', ']')
+	}
+
+	return text, nil
+}
+
+// utilMin will return the lesser of the two
+func utilMin(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// MangledToken is a mangled symbol that was found within a longer string
+// and demangled
+type MangledToken struct {
+	// Offset is the offset of the symbol within the string
+	Offset    int
+	Mangled   string
+	Demangled string
+	Language  Language
+}
+
+// UtilDemangleTokens will find the mangled symbols within the text, such
+// as those that log and assert messages name, and replace each of them
+// with its demangled form. The symbols are returned along with the text,
+// nil when none were found. The names of Go are not looked for, as they
+// read the same as the text around them. opts may be nil for the default
+// options
+func UtilDemangleTokens(text string, opts *DemangleOptions) (string, []MangledToken) {
+	var tokens []MangledToken
+	var out strings.Builder
+
+	if opts == nil {
+		opts = &DemangleOptions{}
+	}
+
+	last := 0
+	for _, m := range tokenStartRegex.FindAllStringSubmatchIndex(text, -1) {
+		start := m[2]
+		if start < last {
+			continue
+		}
+
+		tok := tokenRegex.FindString(text[start:])
+
+		// the punctuation which ends a sentence is left out when the
+		// symbol does not demangle with it
+		demangled, lang, err := utilDemangleToken(tok, opts)
+		for err != nil && strings.ContainsAny(tok[len(tok)-1:], ".$") && len(tok) > 1 {
+			tok = tok[:len(tok)-1]
+			demangled, lang, err = utilDemangleToken(tok, opts)
+		}
+
+		if err != nil || demangled == tok {
+			continue
+		}
+
+		tokens = append(tokens, MangledToken{Offset: start, Mangled: tok, Demangled: demangled, Language: lang})
+
+		out.WriteString(text[last:start])
+		out.WriteString(demangled)
+		last = start + len(tok)
+	}
+
+	if tokens == nil {
+		return text, nil
+	}
+
+	out.WriteString(text[last:])
+
+	return out.String(), tokens
+}
+
+// utilDemangleToken will demangle a symbol found within a longer string,
+// the names of the nested classes in typeinfo are demangled as types
+func utilDemangleToken(tok string, opts *DemangleOptions) (string, Language, error) {
+	if typeinfoNameRegex.MatchString(tok) {
+		text, err := utilDemangleType(tok, opts)
+		if err != nil {
+			return "", LanguageNone, err
+		}
+
+		return text, LanguageCPP, nil
+	}
+
+	text, lang, err := UtilDemangleSymbol(tok, opts)
+	if err == nil && lang == LanguageGo {
+		return "", LanguageNone, errMangled
+	}
+
+	return text, lang, err
+}
+
+// utilDemangleType will demangle the C++ type as it is mangled in the name
+// that its typeinfo holds, without the _Z that symbols start with
+func utilDemangleType(mangled string, opts *DemangleOptions) (string, error) {
+	text, err := utilDemangleItanium("_ZTS"+mangled, false, opts)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(text, "typeinfo name for "), nil
+}
@@ -0,0 +1,66 @@
+package elfstrings
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		symbol  string
+		goNames bool
+		want    Language
+	}{
+		{"_ZN3foo3barEv", false, LanguageCPP},
+		{"_ZN4java4lang6Object8toStringEJPNS0_6StringEv", false, LanguageJava},
+		{"_RNvCs8Gv9BFMk9cN_1m4main", false, LanguageRust},
+		{"$s4main3FooVMn", false, LanguageSwift},
+		{"_D8demangle4testFZv", false, LanguageD},
+		{"camlStdlib__list__map_123", false, LanguageOCaml},
+		{"main.main", true, LanguageGo},
+		{"github.com/foo/bar.(*Baz).Qux", true, LanguageGo},
+		{"main.main", false, LanguageNone},
+		{"os.Exit", false, LanguageNone},
+		{"usr/lib/libc.so", false, LanguageNone},
+		{"hello world", true, LanguageNone},
+	}
+
+	for _, tt := range tests {
+		if got := UtilDetectLanguage(tt.symbol, tt.goNames); got != tt.want {
+			t.Errorf("UtilDetectLanguage(%q, %v) = %s, want %s", tt.symbol, tt.goNames, got, tt.want)
+		}
+	}
+}
+
+func TestFilterStringDemangle(t *testing.T) {
+	tests := []struct {
+		text    string
+		goNames bool
+		want    string
+		lang    Language
+	}{
+		{"_ZN3foo3barEv", false, "foo::bar()", LanguageCPP},
+		{"usr/lib/libc.so", false, "usr/lib/libc.so", LanguageNone},
+		{"etc/ssl/certs.pem", false, "etc/ssl/certs.pem", LanguageNone},
+		{"main.main", false, "main.main", LanguageNone},
+		// a Go name which demangles to itself is left without a language
+		{"main.main", true, "main.main", LanguageNone},
+		{"github.com/foo%2ebar/baz.Qux", true, "github.com/foo.bar/baz.Qux", LanguageGo},
+		{"called _ZN3foo3barEv here", false, "called foo::bar() here", LanguageCPP},
+	}
+
+	for _, tt := range tests {
+		opts := &Options{Demangle: true, Demangling: DemangleOptions{GoNames: tt.goNames}}
+
+		f := UtilFilterString([]byte(tt.text), opts)
+		if f == nil {
+			t.Errorf("UtilFilterString(%q) was filtered out", tt.text)
+			continue
+		}
+
+		if f.Text != tt.want || f.Language != tt.lang {
+			t.Errorf("UtilFilterString(%q) = %q %s, want %q %s", tt.text, f.Text, f.Language, tt.want, tt.lang)
+		}
+
+		if tt.lang == LanguageNone && f.Mangled != nil {
+			t.Errorf("UtilFilterString(%q) has mangled symbols %v", tt.text, f.Mangled)
+		}
+	}
+}
@@ -0,0 +1,365 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"strings"
+)
+
+// The pointer encodings of the exception frames, the low bits are the
+// format of the value and the high bits what it is relative to
+const (
+	ehPtrAbs     = 0x00
+	ehPtrUleb128 = 0x01
+	ehPtrUdata2  = 0x02
+	ehPtrUdata4  = 0x03
+	ehPtrUdata8  = 0x04
+	ehPtrSleb128 = 0x09
+	ehPtrSdata2  = 0x0a
+	ehPtrSdata4  = 0x0b
+	ehPtrSdata8  = 0x0c
+	ehPtrPCRel   = 0x10
+	ehPtrDataRel = 0x30
+	ehPtrOmit    = 0xff
+)
+
+// ehFrame reads the fields of the exception frames, buf is mapped at
+// addr so that the pc relative pointers can be resolved
+type ehFrame struct {
+	buf   []byte
+	addr  uint64
+	off   int
+	order binary.ByteOrder
+	// wide is set for 64 bit binaries, where absolute pointers are 8 bytes
+	wide bool
+	// base is what the data relative pointers are relative to
+	base uint64
+	// bad is set once a field runs past the end of the buffer
+	bad bool
+}
+
+// readerEHFrameFunctions will recover the functions of the binary from the
+// frame description entries in .eh_frame, which the unwinder needs and so
+// are kept even when the symbols are stripped. The section is found from
+// the PT_GNU_EH_FRAME segment when there are no section headers
+func (r *ElfReader) readerEHFrameFunctions() []function {
+	if r.ExecReader.Type == elf.ET_REL {
+		return nil
+	}
+
+	frame := r.readerEHFrame()
+	if frame == nil {
+		return nil
+	}
+
+	var funcs []function
+
+	// the pointer encoding of the entries is set by their CIE
+	encodings := make(map[int]byte)
+
+	for !frame.bad && frame.off+4 <= len(frame.buf) {
+		start := frame.off
+
+		length := uint64(frame.u32())
+		if length == 0 {
+			break
+		}
+
+		idSize := 4
+		if length == 0xffffffff {
+			length = frame.u64()
+			idSize = 8
+		}
+
+		end := frame.off + int(length)
+		if length > uint64(len(frame.buf)) || end > len(frame.buf) {
+			break
+		}
+
+		idOff := frame.off
+
+		var id uint64
+		if idSize == 8 {
+			id = frame.u64()
+		} else {
+			id = uint64(frame.u32())
+		}
+
+		if id == 0 {
+			encodings[start] = frame.cie(end)
+		} else if cie := idOff - int(id); cie >= 0 {
+			enc, ok := encodings[cie]
+			if !ok {
+				// the CIE comes after its entries, which is unusual
+				// but allowed, so it is read out of order
+				next := frame.off
+				frame.off = cie + 4 + idSize
+
+				enc = frame.cie(len(frame.buf))
+				encodings[cie] = enc
+				frame.off = next
+			}
+
+			begin, okBegin := frame.ptr(enc)
+			size, okSize := frame.ptr(enc & 0x0f)
+			if okBegin && okSize && begin != 0 && size != 0 {
+				funcs = append(funcs, function{addr: begin, size: size})
+			}
+		}
+
+		frame.bad = false
+		frame.off = end
+	}
+
+	return funcs
+}
+
+// readerEHFrame will find the contents and address of .eh_frame
+func (r *ElfReader) readerEHFrame() *ehFrame {
+	frame := &ehFrame{
+		order: r.ExecReader.ByteOrder,
+		wide:  r.ExecReader.Class == elf.ELFCLASS64,
+	}
+
+	if s := r.ExecReader.Section(".eh_frame"); s != nil && s.Type != elf.SHT_NOBITS {
+		buf, err := r.ReaderReadAt(s.Offset, s.Size)
+		if err != nil {
+			return nil
+		}
+
+		frame.buf, frame.addr = buf, s.Addr
+		if hdr := r.ExecReader.Section(".eh_frame_hdr"); hdr != nil {
+			frame.base = hdr.Addr
+		}
+
+		return frame
+	}
+
+	// the header holds a pointer to .eh_frame after its version and the
+	// encodings of its fields, which runs up to the end of the segment
+	for _, prog := range r.ExecReader.Progs {
+		if prog.Type != elf.PT_GNU_EH_FRAME || prog.Filesz < 8 {
+			continue
+		}
+
+		hdr, err := r.ReaderReadAt(prog.Off, prog.Filesz)
+		if err != nil || hdr[0] != 1 {
+			return nil
+		}
+
+		header := &ehFrame{buf: hdr, addr: prog.Vaddr, off: 4, order: frame.order, wide: frame.wide, base: prog.Vaddr}
+		addr, ok := header.ptr(hdr[1])
+		if !ok {
+			return nil
+		}
+
+		for _, load := range r.ExecReader.Progs {
+			if load.Type != elf.PT_LOAD || addr < load.Vaddr || addr >= load.Vaddr+load.Filesz {
+				continue
+			}
+
+			buf, err := r.ReaderReadAt(load.Off+addr-load.Vaddr, load.Vaddr+load.Filesz-addr)
+			if err != nil {
+				return nil
+			}
+
+			frame.buf, frame.addr, frame.base = buf, addr, prog.Vaddr
+			return frame
+		}
+	}
+
+	return nil
+}
+
+// cie will read the common information entry at the current offset, just
+// after its id, returning the encoding of the pointers of its entries
+func (f *ehFrame) cie(end int) byte {
+	enc := byte(ehPtrAbs)
+
+	version := f.u8()
+
+	aug := ""
+	for f.off < end && f.buf[f.off] != 0 {
+		aug += string(f.buf[f.off])
+		f.off++
+	}
+
+	f.off++
+
+	// the old GNU "eh" augmentation has a pointer to the exception table
+	if strings.HasPrefix(aug, "eh") {
+		f.ptr(ehPtrAbs)
+	}
+
+	// the code and data alignment, then the return address register
+	f.uleb()
+	f.sleb()
+	if version == 1 {
+		f.u8()
+	} else {
+		f.uleb()
+	}
+
+	if len(aug) == 0 || aug[0] != 'z' {
+		return enc
+	}
+
+	f.uleb()
+	for _, c := range aug[1:] {
+		switch c {
+		case 'R':
+			enc = f.u8()
+		case 'P':
+			f.ptr(f.u8())
+		case 'L':
+			f.u8()
+		case 'S', 'B', 'G':
+		default:
+			return enc
+		}
+	}
+
+	return enc
+}
+
+// ptr will read a pointer with the given encoding
+func (f *ehFrame) ptr(enc byte) (uint64, bool) {
+	if enc == ehPtrOmit {
+		return 0, false
+	}
+
+	pc := f.addr + uint64(f.off)
+
+	var v uint64
+	switch enc & 0x0f {
+	case ehPtrAbs:
+		if f.wide {
+			v = f.u64()
+		} else {
+			v = uint64(f.u32())
+		}
+	case ehPtrUleb128:
+		v = f.uleb()
+	case ehPtrUdata2:
+		v = uint64(f.u16())
+	case ehPtrUdata4:
+		v = uint64(f.u32())
+	case ehPtrUdata8:
+		v = f.u64()
+	case ehPtrSleb128:
+		v = uint64(f.sleb())
+	case ehPtrSdata2:
+		v = uint64(int16(f.u16()))
+	case ehPtrSdata4:
+		v = uint64(int32(f.u32()))
+	case ehPtrSdata8:
+		v = f.u64()
+	default:
+		return 0, false
+	}
+
+	switch enc & 0x70 {
+	case 0:
+	case ehPtrPCRel:
+		v += pc
+	case ehPtrDataRel:
+		v += f.base
+	default:
+		return 0, false
+	}
+
+	if !f.wide {
+		v &= 0xffffffff
+	}
+
+	return v, !f.bad && enc&0x80 == 0
+}
+
+// take will return the next n bytes, or nil past the end of the buffer
+func (f *ehFrame) take(n int) []byte {
+	if f.bad || f.off+n > len(f.buf) {
+		f.bad = true
+		return nil
+	}
+
+	b := f.buf[f.off : f.off+n]
+	f.off += n
+
+	return b
+}
+
+// u8 will read a byte
+func (f *ehFrame) u8() byte {
+	if b := f.take(1); b != nil {
+		return b[0]
+	}
+
+	return 0
+}
+
+// u16 will read a 16 bit value
+func (f *ehFrame) u16() uint16 {
+	if b := f.take(2); b != nil {
+		return f.order.Uint16(b)
+	}
+
+	return 0
+}
+
+// u32 will read a 32 bit value
+func (f *ehFrame) u32() uint32 {
+	if b := f.take(4); b != nil {
+		return f.order.Uint32(b)
+	}
+
+	return 0
+}
+
+// u64 will read a 64 bit value
+func (f *ehFrame) u64() uint64 {
+	if b := f.take(8); b != nil {
+		return f.order.Uint64(b)
+	}
+
+	return 0
+}
+
+// uleb will read an unsigned LEB128 value
+func (f *ehFrame) uleb() uint64 {
+	var v uint64
+	for shift := uint(0); ; shift += 7 {
+		b := f.u8()
+		if shift < 64 {
+			v |= uint64(b&0x7f) << shift
+		}
+
+		if b&0x80 == 0 || f.bad {
+			return v
+		}
+	}
+}
+
+// sleb will read a signed LEB128 value
+func (f *ehFrame) sleb() int64 {
+	var v int64
+	var b byte
+
+	shift := uint(0)
+	for {
+		b = f.u8()
+		if shift < 64 {
+			v |= int64(b&0x7f) << shift
+		}
+
+		shift += 7
+		if b&0x80 == 0 || f.bad {
+			break
+		}
+	}
+
+	if shift < 64 && b&0x40 != 0 {
+		v |= -1 << shift
+	}
+
+	return v
+}
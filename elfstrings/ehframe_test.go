@@ -0,0 +1,168 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestEHFramePtr(t *testing.T) {
+	const addr, base = 0x402000, 0x403000
+
+	tests := []struct {
+		name string
+		enc  byte
+		wide bool
+		buf  []byte
+		want uint64
+		ok   bool
+	}{
+		{"absptr wide", ehPtrAbs, true, []byte{0x10, 0x32, 0x54, 0x76, 0x98, 0xba, 0xdc, 0xfe}, 0xfedcba9876543210, true},
+		{"absptr narrow", ehPtrAbs, false, []byte{0x00, 0x10, 0x40, 0x00}, 0x401000, true},
+		{"uleb128", ehPtrUleb128, true, []byte{0xe5, 0x8e, 0x26}, 624485, true},
+		{"udata2", ehPtrUdata2, true, []byte{0xff, 0xff}, 0xffff, true},
+		{"udata4", ehPtrUdata4, true, []byte{0xff, 0xff, 0xff, 0xff}, 0xffffffff, true},
+		{"udata8", ehPtrUdata8, true, []byte{1, 0, 0, 0, 0, 0, 0, 0x80}, 0x8000000000000001, true},
+		{"sleb128", ehPtrSleb128, true, []byte{0xc0, 0xbb, 0x78}, uint64(0xffffffffffffffff - 123455), true},
+		{"sdata2", ehPtrSdata2, true, []byte{0xfe, 0xff}, 0xfffffffffffffffe, true},
+		{"sdata4", ehPtrSdata4, true, []byte{0xfc, 0xff, 0xff, 0xff}, 0xfffffffffffffffc, true},
+		{"sdata8", ehPtrSdata8, true, []byte{0xf8, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 0xfffffffffffffff8, true},
+		{"pcrel sdata4 backward", ehPtrPCRel | ehPtrSdata4, true, []byte{0x00, 0xf0, 0xff, 0xff}, addr - 0x1000, true},
+		{"pcrel sdata4 forward", ehPtrPCRel | ehPtrSdata4, true, []byte{0x00, 0x10, 0x00, 0x00}, addr + 0x1000, true},
+		{"pcrel sdata4 narrow", ehPtrPCRel | ehPtrSdata4, false, []byte{0x00, 0xf0, 0xff, 0xff}, addr - 0x1000, true},
+		{"pcrel udata2", ehPtrPCRel | ehPtrUdata2, true, []byte{0x10, 0x00}, addr + 0x10, true},
+		{"datarel sdata4", ehPtrDataRel | ehPtrSdata4, true, []byte{0xf0, 0xff, 0xff, 0xff}, base - 0x10, true},
+		{"datarel uleb128", ehPtrDataRel | ehPtrUleb128, true, []byte{0x80, 0x01}, base + 0x80, true},
+		{"narrow wraps", ehPtrSdata8, false, []byte{0xf8, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 0xfffffff8, true},
+		{"omit", ehPtrOmit, true, []byte{0, 0, 0, 0}, 0, false},
+		{"indirect", 0x80 | ehPtrPCRel | ehPtrSdata4, true, []byte{0x00, 0x10, 0x00, 0x00}, addr + 0x1000, false},
+		{"textrel", 0x20 | ehPtrSdata4, true, []byte{0, 0, 0, 0}, 0, false},
+		{"funcrel", 0x40 | ehPtrSdata4, true, []byte{0, 0, 0, 0}, 0, false},
+		{"unknown format", 0x05, true, []byte{0, 0, 0, 0}, 0, false},
+		{"truncated udata4", ehPtrUdata4, true, []byte{0x01, 0x02}, 0, false},
+		{"truncated uleb128", ehPtrUleb128, true, []byte{0x80, 0x80}, 0, false},
+	}
+
+	for _, tt := range tests {
+		f := &ehFrame{buf: tt.buf, addr: addr, order: binary.LittleEndian, wide: tt.wide, base: base}
+
+		got, ok := f.ptr(tt.enc)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("%s: ptr(%#x) = %#x %v, want %#x %v", tt.name, tt.enc, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestEHFrameFunctions(t *testing.T) {
+	const (
+		text    = 0x401000
+		ehFrame = 0x402000
+		hdr     = 0x403000
+	)
+
+	// pointer will encode the value as it is at addr
+	pointer := func(enc byte, v uint64, addr uint64) []byte {
+		switch enc & 0x70 {
+		case ehPtrPCRel:
+			v -= addr
+		case ehPtrDataRel:
+			v -= hdr
+		}
+
+		buf := make([]byte, 8)
+		binary.LittleEndian.PutUint64(buf, v)
+
+		switch enc & 0x0f {
+		case ehPtrUdata2, ehPtrSdata2:
+			return buf[:2]
+		case ehPtrUdata4, ehPtrSdata4:
+			return buf[:4]
+		}
+
+		return buf
+	}
+
+	// cie is version 1 with the augmentation zR, the alignments of code
+	// and data and the return address register, then the encoding
+	cie := func(enc byte) []byte {
+		return []byte{1, 'z', 'R', 0, 1, 0x78, 16, 1, enc}
+	}
+
+	type fde struct {
+		addr, size uint64
+	}
+
+	tests := []struct {
+		name string
+		enc  byte
+		// wide uses the 64 bit lengths
+		wide bool
+		want []fde
+	}{
+		{"pcrel sdata4", ehPtrPCRel | ehPtrSdata4, false, []fde{{text, 0x20}, {text + 0x20, 0x40}}},
+		{"absptr", ehPtrAbs, false, []fde{{text, 0x20}, {text + 0x20, 0x40}}},
+		{"udata4", ehPtrUdata4, false, []fde{{text, 0x20}, {text + 0x20, 0x40}}},
+		{"datarel sdata4", ehPtrDataRel | ehPtrSdata4, false, []fde{{text, 0x20}, {text + 0x20, 0x40}}},
+		{"pcrel udata8", ehPtrPCRel | ehPtrUdata8, false, []fde{{text, 0x20}, {text + 0x20, 0x40}}},
+		{"64 bit lengths", ehPtrPCRel | ehPtrSdata4, true, []fde{{text, 0x20}, {text + 0x20, 0x40}}},
+		{"omitted", ehPtrOmit, false, nil},
+	}
+
+	for _, tt := range tests {
+		var frame []byte
+
+		// entry will append an entry with its length, and its id which is
+		// zero for a CIE and the distance back to the CIE otherwise
+		entry := func(id func(idOff int) uint64, body func(at uint64) []byte) {
+			idOff := len(frame) + 4
+			if tt.wide {
+				idOff = len(frame) + 12
+			}
+
+			idSize := 4
+			if tt.wide {
+				idSize = 8
+			}
+
+			rest := body(ehFrame + uint64(idOff+idSize))
+			length := make([]byte, 4)
+			binary.LittleEndian.PutUint32(length, uint32(idSize+len(rest)))
+			if tt.wide {
+				length = append([]byte{0xff, 0xff, 0xff, 0xff}, testWords(uint64(idSize+len(rest)))...)
+			}
+
+			ident := testWords(id(idOff))[:idSize]
+			frame = append(append(append(frame, length...), ident...), rest...)
+		}
+
+		entry(func(int) uint64 { return 0 }, func(uint64) []byte { return cie(tt.enc) })
+
+		for _, f := range []fde{{text, 0x20}, {text + 0x20, 0x40}} {
+			f := f
+			entry(func(idOff int) uint64 { return uint64(idOff) }, func(at uint64) []byte {
+				body := pointer(tt.enc, f.addr, at)
+				body = append(body, pointer(tt.enc&0x0f, f.size, 0)...)
+				return append(body, 0)
+			})
+		}
+
+		// the terminator
+		frame = append(frame, 0, 0, 0, 0)
+
+		r := testELF(t, []testSection{
+			{name: ".text", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC | elf.SHF_EXECINSTR, addr: text, data: make([]byte, 0x60)},
+			{name: ".eh_frame", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC, addr: ehFrame, data: frame},
+			{name: ".eh_frame_hdr", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC, addr: hdr, data: make([]byte, 8)},
+		}, nil)
+
+		var got []fde
+		for _, fn := range r.readerEHFrameFunctions() {
+			got = append(got, fde{fn.addr, fn.size})
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: found %#x, want %#x", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package elfstrings
+
+import (
+	"bytes"
+	"debug/elf"
+	"sort"
+	"time"
+
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// The default limits of the emulation, so that a hostile binary cannot
+// keep it running forever
+const (
+	// DefaultEmulateSteps is how many instructions each call may run
+	DefaultEmulateSteps = 200000
+	// DefaultEmulateTimeout is how long all of the emulation may take
+	DefaultEmulateTimeout = 10 * time.Second
+	// decoderMaxCandidates is how many of the likeliest decoders are run
+	decoderMaxCandidates = 32
+	// decoderMaxSize is the size of the largest function that is taken
+	// to be a decoder, they are small loops
+	decoderMaxSize = 0x1000
+	// decoderMaxCalls is how many calls of each decoder are emulated
+	decoderMaxCalls = 256
+	// decoderContext is how many instructions of the caller before a
+	// call are run to set up its arguments
+	decoderContext = 512
+)
+
+// decoder is a function which looks like it decodes strings
+type decoder struct {
+	function
+	calls []uint64
+	score float64
+}
+
+// ReaderDecodedStrings will find the functions which look like they decode
+// strings at runtime, those called often with a loop of XOR-heavy arithmetic,
+// and emulate each call of them with the arguments that the caller sets up.
+// The printable buffers which they write are returned as decoded strings,
+// attributed to the call along with the decoder. The amount of instructions
+// and time that the emulation may take is limited by opts. Only x86-64 code
+// is emulated
+func (r *ElfReader) ReaderDecodedStrings(opts *Options) []StringRecord {
+	var records []StringRecord
+
+	if r.ExecReader.Machine != elf.EM_X86_64 || r.ExecReader.Type == elf.ET_REL {
+		return nil
+	}
+
+	steps := opts.EmulateSteps
+	if steps == 0 {
+		steps = DefaultEmulateSteps
+	}
+
+	timeout := opts.EmulateTimeout
+	if timeout == 0 {
+		timeout = DefaultEmulateTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	static := r.readerLoadedImage()
+	stubs := r.readerPLTStubs()
+	seen := make(map[string]bool)
+
+	for _, dec := range r.readerDecoders() {
+		for _, call := range dec.calls {
+			if time.Now().After(deadline) {
+				return UtilLimitRecords(records, opts)
+			}
+
+			mem := newEmuMemory(r)
+			for _, run := range r.readerEmulateCall(call, dec.addr, mem, stubs, steps, deadline) {
+				s := r.readerSectionOfAddress(call)
+				if s == nil {
+					continue
+				}
+
+				for _, rec := range r.readerBuiltRecords(s, run, call, SourceDecoded, dec.addr, opts) {
+					// what is already in the file as it is was only copied
+					key := rec.Text
+					if seen[key] || bytes.Contains(static, []byte(rec.Text)) {
+						continue
+					}
+
+					seen[key] = true
+					records = append(records, rec)
+				}
+			}
+		}
+	}
+
+	return UtilLimitRecords(records, opts)
+}
+
+// readerDecoders will find the likeliest decoding functions, those called
+// more than once with a backward branch and more XOR or rotate arithmetic
+// than zeroing, ordered by how likely they are
+func (r *ElfReader) readerDecoders() []decoder {
+	calls := make(map[uint64][]uint64)
+
+	for _, s := range r.ExecReader.Sections {
+		if s.Type != elf.SHT_PROGBITS || s.Flags&elf.SHF_EXECINSTR == 0 {
+			continue
+		}
+
+		code, err := r.ReaderReadAt(s.Offset, s.Size)
+		if err != nil {
+			continue
+		}
+
+		for off := 0; off < len(code); {
+			inst, err := x86asm.Decode(code[off:], 64)
+			if err != nil || inst.Len == 0 {
+				off++
+				continue
+			}
+
+			pc := s.Addr + uint64(off)
+			off += inst.Len
+
+			if rel, ok := inst.Args[0].(x86asm.Rel); ok && inst.Op == x86asm.CALL {
+				target := pc + uint64(inst.Len) + uint64(int64(rel))
+				calls[target] = append(calls[target], pc)
+			}
+		}
+	}
+
+	var decoders []decoder
+	for _, fn := range r.readerFunctions() {
+		if len(calls[fn.addr]) < 2 || fn.size == 0 || fn.size > decoderMaxSize {
+			continue
+		}
+
+		if score := r.readerDecoderScore(fn); score > 0 {
+			dec := decoder{function: fn, calls: calls[fn.addr], score: score * float64(len(calls[fn.addr]))}
+			if len(dec.calls) > decoderMaxCalls {
+				dec.calls = dec.calls[:decoderMaxCalls]
+			}
+
+			decoders = append(decoders, dec)
+		}
+	}
+
+	sort.SliceStable(decoders, func(i, j int) bool {
+		return decoders[i].score > decoders[j].score
+	})
+
+	if len(decoders) > decoderMaxCandidates {
+		decoders = decoders[:decoderMaxCandidates]
+	}
+
+	return decoders
+}
+
+// readerDecoderScore will score how much the function looks like a decoder,
+// zero when it has no loop or none of the arithmetic that decoding needs
+func (r *ElfReader) readerDecoderScore(fn function) float64 {
+	s := r.readerSectionOfAddress(fn.addr)
+	if s == nil || fn.addr+fn.size > s.Addr+s.Size {
+		return 0
+	}
+
+	code, err := r.ReaderReadAt(s.Offset+fn.addr-s.Addr, fn.size)
+	if err != nil {
+		return 0
+	}
+
+	var loop bool
+	var total, mixing int
+
+	for off := 0; off < len(code); {
+		inst, err := x86asm.Decode(code[off:], 64)
+		if err != nil || inst.Len == 0 {
+			off++
+			continue
+		}
+
+		pc := fn.addr + uint64(off)
+		off += inst.Len
+		total++
+
+		if rel, ok := inst.Args[0].(x86asm.Rel); ok && inst.Op != x86asm.CALL {
+			target := pc + uint64(inst.Len) + uint64(int64(rel))
+			if target >= fn.addr && target <= pc {
+				loop = true
+			}
+		}
+
+		switch inst.Op {
+		case x86asm.XOR:
+			// xor of a register with itself only zeroes it
+			if inst.Args[0] != inst.Args[1] {
+				mixing++
+			}
+		case x86asm.ROL, x86asm.ROR, x86asm.NOT, x86asm.NEG:
+			mixing++
+		}
+	}
+
+	if !loop || mixing == 0 || total == 0 {
+		return 0
+	}
+
+	return float64(mixing) / float64(total)
+}
+
+// readerEmulateCall will emulate the instructions of the caller leading up
+// to the call, which set up its arguments, and then the call itself. The
+// runs of bytes written by the call are returned
+func (r *ElfReader) readerEmulateCall(call uint64, target uint64, mem *emuMemory, stubs map[uint64]string, steps uint64, deadline time.Time) [][]byte {
+	e := newX86Emulator(r, mem, stubs, steps, deadline)
+
+	// the caller is run straight through, as the branches cannot be
+	// followed without knowing how it was called. The calls it makes
+	// are skipped, which only keeps the registers they preserve
+	for _, pc := range r.readerCallContext(call) {
+		e.rip = pc
+
+		inst, err := e.fetch()
+		if err != nil {
+			continue
+		}
+
+		if inst.Op == x86asm.CALL {
+			for _, reg := range []x86asm.Reg{x86asm.RAX, x86asm.RCX, x86asm.RDX, x86asm.RSI, x86asm.RDI, x86asm.R8, x86asm.R9, x86asm.R10, x86asm.R11} {
+				e.regs[utilRegFamily(reg)] = 0
+			}
+
+			continue
+		}
+
+		// an argument that cannot be set up is left as it is
+		e.step()
+	}
+
+	mem.written = make(map[uint64]bool)
+
+	e.rip = target
+	if e.push(emuReturn) != nil {
+		return nil
+	}
+
+	// whatever was written before the limits were hit is still kept, as
+	// much may have been decoded by then
+	e.run(emuReturn)
+
+	return utilWrittenRuns(mem)
+}
+
+// readerCallContext will return the addresses of the instructions of the
+// function before the call which are not branches, up to a limit
+func (r *ElfReader) readerCallContext(call uint64) []uint64 {
+	start, ok := r.ReaderFunctionStart(call)
+	if !ok || call-start > decoderMaxSize*4 {
+		return nil
+	}
+
+	s := r.readerSectionOfAddress(start)
+	if s == nil || call > s.Addr+s.Size {
+		return nil
+	}
+
+	code, err := r.ReaderReadAt(s.Offset+start-s.Addr, call-start)
+	if err != nil {
+		return nil
+	}
+
+	var pcs []uint64
+	for off := 0; off < len(code); {
+		inst, err := x86asm.Decode(code[off:], 64)
+		if err != nil || inst.Len == 0 {
+			off++
+			continue
+		}
+
+		pc := start + uint64(off)
+		off += inst.Len
+
+		switch inst.Op {
+		case x86asm.RET, x86asm.JMP:
+			continue
+		}
+
+		if _, ok := inst.Args[0].(x86asm.Rel); ok && inst.Op != x86asm.CALL {
+			continue
+		}
+
+		pcs = append(pcs, pc)
+	}
+
+	if len(pcs) > decoderContext {
+		pcs = pcs[len(pcs)-decoderContext:]
+	}
+
+	return pcs
+}
+
+// readerPLTStubs will name the PLT entries of the binary by the functions
+// that they jump to, found from the GOT slot each one jumps through
+func (r *ElfReader) readerPLTStubs() map[uint64]string {
+	stubs := make(map[uint64]string)
+
+	syms, err := r.ExecReader.DynamicSymbols()
+	if err != nil {
+		return stubs
+	}
+
+	// the GOT slots of the jump slot relocations name the import
+	slots := make(map[uint64]string)
+	for _, s := range r.ExecReader.Sections {
+		if s.Type != elf.SHT_RELA {
+			continue
+		}
+
+		buf, err := r.ReaderReadAt(s.Offset, s.Size)
+		if err != nil {
+			continue
+		}
+
+		order := r.ExecReader.ByteOrder
+		for i := 0; i+24 <= len(buf); i += 24 {
+			info := order.Uint64(buf[i+8:])
+			if sym := info >> 32; sym != 0 && sym <= uint64(len(syms)) {
+				slots[order.Uint64(buf[i:])] = syms[sym-1].Name
+			}
+		}
+	}
+
+	for _, s := range r.ExecReader.Sections {
+		if s.Flags&elf.SHF_EXECINSTR == 0 || (s.Name != ".plt" && s.Name != ".plt.sec" && s.Name != ".plt.got") {
+			continue
+		}
+
+		code, err := r.ReaderReadAt(s.Offset, s.Size)
+		if err != nil {
+			continue
+		}
+
+		// each entry has a jump through its slot, the entry starts at
+		// most 8 bytes before it after an endbr64 or a bnd prefix
+		for off := 0; off < len(code); {
+			inst, err := x86asm.Decode(code[off:], 64)
+			if err != nil || inst.Len == 0 {
+				off++
+				continue
+			}
+
+			pc := s.Addr + uint64(off)
+			off += inst.Len
+
+			mem, ok := inst.Args[0].(x86asm.Mem)
+			if inst.Op != x86asm.JMP || !ok || mem.Base != x86asm.RIP {
+				continue
+			}
+
+			name, ok := slots[pc+uint64(inst.Len)+uint64(int64(int32(mem.Disp)))]
+			if !ok {
+				continue
+			}
+
+			entry := pc &^ 0xf
+			if s.Name == ".plt.got" {
+				entry = pc &^ 0x7
+			}
+
+			stubs[entry] = name
+			stubs[pc] = name
+		}
+	}
+
+	return stubs
+}
+
+// readerLoadedImage will return the contents of the loaded segments, which
+// the decoded strings are checked against
+func (r *ElfReader) readerLoadedImage() []byte {
+	var image []byte
+
+	for _, prog := range r.ExecReader.Progs {
+		if prog.Type != elf.PT_LOAD {
+			continue
+		}
+
+		buf, err := r.ReaderReadAt(prog.Off, prog.Filesz)
+		if err == nil {
+			image = append(image, buf...)
+		}
+	}
+
+	return image
+}
+
+// readerSectionOfAddress will find the allocated section containing the
+// virtual address, or nil if there is none
+func (r *ElfReader) readerSectionOfAddress(addr uint64) *elf.Section {
+	for _, s := range r.ExecReader.Sections {
+		if s.Flags&elf.SHF_ALLOC == 0 || s.Type == elf.SHT_NOBITS {
+			continue
+		}
+
+		if addr >= s.Addr && addr < s.Addr+s.Size {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// utilWrittenRuns will return the contiguous runs of bytes written to the
+// memory, in the order of their addresses
+func utilWrittenRuns(mem *emuMemory) [][]byte {
+	addrs := make([]uint64, 0, len(mem.written))
+	for addr := range mem.written {
+		addrs = append(addrs, addr)
+	}
+
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i] < addrs[j]
+	})
+
+	var runs [][]byte
+	var run []byte
+	for i, addr := range addrs {
+		if i > 0 && addr != addrs[i-1]+1 {
+			runs = append(runs, run)
+			run = nil
+		}
+
+		b, _ := mem.read(addr, 1)
+		run = append(run, byte(b))
+	}
+
+	if run != nil {
+		runs = append(runs, run)
+	}
+
+	return runs
+}
@@ -0,0 +1,109 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"testing"
+)
+
+func TestDecodedStrings(t *testing.T) {
+	plain := []string{"http://update.example.com/gate.php", "/bin/sh -c 'crontab -l'"}
+
+	// loop will wrap the operation on each byte in xor ecx, ecx; the
+	// operation; inc rcx; cmp rcx, rsi; jb back to the operation
+	loop := func(op ...byte) []byte {
+		code := append([]byte{0x31, 0xc9}, op...)
+		return append(code, 0x48, 0xff, 0xc1, 0x48, 0x39, 0xf1, 0x72, byte(-(len(op) + 8)))
+	}
+
+	decoders := []struct {
+		name   string
+		code   []byte
+		encode func(b byte) byte
+	}{
+		// xor byte [rdi+rcx], 0x5a
+		{"xor", append(loop(0x80, 0x34, 0x0f, 0x5a), 0xc3), func(b byte) byte { return b ^ 0x5a }},
+		// not byte [rdi+rcx]
+		{"not", append(loop(0xf6, 0x14, 0x0f), 0xc3), func(b byte) byte { return ^b }},
+		// rol byte [rdi+rcx], 3
+		{"rol", append(loop(0xc0, 0x04, 0x0f, 0x03), 0xc3), func(b byte) byte { return b>>3 | b<<5 }},
+		// push rbp; mov rbp, rsp; sub rsp, 0x200; then mov al, [rdi+rcx];
+		// xor al, 0x5a; mov [rbp+rcx-0x100], al for each byte; leave; ret
+		{"stack", append(append([]byte{0x55, 0x48, 0x89, 0xe5, 0x48, 0x81, 0xec, 0x00, 0x02, 0x00, 0x00},
+			loop(0x8a, 0x04, 0x0f, 0x34, 0x5a, 0x88, 0x84, 0x0d, 0x00, 0xff, 0xff, 0xff)...), 0xc9, 0xc3),
+			func(b byte) byte { return b ^ 0x5a }},
+	}
+
+	layouts := []struct {
+		name       string
+		text, data uint64
+	}{
+		{"data after code", 0x401000, 0x402000},
+		{"data before code", 0x402000, 0x401000},
+	}
+
+	for _, layout := range layouts {
+		for _, dec := range decoders {
+			name := dec.name + ", " + layout.name
+			text, data := layout.text, layout.data
+			main := text + 0x40
+
+			code := make([]byte, main-text)
+			copy(code, dec.code)
+
+			// each string is decoded by a call with its address and
+			// length, as lea rdi, [rip+string]; mov esi, length; call
+			var encoded []byte
+			for _, s := range plain {
+				at := data + uint64(len(encoded))
+				for i := 0; i < len(s); i++ {
+					encoded = append(encoded, dec.encode(s[i]))
+				}
+
+				encoded = append(encoded, make([]byte, 16-len(encoded)%16)...)
+
+				pc := text + uint64(len(code))
+				inst := make([]byte, 17)
+				copy(inst, []byte{0x48, 0x8d, 0x3d})
+				binary.LittleEndian.PutUint32(inst[3:], uint32(at-(pc+7)))
+				inst[7] = 0xbe
+				binary.LittleEndian.PutUint32(inst[8:], uint32(len(s)))
+				inst[12] = 0xe8
+				binary.LittleEndian.PutUint32(inst[13:], uint32(text-(pc+17)))
+				code = append(code, inst...)
+			}
+
+			code = append(code, 0xc3)
+
+			r := testELF(t, []testSection{
+				{name: ".text", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC | elf.SHF_EXECINSTR, addr: text, data: code},
+				{name: ".data", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC | elf.SHF_WRITE, addr: data, data: encoded},
+			}, []testSymbol{
+				{name: "decode", typ: elf.STT_FUNC, value: text, size: uint64(len(dec.code)), section: 1},
+				{name: "main", typ: elf.STT_FUNC, value: main, size: uint64(len(code)) - (main - text), section: 1},
+			})
+
+			records := r.ReaderDecodedStrings(&Options{})
+
+			var got []string
+			for _, rec := range records {
+				got = append(got, rec.Text)
+
+				if rec.Source != SourceDecoded || rec.FunctionAddress != text {
+					t.Errorf("%s: %q is from %s at %#x, want decoded by %#x", name, rec.Text, rec.Source, rec.FunctionAddress, text)
+				}
+			}
+
+			if len(got) != len(plain) {
+				t.Errorf("%s: decoded %q, want %q", name, got, plain)
+				continue
+			}
+
+			for i := range plain {
+				if got[i] != plain[i] {
+					t.Errorf("%s: decoded %q, want %q", name, got[i], plain[i])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,385 @@
+package elfstrings
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding to emulate an enum of the encodings a string may be in
+type Encoding int32
+
+// Encodings of strings that may be extracted
+const (
+	EncodingASCII Encoding = iota
+	EncodingUTF16LE
+	EncodingUTF16BE
+	EncodingUTF32LE
+	EncodingUTF32BE
+	EncodingUTF8
+	EncodingShiftJIS
+	EncodingGBK
+	EncodingKOI8R
+	EncodingWindows1250
+	EncodingWindows1251
+	EncodingWindows1252
+	EncodingWindows1253
+	EncodingWindows1254
+	EncodingWindows1255
+	EncodingWindows1256
+	EncodingWindows1257
+	EncodingWindows1258
+	EncodingBinary
+	encodingEnd
+)
+
+var encodingNames = map[Encoding]string{
+	EncodingASCII:       "ascii",
+	EncodingUTF16LE:     "utf-16le",
+	EncodingUTF16BE:     "utf-16be",
+	EncodingUTF32LE:     "utf-32le",
+	EncodingUTF32BE:     "utf-32be",
+	EncodingUTF8:        "utf-8",
+	EncodingShiftJIS:    "shift-jis",
+	EncodingGBK:         "gbk",
+	EncodingKOI8R:       "koi8-r",
+	EncodingWindows1250: "windows-1250",
+	EncodingWindows1251: "windows-1251",
+	EncodingWindows1252: "windows-1252",
+	EncodingWindows1253: "windows-1253",
+	EncodingWindows1254: "windows-1254",
+	EncodingWindows1255: "windows-1255",
+	EncodingWindows1256: "windows-1256",
+	EncodingWindows1257: "windows-1257",
+	EncodingWindows1258: "windows-1258",
+	EncodingBinary:      "binary",
+}
+
+// String will return the name of the encoding
+func (e Encoding) String() string {
+	if name, ok := encodingNames[e]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("encoding(%d)", int32(e))
+}
+
+// Width will return the size in bytes of a single code unit
+func (e Encoding) Width() int {
+	switch e {
+	case EncodingUTF16LE, EncodingUTF16BE:
+		return 2
+	case EncodingUTF32LE, EncodingUTF32BE:
+		return 4
+	}
+
+	return 1
+}
+
+// order will return the byte order of the code units
+func (e Encoding) order() binary.ByteOrder {
+	if e == EncodingUTF16BE || e == EncodingUTF32BE {
+		return binary.BigEndian
+	}
+
+	return binary.LittleEndian
+}
+
+// WideString is a run of wide characters found within a buffer
+type WideString struct {
+	// Offset is the offset of the first code unit within the buffer
+	Offset uint64
+	// Raw is the code units as they are in the buffer
+	Raw []byte
+	// Text is the run decoded to UTF-8
+	Text string
+	// Length is the length of the run in code points
+	Length uint64
+}
+
+// UtilWideStrings will find the runs of printable wide characters in the
+// buffer, base is the file offset of the buffer so that the code units
+// are read at their natural alignment, unless unaligned is set in which
+// case every byte offset is tried
+func UtilWideStrings(buf []byte, base uint64, enc Encoding, unaligned bool) []WideString {
+	var runs []WideString
+
+	width := enc.Width()
+	if width == 1 {
+		return nil
+	}
+
+	phases := []int{int((uint64(width) - base%uint64(width)) % uint64(width))}
+	if unaligned {
+		phases = phases[:0]
+		for i := 0; i < width; i++ {
+			phases = append(phases, i)
+		}
+	}
+
+	for _, phase := range phases {
+		runs = append(runs, utilWideRuns(buf, phase, enc)...)
+	}
+
+	return runs
+}
+
+// utilWideRuns will find the runs of printable wide characters
+// starting at the given phase within the buffer
+func utilWideRuns(buf []byte, phase int, enc Encoding) []WideString {
+	var runs []WideString
+	var text []rune
+	var pairs int
+
+	width := enc.Width()
+	order := enc.order()
+	start := -1
+
+	// a run where most code units are two printable ASCII bytes, or an
+	// ASCII byte in the high half, is almost always plain text or a wide
+	// string being read at the wrong width or byte order
+	flush := func(end int) {
+		if start != -1 && len(text) != 0 && pairs*2 <= len(text) && UtilIsSingleScript(text) {
+			runs = append(runs, WideString{
+				Offset: uint64(start),
+				Raw:    buf[start:end],
+				Text:   string(text),
+				Length: uint64(len(text)),
+			})
+		}
+
+		start = -1
+		text = text[:0]
+		pairs = 0
+	}
+
+	i := phase
+	for i+width <= len(buf) {
+		var r rune
+		size := width
+
+		if width == 2 {
+			r = rune(order.Uint16(buf[i:]))
+			if utf16.IsSurrogate(r) {
+				if i+4 > len(buf) {
+					flush(i)
+					break
+				}
+
+				r = utf16.DecodeRune(r, rune(order.Uint16(buf[i+2:])))
+				size = 4
+			}
+		} else {
+			r = rune(order.Uint32(buf[i:]))
+		}
+
+		if !UtilIsWidePrintable(r) {
+			flush(i)
+			i += width
+			continue
+		}
+
+		if start == -1 {
+			start = i
+		}
+
+		if size == 2 && utilIsMisread(r) {
+			pairs++
+		}
+
+		text = append(text, r)
+		i += size
+	}
+
+	flush(i)
+
+	return runs
+}
+
+// utilIsMisread will check if the UTF-16 code unit looks like ASCII
+// that has been read at the wrong width or byte order
+func utilIsMisread(r rune) bool {
+	hi, lo := byte(r>>8), byte(r)
+	if lo == 0 {
+		return UtilIsPrintable(hi)
+	}
+
+	return UtilIsPrintable(hi) && UtilIsPrintable(lo)
+}
+
+// scripts are the writing systems that letters are classified into,
+// the scripts which are commonly written together are grouped
+var scripts = []struct {
+	name   string
+	tables []*unicode.RangeTable
+}{
+	{"latin", []*unicode.RangeTable{unicode.Latin}},
+	{"greek", []*unicode.RangeTable{unicode.Greek}},
+	{"cyrillic", []*unicode.RangeTable{unicode.Cyrillic}},
+	{"armenian", []*unicode.RangeTable{unicode.Armenian}},
+	{"georgian", []*unicode.RangeTable{unicode.Georgian}},
+	{"hebrew", []*unicode.RangeTable{unicode.Hebrew}},
+	{"arabic", []*unicode.RangeTable{unicode.Arabic}},
+	{"devanagari", []*unicode.RangeTable{unicode.Devanagari}},
+	{"bengali", []*unicode.RangeTable{unicode.Bengali}},
+	{"tamil", []*unicode.RangeTable{unicode.Tamil}},
+	{"thai", []*unicode.RangeTable{unicode.Thai}},
+	{"hangul", []*unicode.RangeTable{unicode.Hangul}},
+	{"cjk", []*unicode.RangeTable{unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Bopomofo}},
+}
+
+// UtilRuneScript will return the script of a letter, or an empty
+// string if the rune is common to all scripts, such as punctuation
+func UtilRuneScript(r rune) string {
+	if r < utf8.RuneSelf {
+		if unicode.IsLetter(r) {
+			return "latin"
+		}
+
+		return ""
+	}
+
+	for _, script := range scripts {
+		if unicode.In(r, script.tables...) {
+			return script.name
+		}
+	}
+
+	if unicode.IsLetter(r) {
+		return "other"
+	}
+
+	return ""
+}
+
+// UtilIsSingleScript will check that the text has letters, all of which
+// are from the same script, ASCII letters are allowed alongside any other
+// script as they are commonly mixed in
+func UtilIsSingleScript(text []rune) bool {
+	var found string
+	var ascii bool
+
+	for _, r := range text {
+		script := UtilRuneScript(r)
+		if script == "" {
+			continue
+		}
+
+		if r < utf8.RuneSelf {
+			ascii = true
+			continue
+		}
+
+		if found == "" {
+			found = script
+			continue
+		}
+
+		if script != found {
+			return false
+		}
+	}
+
+	if found == "" {
+		return ascii
+	}
+
+	return found != "other"
+}
+
+// UtilIsWidePrintable will check if the decoded code point
+// can be part of a wide string
+func UtilIsWidePrintable(r rune) bool {
+	if r == utf8.RuneError || r > unicode.MaxRune {
+		return false
+	}
+
+	return unicode.IsPrint(r) || r == '\t'
+}
+
+// EncodingParseStr converts a comma separated list of encodings such as
+// "ascii,utf16le" into the encodings, "all" selects every encoding
+// that strings can be extracted in
+func EncodingParseStr(list string) ([]Encoding, error) {
+	if strings.TrimSpace(strings.ToLower(list)) == "all" {
+		return []Encoding{EncodingASCII, EncodingUTF16LE, EncodingUTF16BE,
+			EncodingUTF32LE, EncodingUTF32BE}, nil
+	}
+
+	encodings, err := encodingParseList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, enc := range encodings {
+		if enc > EncodingUTF32BE {
+			return nil, fmt.Errorf("strings cannot be extracted in %s", enc)
+		}
+	}
+
+	return encodings, nil
+}
+
+// EncodingParseLegacyStr converts a comma separated list of legacy
+// encodings such as "sjis,koi8-r,cp1251" into the encodings, "all"
+// selects every legacy encoding
+func EncodingParseLegacyStr(list string) ([]Encoding, error) {
+	if strings.TrimSpace(strings.ToLower(list)) == "all" {
+		// the most widely used codepages come first, as they are
+		// preferred when two of them decode a string equally well
+		return []Encoding{EncodingShiftJIS, EncodingGBK, EncodingWindows1251,
+			EncodingKOI8R, EncodingWindows1252, EncodingWindows1250,
+			EncodingWindows1253, EncodingWindows1254, EncodingWindows1255,
+			EncodingWindows1256, EncodingWindows1257, EncodingWindows1258}, nil
+	}
+
+	encodings, err := encodingParseList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, enc := range encodings {
+		if _, ok := codepages[enc]; !ok {
+			return nil, fmt.Errorf("%s is not a legacy encoding", enc)
+		}
+	}
+
+	return encodings, nil
+}
+
+// encodingParseList converts a comma separated list of encoding names
+func encodingParseList(list string) ([]Encoding, error) {
+	var encodings []Encoding
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		name = strings.Replace(name, "-", "", -1)
+		if name == "" {
+			continue
+		}
+
+		if name == "sjis" {
+			name = "shiftjis"
+		} else if strings.HasPrefix(name, "cp125") {
+			name = "windows" + name[2:]
+		}
+
+		found := false
+		for enc, encName := range encodingNames {
+			if strings.Replace(encName, "-", "", -1) == name {
+				encodings = append(encodings, enc)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unknown encoding %q", name)
+		}
+	}
+
+	return encodings, nil
+}
@@ -0,0 +1,387 @@
+// Package elfstrings reads the string sections of an ELF binary and
+// returns every string found as a typed record, filtered and transformed
+// according to a set of options.
+package elfstrings
+
+import (
+	"debug/elf"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shawnsmithdev/zermelo"
+)
+
+// DefaultSections is the list of sections that are known to contain
+// strings in most binaries
+var DefaultSections = []string{".dynstr", ".rodata", ".rdata",
+	".strtab", ".comment", ".note",
+	".stab", ".stabstr", ".note.ABI-tag", ".note.gnu.build-id"}
+
+// Options controls which strings are returned by the extractor
+// and how they are transformed beforehand
+type Options struct {
+	// MinLength is the minimum length of a string, zero for no limit
+	MinLength uint64
+	// MaxCount is the maximum amount of strings returned per section,
+	// zero for no limit
+	MaxCount uint64
+	// Demangle will demangle the symbols of C++, Rust, Swift, D, Java,
+	// Go and OCaml into their source identifiers
+	Demangle bool
+	// Demangling controls how much of a demangled symbol is shown
+	Demangling DemangleOptions
+	// Hex will convert the text to a hexadecimal literal
+	Hex bool
+	// NoTrim disables the removal of trailing newlines
+	NoTrim bool
+	// NoHuman disables the 'human readable' validation
+	NoHuman bool
+	// NoSplit disables the splitting of the string literals of Go and
+	// Rust binaries, which are stored back to back, at the ends found
+	// from their headers
+	NoSplit bool
+	// Encodings are the encodings that strings are extracted in,
+	// only EncodingASCII when nil
+	Encodings []Encoding
+	// Unaligned will look for wide strings at every byte offset rather
+	// than only at the natural alignment of their code units
+	Unaligned bool
+	// Legacy are the legacy encodings which are tried when a string
+	// is not valid UTF-8, such as EncodingShiftJIS or EncodingKOI8R
+	Legacy []Encoding
+	// MinScore is the minimum readability score of a string, between
+	// 0 and 1, zero for no limit
+	MinScore float64
+	// Tags only keeps the strings in at least one of the categories,
+	// every string is kept when nil
+	Tags []Tag
+	// Xrefs will find the instructions which reference each string
+	Xrefs bool
+	// EmulateSteps is how many instructions each emulated call may run,
+	// DefaultEmulateSteps when zero
+	EmulateSteps uint64
+	// EmulateTimeout is how long the emulation may take in all,
+	// DefaultEmulateTimeout when zero
+	EmulateTimeout time.Duration
+	// Prefixes are the lengths which strings are recovered after when
+	// they are not terminated, those of the compiler of the binary when
+	// nil, which are only Prefix8 for Free Pascal and otherwise none
+	Prefixes []Prefix
+	// UnwrapDepth is how many layers of Base64, hex, URL encoding and
+	// ROT13, and of the compressed streams and ELF binaries within
+	// them, are decoded from each string, zero to not decode them
+	UnwrapDepth uint64
+}
+
+// encodings will return the encodings that strings are extracted in
+func (o *Options) encodings() []Encoding {
+	if o.Encodings == nil {
+		return []Encoding{EncodingASCII}
+	}
+
+	return o.Encodings
+}
+
+// StringRecord is a single string found within the binary
+type StringRecord struct {
+	// Section is the name of the section the string resides in
+	Section string
+	// Offset is the offset of the string within the section
+	Offset uint64
+	// FileOffset is the offset of the string within the file
+	FileOffset uint64
+	// Address is the virtual address of the string, zero if
+	// the string is not loaded into memory
+	Address uint64
+	// Segment is the PT_LOAD segment mapping the string, nil if
+	// the string is not loaded into memory
+	Segment *Segment
+	// Encoding is the encoding the string was found or detected in
+	Encoding Encoding
+	// Score is the readability score of the string
+	Score Score
+	// Rank is the relevance of the string, nil until it has been ranked
+	Rank *Rank
+	// IOCs are the indicators of compromise within the string
+	IOCs []IOC
+	// Tags are the categories the string falls into
+	Tags []Tag
+	// Xrefs are the instructions which reference the string, only
+	// filled in when Options.Xrefs is set
+	Xrefs []Xref
+	// Source is where the string was recovered from
+	Source Source
+	// FunctionAddress is the address of the function which builds the
+	// string, for the strings that are not stored in the file as they are
+	FunctionAddress uint64
+	// Prefix is the length that the string was found after, PrefixNone
+	// when it is terminated rather than prefixed
+	Prefix Prefix
+	// Decodings are the transforms which were undone to recover the
+	// string from the bytes in the file, nil when it is stored as it is
+	Decodings []Decoding
+	// ID numbers the record among those of the reader, the records that
+	// are copies of the same string share it
+	ID uint64
+	// Parent is the string that this one was decoded from, which need
+	// not have passed the filters itself, nil when it was not decoded
+	// from another string
+	Parent *StringRecord
+	// Raw is the content of the string as it is in the file, or as it
+	// was rebuilt or decoded when it is not stored as it is
+	Raw []byte
+	// Text is the decoded text after the transforms have been applied
+	Text string
+	// Language is the language whose mangling the text was demangled
+	// from, or that of the first symbol demangled within it, LanguageNone
+	// when it was not demangled
+	Language Language
+	// Mangled are the symbols which were demangled, the string itself or
+	// those found within it
+	Mangled []MangledToken
+}
+
+// ReaderExtract will parse the strings of the given section and
+// run each of them through the filters and transforms in opts
+func (r *ElfReader) ReaderExtract(s *elf.Section, opts *Options) []StringRecord {
+	var records []StringRecord
+
+	sect := r.ReaderReadSection(s)
+	if sect == nil {
+		return nil
+	}
+
+	for _, enc := range opts.encodings() {
+		if enc == EncodingASCII {
+			records = append(records, r.readerExtractASCII(s, sect, opts)...)
+			continue
+		}
+
+		for _, w := range UtilWideStrings(sect, s.Offset, enc, opts.Unaligned) {
+			f := UtilFilterWide(&w, enc, opts)
+			if f == nil {
+				continue
+			}
+
+			records = append(records, r.readerRecord(s, w.Offset, w.Raw, f))
+		}
+	}
+
+	records = UtilLimitRecords(records, opts)
+	if opts.Xrefs {
+		r.readerAttachXrefs(records)
+	}
+
+	return records
+}
+
+// readerExtractASCII will parse the NUL terminated strings
+// of the section and filter them, along with the strings which
+// are prefixed by their length when there are any. The strings
+// of Go and Rust binaries are split into the literals they are made of
+func (r *ElfReader) readerExtractASCII(s *elf.Section, sect []byte, opts *Options) []StringRecord {
+	records, covered := r.readerExtractPrefixed(s, sect, opts)
+	prefixed := len(records)
+
+	nodes := r.ReaderParseStrings(sect)
+	if cuts := r.readerLiteralCuts(s, opts); len(cuts) != 0 {
+		nodes = UtilSplitStrings(nodes, cuts)
+	}
+
+	// Since maps in Go are unsorted, we're going to have to make
+	// a slice of keys, then iterate over this and just use the index
+	// from the map.
+	keys := make([]uint64, 0, len(nodes))
+	for k := range nodes {
+		keys = append(keys, k)
+	}
+
+	err := zermelo.Sort(keys)
+	if err != nil {
+		return nil
+	}
+
+	keys = UtilUniqueSlice(keys)
+
+	for _, off := range keys {
+		raw := nodes[off]
+		if utilIsCovered(covered, off, len(raw)) {
+			continue
+		}
+
+		records = append(records, r.readerWithUnwrapped(raw, UtilFilterString(raw, opts), func(f *FilteredString) StringRecord {
+			return r.readerRecord(s, off, raw, f)
+		}, opts)...)
+	}
+
+	// the prefixed strings are found apart, so are put in their place
+	if prefixed != 0 {
+		sort.SliceStable(records, func(i, j int) bool {
+			return records[i].Offset < records[j].Offset
+		})
+	}
+
+	return records
+}
+
+// UtilLimitRecords will order the records by where they are in the file
+// when more than one encoding has been extracted, and then cut them down
+// to the maximum amount of strings in opts
+func UtilLimitRecords(records []StringRecord, opts *Options) []StringRecord {
+	if len(opts.encodings()) > 1 {
+		sort.SliceStable(records, func(i, j int) bool {
+			return records[i].FileOffset < records[j].FileOffset
+		})
+	}
+
+	if opts.MaxCount != 0 && uint64(len(records)) > opts.MaxCount {
+		records = records[:opts.MaxCount]
+	}
+
+	return records
+}
+
+// FilteredString is a string which has passed the filters in Options
+type FilteredString struct {
+	// Text is the decoded text after the transforms have been applied
+	Text string
+	// Encoding is the encoding the string was found or detected in
+	Encoding Encoding
+	// Score is the readability score of the decoded text
+	Score Score
+	// IOCs are the indicators of compromise within the decoded text
+	IOCs []IOC
+	// Tags are the categories the decoded text falls into
+	Tags []Tag
+	// Language is the language whose mangling the text was demangled
+	// from, or that of the first symbol demangled within it, LanguageNone
+	// when it was not demangled
+	Language Language
+	// Mangled are the symbols which were demangled, the string itself or
+	// those found within it
+	Mangled []MangledToken
+}
+
+// UtilFilterWide will run the wide string through the filters in opts,
+// the minimum length is measured in code points rather than bytes. nil
+// is returned if the string should not be kept
+func UtilFilterWide(w *WideString, enc Encoding, opts *Options) *FilteredString {
+	minLength := opts.MinLength
+	if minLength == 0 {
+		minLength = ScanMinLength
+	}
+
+	if w.Length < minLength {
+		return nil
+	}
+
+	f := UtilFilterString([]byte(w.Text), opts)
+	if f != nil {
+		f.Encoding = enc
+	}
+
+	return f
+}
+
+// UtilFilterString will decode the raw string and run it through the
+// filters in opts, returning the transformed text along with the encoding
+// that it was detected in and its readability score, or nil if the string
+// should not be kept. The minimum length is measured in characters
+// rather than bytes
+func UtilFilterString(raw []byte, opts *Options) *FilteredString {
+	str, enc := UtilDecodeString(raw, opts.Legacy)
+	if uint64(utf8.RuneCountInString(str)) < opts.MinLength {
+		return nil
+	}
+
+	if !opts.NoHuman {
+		if !UtilIsNice(str) {
+			return nil
+		}
+	}
+
+	str = strings.TrimSpace(str)
+
+	// a string with a valid indicator in it is always of interest,
+	// however unlikely its score makes it look
+	score := UtilScore(str)
+	iocs := UtilFindIOCs(str)
+	if !opts.NoHuman && score.Total < opts.MinScore && iocs == nil {
+		return nil
+	}
+
+	tags := UtilTags(str, iocs)
+	if opts.Tags != nil && !UtilHasAnyTag(tags, opts.Tags) {
+		return nil
+	}
+
+	if !opts.NoTrim {
+		bad := []string{"\n", "\r"}
+		for _, char := range bad {
+			str = strings.Replace(str, char, "", -1)
+		}
+	}
+
+	var lang Language
+	var mangled []MangledToken
+	if opts.Demangle {
+		// a symbol which demangles to itself is left as it is, without
+		// a language, as nothing was demangled
+		demangled, detected, err := UtilDemangleSymbol(str, &opts.Demangling)
+		if err == nil && demangled != str {
+			mangled = []MangledToken{{Mangled: str, Demangled: demangled, Language: detected}}
+			str, lang = demangled, detected
+		} else if err != nil {
+			if str, mangled = UtilDemangleTokens(str, &opts.Demangling); mangled != nil {
+				lang = mangled[0].Language
+			}
+		}
+	}
+
+	if opts.Hex {
+		str = UtilConvHex(str)
+	}
+
+	return &FilteredString{Text: str, Encoding: enc, Score: score, IOCs: iocs, Tags: tags, Language: lang, Mangled: mangled}
+}
+
+// readerNextID will return the ID of the next record that is created
+func (r *ElfReader) readerNextID() uint64 {
+	r.lastID++
+	return r.lastID
+}
+
+// readerRecord will create the record for a string at the offset
+// in the section, resolving where it resides in the file and memory
+func (r *ElfReader) readerRecord(s *elf.Section, off uint64, raw []byte, f *FilteredString) StringRecord {
+	rec := StringRecord{
+		ID:         r.readerNextID(),
+		Section:    s.Name,
+		Offset:     off,
+		FileOffset: s.Offset + off,
+		Raw:        raw,
+		Text:       f.Text,
+		Encoding:   f.Encoding,
+		Score:      f.Score,
+		IOCs:       f.IOCs,
+		Tags:       f.Tags,
+		Language:   f.Language,
+		Mangled:    f.Mangled,
+	}
+
+	// relocatable objects have no addresses until they are linked
+	if r.ExecReader.Type == elf.ET_REL {
+		return rec
+	}
+
+	rec.Segment = r.ReaderSegmentAt(rec.FileOffset)
+	if s.Flags&elf.SHF_ALLOC != 0 {
+		rec.Address = s.Addr + off
+	} else if rec.Segment != nil {
+		rec.Address, _ = r.ReaderAddressOf(rec.FileOffset)
+	}
+
+	return rec
+}
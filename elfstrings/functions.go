@@ -0,0 +1,210 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"fmt"
+	"sort"
+)
+
+// function is a function of the binary and where it lies in memory
+type function struct {
+	name string
+	addr uint64
+	size uint64
+}
+
+// FunctionStrings is a function along with the strings that it references
+type FunctionStrings struct {
+	// Name is the symbol of the function, or sub_ and its address when
+	// it was only found from the exception frames. It is empty for the
+	// strings referenced from outside of any known function
+	Name    string
+	Address uint64
+	Size    uint64
+	Records []StringRecord
+}
+
+// readerFunctions will build the sorted list of functions from the
+// symbol tables, the static symbols are preferred as they also name
+// the functions which are not exported. The functions in the exception
+// frames that no symbol covers are added with a name made from their
+// address, which is all there is in a stripped binary
+func (r *ElfReader) readerFunctions() []function {
+	if r.functions != nil {
+		return r.functions
+	}
+
+	r.functions = []function{}
+
+	seen := make(map[uint64]bool)
+	for _, load := range []func() ([]elf.Symbol, error){r.ExecReader.Symbols, r.ExecReader.DynamicSymbols} {
+		syms, err := load()
+		if err != nil {
+			continue
+		}
+
+		for _, sym := range syms {
+			if elf.ST_TYPE(sym.Info) != elf.STT_FUNC || sym.Value == 0 || seen[sym.Value] {
+				continue
+			}
+
+			// the low bit of a function on ARM marks it as Thumb
+			addr := sym.Value
+			if r.ExecReader.Machine == elf.EM_ARM {
+				addr &^= 1
+			}
+
+			seen[sym.Value] = true
+			r.functions = append(r.functions, function{name: sym.Name, addr: addr, size: sym.Size})
+		}
+	}
+
+	utilSortFunctions(r.functions)
+
+	frames := r.readerEHFrameFunctions()
+
+	// the symbols of assembly functions often have no size, which
+	// the frames give them before anything is looked up
+	for _, fn := range frames {
+		if i, ok := utilFunctionIndex(r.functions, fn.addr); ok {
+			if sym := &r.functions[i]; sym.addr == fn.addr && sym.size == 0 {
+				sym.size = fn.size
+			}
+		}
+	}
+
+	var found []function
+	for _, fn := range frames {
+		if _, ok := utilFunctionIndex(r.functions, fn.addr); !ok {
+			fn.name = UtilSyntheticName(fn.addr)
+			found = append(found, fn)
+		}
+	}
+
+	r.functions = append(r.functions, found...)
+	utilSortFunctions(r.functions)
+
+	return r.functions
+}
+
+// ReaderFunctionAt will find the name of the function containing the
+// address, or an empty string if there is no symbol for it
+func (r *ElfReader) ReaderFunctionAt(addr uint64) string {
+	funcs := r.readerFunctions()
+
+	i, ok := utilFunctionIndex(funcs, addr)
+	if !ok {
+		return ""
+	}
+
+	return funcs[i].name
+}
+
+// ReaderFunctionStart will find the address of the function containing
+// the address, false if it is not within any known function
+func (r *ElfReader) ReaderFunctionStart(addr uint64) (uint64, bool) {
+	funcs := r.readerFunctions()
+
+	i, ok := utilFunctionIndex(funcs, addr)
+	if !ok {
+		return 0, false
+	}
+
+	return funcs[i].addr, true
+}
+
+// ReaderGroupByFunction will group the records under the functions which
+// reference or build them, in the order of the functions in memory. A
+// record that is referenced from more than one function is listed under
+// each of them and the records which nothing references are left out
+func (r *ElfReader) ReaderGroupByFunction(records []StringRecord) []FunctionStrings {
+	funcs := r.readerFunctions()
+
+	groups := make(map[uint64]*FunctionStrings)
+	var outside *FunctionStrings
+
+	for _, rec := range records {
+		added := make(map[*FunctionStrings]bool)
+
+		// the strings built by the code are in the function building them
+		var from []uint64
+		if rec.Source != SourceSection {
+			from = append(from, rec.Address)
+		}
+
+		for _, xref := range r.ReaderXrefsTo(rec.Address, uint64(len(rec.Raw))) {
+			from = append(from, xref.Address)
+		}
+
+		for _, addr := range from {
+			i, ok := utilFunctionIndex(funcs, addr)
+
+			var group *FunctionStrings
+			switch {
+			case ok:
+				fn := funcs[i]
+				if group = groups[fn.addr]; group == nil {
+					group = &FunctionStrings{Name: fn.name, Address: fn.addr, Size: fn.size}
+					groups[fn.addr] = group
+				}
+			case outside == nil:
+				outside = &FunctionStrings{}
+				group = outside
+			default:
+				group = outside
+			}
+
+			if !added[group] {
+				added[group] = true
+				group.Records = append(group.Records, rec)
+			}
+		}
+	}
+
+	var result []FunctionStrings
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Address < result[j].Address
+	})
+
+	// the references from outside of any function come last
+	if outside != nil {
+		result = append(result, *outside)
+	}
+
+	return result
+}
+
+// UtilSyntheticName will name a function that has no symbol by its address
+func UtilSyntheticName(addr uint64) string {
+	return fmt.Sprintf("sub_%x", addr)
+}
+
+// utilFunctionIndex will find the index of the function containing the
+// address in the sorted functions
+func utilFunctionIndex(funcs []function, addr uint64) (int, bool) {
+	i := sort.Search(len(funcs), func(i int) bool {
+		return funcs[i].addr > addr
+	})
+
+	if i == 0 {
+		return 0, false
+	}
+
+	fn := funcs[i-1]
+	if fn.size != 0 && addr >= fn.addr+fn.size {
+		return 0, false
+	}
+
+	return i - 1, true
+}
+
+// utilSortFunctions will sort the functions by their address
+func utilSortFunctions(funcs []function) {
+	sort.Slice(funcs, func(i, j int) bool {
+		return funcs[i].addr < funcs[j].addr
+	})
+}
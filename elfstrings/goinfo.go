@@ -0,0 +1,339 @@
+package elfstrings
+
+import (
+	"bytes"
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/gosym"
+	"encoding/binary"
+	"errors"
+	"runtime/debug"
+	"sort"
+)
+
+// goBuildInfoMagic starts the build information of a Go binary
+var goBuildInfoMagic = []byte("\xff Go buildinf:")
+
+// goPclntabMagics are the magic numbers which start the pclntab of each
+// version of its layout, from Go 1.2 to Go 1.20 onwards
+var goPclntabMagics = []uint32{0xfffffffb, 0xfffffffa, 0xfffffff0, 0xfffffff1}
+
+// ReaderIsGo will check whether the binary was built by Go, which keeps its
+// build information, its build ID and the table of its functions in their
+// own sections
+func (r *ElfReader) ReaderIsGo() bool {
+	for _, name := range []string{".go.buildinfo", ".gopclntab", ".note.go.buildid"} {
+		if r.ExecReader.Section(name) != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GoModule is a module that a Go binary was built from
+type GoModule struct {
+	Path    string
+	Version string
+	// Sum is the checksum of the module, empty for the main module
+	Sum string
+	// Replace is the module that this one was replaced by, nil when it
+	// was not replaced
+	Replace *GoModule
+}
+
+// GoSetting is a setting that a Go binary was built with, such as
+// -ldflags, CGO_ENABLED or vcs.revision
+type GoSetting struct {
+	Key   string
+	Value string
+}
+
+// GoInfo is what a Go binary records about how it was built, from its
+// build information, and about its code, from its pclntab
+type GoInfo struct {
+	// Version is the version of Go which built the binary, empty when
+	// there is no build information
+	Version string
+	// Path is the path of the main package
+	Path string
+	// Main is the module of the main package
+	Main GoModule
+	// Deps are the modules that the main module depends on
+	Deps []GoModule
+	// Settings are the settings of the build
+	Settings []GoSetting
+	// Functions are the names of the functions, in the order of their code
+	Functions []string
+	// Files are the paths of the source files, in ascending order
+	Files []string
+}
+
+// ReaderGoInfo will read the build information of a Go binary, the version
+// of Go, its modules and build settings, along with the names of its
+// functions and source files from the pclntab. Both are found from their
+// sections, or from their magic numbers when the section headers are gone,
+// and both are kept when the binary is stripped of its symbols
+func (r *ElfReader) ReaderGoInfo() (*GoInfo, error) {
+	info := &GoInfo{}
+
+	build, buildErr := r.readerBuildInfo()
+	if buildErr == nil {
+		info.Version = build.GoVersion
+		info.Path = build.Path
+		info.Main = utilGoModule(&build.Main)
+
+		for _, dep := range build.Deps {
+			info.Deps = append(info.Deps, utilGoModule(dep))
+		}
+
+		for _, setting := range build.Settings {
+			info.Settings = append(info.Settings, GoSetting{Key: setting.Key, Value: setting.Value})
+		}
+	}
+
+	tableErr := r.readerGoTable(info)
+	if buildErr != nil && tableErr != nil {
+		return nil, errors.New("no Go build information or pclntab found")
+	}
+
+	return info, nil
+}
+
+// utilGoModule will convert a module of the build information, along with
+// the module that replaced it
+func utilGoModule(m *debug.Module) GoModule {
+	mod := GoModule{Path: m.Path, Version: m.Version, Sum: m.Sum}
+
+	if m.Replace != nil {
+		replaced := utilGoModule(m.Replace)
+		mod.Replace = &replaced
+	}
+
+	return mod
+}
+
+// readerBuildInfo will read the build information of the binary from its
+// .go.buildinfo section, or without the section headers from the loaded
+// segments where its header is found. Only the layout of Go 1.18 onwards
+// is read from the segments, where the version and the modules follow the
+// header rather than being pointed to
+func (r *ElfReader) readerBuildInfo() (*debug.BuildInfo, error) {
+	build, err := buildinfo.Read(r.File)
+	if err == nil {
+		return build, nil
+	}
+
+	for _, prog := range r.ExecReader.Progs {
+		if prog.Type != elf.PT_LOAD {
+			continue
+		}
+
+		buf, readErr := r.ReaderReadAt(prog.Off, prog.Filesz)
+		if readErr != nil {
+			continue
+		}
+
+		// the header is aligned to 16 bytes
+		for off := 0; ; off += 16 {
+			i := bytes.Index(buf[off:], goBuildInfoMagic)
+			if i < 0 {
+				break
+			}
+
+			off += i &^ 15
+			if i%16 != 0 {
+				continue
+			}
+
+			if found, parseErr := utilParseBuildInfo(buf[off:]); parseErr == nil {
+				return found, nil
+			}
+		}
+	}
+
+	return nil, err
+}
+
+// utilParseBuildInfo will parse the build information at the start of the
+// buffer, a 32 byte header followed by the version of Go and the modules,
+// each after its length as a varint. The modules are wrapped in 16 bytes
+// which mark them out in the binary
+func utilParseBuildInfo(buf []byte) (*debug.BuildInfo, error) {
+	if len(buf) < 32 || buf[15]&2 == 0 {
+		return nil, errors.New("unsupported build information")
+	}
+
+	buf = buf[32:]
+
+	var fields [2]string
+	for i := range fields {
+		size, n := binary.Uvarint(buf)
+		if n <= 0 || size > uint64(len(buf)-n) {
+			return nil, errors.New("truncated build information")
+		}
+
+		fields[i] = string(buf[n : n+int(size)])
+		buf = buf[n+int(size):]
+	}
+
+	version, mod := fields[0], fields[1]
+	if len(mod) >= 33 && mod[len(mod)-17] == '\n' {
+		mod = mod[16 : len(mod)-16]
+	}
+
+	build, err := debug.ParseBuildInfo(mod)
+	if err != nil {
+		return nil, err
+	}
+
+	build.GoVersion = version
+
+	return build, nil
+}
+
+// readerGoTable will fill in the functions and source files of the info
+// from the pclntab of the binary
+func (r *ElfReader) readerGoTable(info *GoInfo) error {
+	data, text := r.readerPclntab()
+	if data == nil {
+		return errors.New("no pclntab found")
+	}
+
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(data, text))
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for _, fn := range table.Funcs {
+		if !seen[fn.Name] {
+			seen[fn.Name] = true
+			info.Functions = append(info.Functions, fn.Name)
+		}
+	}
+
+	for file := range table.Files {
+		info.Files = append(info.Files, file)
+	}
+
+	sort.Strings(info.Files)
+
+	return nil
+}
+
+// readerPclntab will return the pclntab of the binary along with the start
+// of its code, which older layouts of the pclntab are relative to. Without
+// the .gopclntab section the loaded segments are searched for its header
+func (r *ElfReader) readerPclntab() ([]byte, uint64) {
+	var text uint64
+	if s := r.ExecReader.Section(".text"); s != nil {
+		text = s.Addr
+	}
+
+	if s := r.ExecReader.Section(".gopclntab"); s != nil {
+		data, err := s.Data()
+		if err == nil {
+			return data, text
+		}
+	}
+
+	for _, prog := range r.ExecReader.Progs {
+		if prog.Type != elf.PT_LOAD {
+			continue
+		}
+
+		if text == 0 && prog.Flags&elf.PF_X != 0 {
+			text = prog.Vaddr
+		}
+
+		buf, err := r.ReaderReadAt(prog.Off, prog.Filesz)
+		if err != nil {
+			continue
+		}
+
+		if off := utilFindPclntab(buf, r.ExecReader.ByteOrder); off >= 0 {
+			return buf[off:], text
+		}
+	}
+
+	return nil, 0
+}
+
+// utilFindPclntab will return the offset of the header of the pclntab in
+// the buffer, -1 when there is none. The header is the magic number, two
+// zero bytes, the quantum of the instructions and the size of a pointer
+func utilFindPclntab(buf []byte, order binary.ByteOrder) int {
+	for _, magic := range goPclntabMagics {
+		var want [4]byte
+		order.PutUint32(want[:], magic)
+
+		for base := 0; ; {
+			i := bytes.Index(buf[base:], want[:])
+			if i < 0 {
+				break
+			}
+
+			off := base + i
+			base = off + 1
+
+			// the header is aligned to the size of a pointer
+			if off%4 != 0 || off+8 > len(buf) || buf[off+4] != 0 || buf[off+5] != 0 {
+				continue
+			}
+
+			quantum, ptrSize := buf[off+6], buf[off+7]
+			if quantum != 1 && quantum != 2 && quantum != 4 || ptrSize != 4 && ptrSize != 8 {
+				continue
+			}
+
+			if utilIsPclntab(buf[off:], magic, int(ptrSize), order) {
+				return off
+			}
+		}
+	}
+
+	return -1
+}
+
+// utilIsPclntab will check that the words after the header of the pclntab
+// fit within it, the amount of functions in the oldest layout and the
+// ascending offsets of its tables in the others, so that a magic number
+// found by chance is not taken to be the start of one
+func utilIsPclntab(buf []byte, magic uint32, ptrSize int, order binary.ByteOrder) bool {
+	words := 8
+	if magic == 0xfffffffb {
+		words = 1
+	} else if magic == 0xfffffffa {
+		words = 7
+	}
+
+	if 8+words*ptrSize > len(buf) {
+		return false
+	}
+
+	word := func(i int) uint64 {
+		if ptrSize == 8 {
+			return order.Uint64(buf[8+i*8:])
+		}
+
+		return uint64(order.Uint32(buf[8+i*4:]))
+	}
+
+	size := uint64(len(buf))
+	if words == 1 {
+		return word(0) != 0 && word(0) < size && 8+(2*word(0)+1)*uint64(ptrSize)+4 <= size
+	}
+
+	// the offsets of the names, units, files, pc tables and functions
+	prev := uint64(0)
+	for i := words - 5; i < words; i++ {
+		if word(i) <= prev || word(i) >= size {
+			return false
+		}
+
+		prev = word(i)
+	}
+
+	return word(0) != 0
+}
@@ -0,0 +1,297 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"sort"
+	"unicode/utf8"
+
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// headerMaxLength is the longest literal that a string header is taken to
+// describe, a length any longer is more likely to be something else
+const headerMaxLength = 0x10000
+
+// headerPairWindow is how many instructions after the address of a literal
+// is loaded that its length is looked for, the compiler moves the length
+// into the next register or the next word of the header just after
+const headerPairWindow = 3
+
+// ReaderStringHeaders will find the string headers of a Go or Rust binary,
+// the pairs of the address of a literal and its length. They are read from
+// the words of the data sections, and from the code which loads the address
+// of a literal and then its length on x86-64 and AArch64. Only the headers
+// of text are kept, returned as a map of the address to each length it is
+// given, as a literal which is the prefix of another shares its address
+func (r *ElfReader) ReaderStringHeaders() map[uint64][]uint64 {
+	if r.headers != nil {
+		return r.headers
+	}
+
+	headers := make(map[uint64][]uint64)
+	r.headers = headers
+
+	if r.ExecReader.Type == elf.ET_REL {
+		return headers
+	}
+
+	var data []*elf.Section
+	for _, s := range r.ExecReader.Sections {
+		if s.Type == elf.SHT_PROGBITS && s.Flags&elf.SHF_ALLOC != 0 && s.Flags&elf.SHF_EXECINSTR == 0 && s.Addr != 0 {
+			data = append(data, s)
+		}
+	}
+
+	contents := make(map[*elf.Section][]byte)
+	read := func(s *elf.Section) []byte {
+		buf, ok := contents[s]
+		if !ok {
+			buf, _ = r.ReaderReadAt(s.Offset, s.Size)
+			contents[s] = buf
+		}
+
+		return buf
+	}
+
+	add := func(ptr uint64, size uint64) {
+		if size == 0 || size > headerMaxLength {
+			return
+		}
+
+		for _, known := range headers[ptr] {
+			if known == size {
+				return
+			}
+		}
+
+		for _, s := range data {
+			if ptr < s.Addr || ptr+size > s.Addr+s.Size {
+				continue
+			}
+
+			buf := read(s)
+			off := ptr - s.Addr
+			if off+size <= uint64(len(buf)) && utilIsLiteralText(buf[off:off+size]) {
+				headers[ptr] = append(headers[ptr], size)
+			}
+
+			return
+		}
+	}
+
+	mem := r.readerMemory()
+
+	word := uint64(4)
+	if r.ExecReader.Class == elf.ELFCLASS64 {
+		word = 8
+	}
+
+	for _, s := range data {
+		utilHeadersData(read(s), s.Addr, word, mem, add)
+	}
+
+	for _, s := range r.ExecReader.Sections {
+		if s.Type != elf.SHT_PROGBITS || s.Flags&elf.SHF_EXECINSTR == 0 {
+			continue
+		}
+
+		code, err := r.ReaderReadAt(s.Offset, s.Size)
+		if err != nil {
+			continue
+		}
+
+		switch r.ExecReader.Machine {
+		case elf.EM_X86_64:
+			utilHeadersX86(code, s.Addr, add)
+		case elf.EM_AARCH64:
+			utilHeadersARM64(code, s.Addr, add)
+		}
+	}
+
+	return headers
+}
+
+// utilHeadersData will pass each pair of aligned words in the section at
+// addr to add, the first word as the address of a literal and the second as
+// its length. In position independent binaries the address is what the
+// relocation of the word holds
+func utilHeadersData(buf []byte, addr uint64, word uint64, mem *memory, add func(ptr uint64, size uint64)) {
+	load := func(off uint64) uint64 {
+		if word == 8 {
+			return mem.order.Uint64(buf[off:])
+		}
+
+		return uint64(mem.order.Uint32(buf[off:]))
+	}
+
+	for off := uint64(0); off+2*word <= uint64(len(buf)); off += word {
+		ptr, ok := mem.relocs[addr+off]
+		if !ok {
+			ptr = load(off)
+		}
+
+		if ptr != 0 {
+			add(ptr, load(off+word))
+		}
+	}
+}
+
+// utilHeadersX86 will decode the x86-64 code at addr with a linear sweep,
+// and pass the address of each literal loaded by a RIP relative lea to add
+// along with the immediate which is moved just after it as its length
+func utilHeadersX86(code []byte, addr uint64, add func(ptr uint64, size uint64)) {
+	var ptr uint64
+
+	since := headerPairWindow
+	for off := 0; off < len(code); {
+		inst, err := x86asm.Decode(code[off:], 64)
+		if err != nil || inst.Len == 0 {
+			off++
+			continue
+		}
+
+		next := addr + uint64(off) + uint64(inst.Len)
+		since++
+
+		switch inst.Op {
+		case x86asm.LEA:
+			// the displacement is decoded without its sign, which matters
+			// when the literals are before the code as they are in PIE
+			if m, ok := inst.Args[1].(x86asm.Mem); ok && m.Base == x86asm.RIP {
+				ptr, since = next+uint64(int64(int32(m.Disp))), 0
+			}
+		case x86asm.MOV:
+			if imm, ok := inst.Args[1].(x86asm.Imm); ok && since <= headerPairWindow && imm > 0 {
+				add(ptr, uint64(imm))
+				since = headerPairWindow
+			}
+		}
+
+		off += inst.Len
+	}
+}
+
+// utilHeadersARM64 will decode the AArch64 code at addr, and pass the
+// address of each literal built by an adrp and an add to add, along with the
+// immediate which is moved by a movz or an orr just after it as its length
+func utilHeadersARM64(code []byte, addr uint64, add func(ptr uint64, size uint64)) {
+	var regs pairs
+	var ptr uint64
+
+	since := headerPairWindow
+	for off := 0; off+4 <= len(code); off += 4 {
+		w := binary.LittleEndian.Uint32(code[off:])
+		pc := addr + uint64(off)
+		rd := w & 31
+		rn := (w >> 5) & 31
+		since++
+
+		switch {
+		case w&0x9f000000 == 0x90000000:
+			// adrp xd, page
+			imm := utilSignExtend(uint64((w>>5)&0x7ffff)<<2|uint64((w>>29)&3), 21)
+			regs.set(rd, (pc&^0xfff)+imm<<12, pc)
+			continue
+		case w&0x7f800000 == 0x11000000:
+			// add xd, xn, #imm
+			if base, ok := regs.get(rn, pc); ok && w&(1<<22) == 0 {
+				ptr, since = base+uint64((w>>10)&0xfff), 0
+			}
+		case w&0x7fe00000 == 0x52800000:
+			// movz xd, #imm without a shift
+			if imm := uint64((w >> 5) & 0xffff); since <= headerPairWindow && imm > 0 {
+				add(ptr, imm)
+				since = headerPairWindow
+			}
+		case w&0x7f800000 == 0x32000000 && rn == 31:
+			// orr xd, xzr, #imm which moves the bitmask immediates
+			imm, ok := utilBitMask((w>>22)&1, (w>>16)&0x3f, (w>>10)&0x3f, w&(1<<31) != 0)
+			if ok && since <= headerPairWindow {
+				add(ptr, imm)
+				since = headerPairWindow
+			}
+		}
+
+		regs.clear(rd)
+	}
+}
+
+// readerLiteralCuts will return the offsets within the section where the
+// string literals of a Go or Rust binary start and end, in ascending order.
+// nil is returned when the binary was built by neither, or the literals are
+// not to be split
+func (r *ElfReader) readerLiteralCuts(s *elf.Section, opts *Options) []uint64 {
+	var cuts []uint64
+
+	if opts.NoSplit || s.Flags&elf.SHF_ALLOC == 0 {
+		return nil
+	}
+
+	if r.packed == nil {
+		packed := r.ReaderIsGo() || r.ReaderIsRust()
+		r.packed = &packed
+	}
+
+	if !*r.packed {
+		return nil
+	}
+
+	// every length is cut at, a literal which is the prefix of another
+	// ends where the other goes on
+	for ptr, sizes := range r.ReaderStringHeaders() {
+		for _, size := range sizes {
+			if ptr >= s.Addr && ptr+size <= s.Addr+s.Size {
+				cuts = append(cuts, ptr-s.Addr, ptr+size-s.Addr)
+			}
+		}
+	}
+
+	sort.Slice(cuts, func(i, j int) bool {
+		return cuts[i] < cuts[j]
+	})
+
+	return UtilUniqueSlice(cuts)
+}
+
+// utilIsLiteralText will check that the literal is text, which unlike the
+// strings prefixed by their length may hold line breaks, as the literals
+// printed by Go and Rust so often end in one
+func utilIsLiteralText(raw []byte) bool {
+	if !utf8.Valid(raw) {
+		return false
+	}
+
+	for _, b := range raw {
+		if !UtilIsPrintable(b) && b != '\n' && b != '\r' && b < utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+// UtilSplitStrings will split the strings, keyed by their offsets, at each
+// of the offsets in cuts which falls within them. cuts must be in ascending
+// order, as they are from the string headers of Go and Rust binaries
+func UtilSplitStrings(nodes map[uint64][]byte, cuts []uint64) map[uint64][]byte {
+	split := make(map[uint64][]byte, len(nodes))
+
+	for off, raw := range nodes {
+		end := off + uint64(len(raw))
+
+		i := sort.Search(len(cuts), func(i int) bool {
+			return cuts[i] > off
+		})
+
+		start := off
+		for ; i < len(cuts) && cuts[i] < end; i++ {
+			split[start] = raw[start-off : cuts[i]-off]
+			start = cuts[i]
+		}
+
+		split[start] = raw[start-off:]
+	}
+
+	return split
+}
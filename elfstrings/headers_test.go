@@ -0,0 +1,102 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestLiteralCuts(t *testing.T) {
+	const (
+		text   = 0x401000
+		rodata = 0x402000
+		data   = 0x403000
+	)
+
+	// literal is laid out back to back with the others, as Go does, with
+	// the lengths that the code and the data give it in their headers
+	type literal struct {
+		text string
+		code []int
+		data []int
+	}
+
+	tests := []struct {
+		name     string
+		isGo     bool
+		literals []literal
+		want     []string
+	}{
+		{"code headers", true, []literal{
+			{text: "connecting to", code: []int{13}},
+			{text: "the server", code: []int{10}},
+		}, []string{"connecting to", "the server"}},
+		{"data headers", true, []literal{
+			{text: "connecting to", data: []int{13}},
+			{text: "the server", data: []int{10}},
+		}, []string{"connecting to", "the server"}},
+		{"line breaks", true, []literal{
+			{text: "fips140: verified code+data\n", code: []int{28}},
+			{text: "[+] panic locations: %d\n", code: []int{24}},
+		}, []string{"fips140: verified code+data", "[+] panic locations: %d"}},
+		{"unreferenced neighbour", true, []literal{
+			{text: "fips140: verified code+data\n", code: []int{28}},
+			{text: "nothing loads this"},
+			{text: "invalid section header", data: []int{22}},
+		}, []string{"fips140: verified code+data", "nothing loads this", "invalid section header"}},
+		{"every length", true, []literal{
+			{text: "runtime: panicking", code: []int{9, 18}},
+			{text: "unexpected signal"},
+		}, []string{"runtime:", "panicking", "unexpected signal"}},
+		{"neither Go nor Rust", false, []literal{
+			{text: "connecting to", code: []int{13}},
+			{text: "the server", code: []int{10}},
+		}, []string{"connecting tothe server"}},
+	}
+
+	for _, tt := range tests {
+		var strs, code, headers []byte
+		for _, lit := range tt.literals {
+			ptr := uint64(rodata + len(strs))
+			strs = append(strs, lit.text...)
+
+			// lea rax, [rip+literal]; mov ebx, length
+			for _, size := range lit.code {
+				pc := uint64(text + len(code))
+				inst := []byte{0x48, 0x8d, 0x05, 0, 0, 0, 0, 0xbb, 0, 0, 0, 0}
+				binary.LittleEndian.PutUint32(inst[3:], uint32(ptr-(pc+7)))
+				binary.LittleEndian.PutUint32(inst[8:], uint32(size))
+				code = append(code, inst...)
+			}
+
+			for _, size := range lit.data {
+				headers = append(headers, testWords(ptr, uint64(size))...)
+			}
+		}
+
+		code = append(code, 0xc3)
+		strs = append(strs, 0)
+
+		sections := []testSection{
+			{name: ".text", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC | elf.SHF_EXECINSTR, addr: text, data: code},
+			{name: ".rodata", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC, addr: rodata, data: strs},
+			{name: ".data.rel.ro", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC | elf.SHF_WRITE, addr: data, data: append(headers, make([]byte, 16)...)},
+		}
+
+		if tt.isGo {
+			sections = append(sections, testSection{name: ".note.go.buildid", typ: elf.SHT_NOTE, data: make([]byte, 16)})
+		}
+
+		r := testELF(t, sections, nil)
+
+		var got []string
+		for _, rec := range r.ReaderExtract(r.ExecReader.Section(".rodata"), &Options{}) {
+			got = append(got, rec.Text)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: extracted %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,776 @@
+package elfstrings
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// IOCType to emulate an enum of the kinds of indicator of compromise
+type IOCType int32
+
+// Types of indicators which are extracted from strings
+const (
+	IOCURL IOCType = iota
+	IOCIPv4
+	IOCIPv6
+	IOCDomain
+	IOCEmail
+	IOCPort
+	IOCOnion
+	IOCBitcoin
+	IOCEthereum
+	IOCMonero
+	iocEnd
+)
+
+var iocNames = map[IOCType]string{
+	IOCURL:      "url",
+	IOCIPv4:     "ipv4",
+	IOCIPv6:     "ipv6",
+	IOCDomain:   "domain",
+	IOCEmail:    "email",
+	IOCPort:     "port",
+	IOCOnion:    "onion",
+	IOCBitcoin:  "bitcoin",
+	IOCEthereum: "ethereum",
+	IOCMonero:   "monero",
+}
+
+// String will return the name of the indicator type
+func (t IOCType) String() string {
+	if name, ok := iocNames[t]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("ioc(%d)", int32(t))
+}
+
+// IOC is an indicator of compromise found within a string
+type IOC struct {
+	Type IOCType
+	// Value is the indicator, with any defanging undone
+	Value string
+	// Defanged is set when the indicator was written in a defanged
+	// form such as hxxp://example[.]com
+	Defanged bool
+}
+
+// Indicator is an indicator of compromise along with every
+// place in the binary that it was found
+type Indicator struct {
+	IOC
+	Sources []IndicatorSource
+}
+
+// IndicatorSource is where in the binary an indicator was found
+type IndicatorSource struct {
+	Section    string
+	Offset     uint64
+	FileOffset uint64
+}
+
+var (
+	urlRegex      = regexp.MustCompile(`(?i)\b(?:https?|ftps?|wss?|tcp|udp|ssh|sftp|smb|rtsp|irc|gopher|tftp)://[^\s"'<>\x60{}|\\^]+`)
+	emailRegex    = regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z][a-z0-9-]*[a-z0-9]\b`)
+	onionRegex    = regexp.MustCompile(`(?i)\b(?:[a-z2-7]{56}|[a-z2-7]{16})\.onion\b`)
+	ipv4Regex     = regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}(?::\d{1,5})?\b`)
+	ipv6Regex     = regexp.MustCompile(`(?i)\[[0-9a-f:.]+\](?::\d{1,5})?|[0-9a-f]{0,4}(?::[0-9a-f]{0,4}){2,7}(?:\.\d{1,3}){0,3}`)
+	domainRegex   = regexp.MustCompile(`(?i)\b(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z][a-z0-9-]{0,61}[a-z0-9](?::\d{1,5})?\b`)
+	bitcoinRegex  = regexp.MustCompile(`\b[13][1-9A-HJ-NP-Za-km-z]{25,34}\b|(?i)\b(?:bc1|tb1)[02-9ac-hj-np-z]{11,71}\b`)
+	ethereumRegex = regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`)
+	ethContext    = regexp.MustCompile(`(?i)\b(?:eth|ether|ethereum|erc-?20|wallet|address|addr)\b`)
+	moneroRegex   = regexp.MustCompile(`\b[48][1-9A-HJ-NP-Za-km-z]{94}\b`)
+)
+
+// refangRegexes undo the common ways of defanging indicators
+var refangRegexes = []struct {
+	regex *regexp.Regexp
+	with  string
+}{
+	{regexp.MustCompile(`(?i)\bhxxp`), "http"},
+	{regexp.MustCompile(`(?i)\bfxp`), "ftp"},
+	{regexp.MustCompile(`(?i)\[\.\]|\(\.\)|\{\.\}|\[dot\]|\(dot\)|\{dot\}`), "."},
+	{regexp.MustCompile(`(?i)\[:\]`), ":"},
+	{regexp.MustCompile(`(?i)\[://\]`), "://"},
+	{regexp.MustCompile(`(?i)\[/\]`), "/"},
+	{regexp.MustCompile(`(?i)\[@\]|\(@\)|\[at\]|\(at\)`), "@"},
+}
+
+// fileTLDs are the top level domains that are far more often the
+// extension of a file name, such as libc.so, than a domain
+var fileTLDs = map[string]bool{
+	"so": true, "sh": true, "py": true, "pl": true, "rs": true, "md": true,
+	"cc": true, "in": true, "zip": true, "mov": true, "ps": true, "am": true,
+	"ms": true, "mk": true, "lo": true, "la": true, "ml": true, "hs": true,
+	"cs": true, "rb": true, "sc": true, "st": true, "gl": true, "ai": true,
+	"do": true, "go": true, "ts": true, "java": true, "php": true, "tel": true,
+}
+
+// tlds is the set of top level domains, built from tldList when the
+// package is loaded so that it is only ever read afterwards
+var tlds = utilBuildTLDs()
+
+// utilBuildTLDs will build the set of top level domains from tldList
+func utilBuildTLDs() map[string]bool {
+	set := make(map[string]bool)
+	for _, tld := range strings.Fields(tldList) {
+		set[tld] = true
+	}
+
+	return set
+}
+
+// UtilIsTLD will check if the name is a real top level domain
+func UtilIsTLD(name string) bool {
+	return tlds[strings.ToLower(name)]
+}
+
+// UtilFindIOCs will find the indicators of compromise within the text,
+// including those which have been defanged, the same indicator is only
+// returned once and never also as a part of a larger indicator
+func UtilFindIOCs(text string) []IOC {
+	iocs := utilFindIOCs(text)
+
+	refanged := text
+	for _, refang := range refangRegexes {
+		refanged = refang.regex.ReplaceAllString(refanged, refang.with)
+	}
+
+	if refanged == text {
+		return iocs
+	}
+
+	seen := make(map[string]bool)
+	for _, ioc := range iocs {
+		seen[ioc.Type.String()+ioc.Value] = true
+	}
+
+	for _, ioc := range utilFindIOCs(refanged) {
+		if !seen[ioc.Type.String()+ioc.Value] {
+			ioc.Defanged = true
+			iocs = append(iocs, ioc)
+		}
+	}
+
+	return iocs
+}
+
+// utilFindIOCs will find the indicators in the text, each part of the
+// text is only claimed by the first expression which matches it
+func utilFindIOCs(text string) []IOC {
+	var iocs []IOC
+	var claimed [][2]int
+
+	seen := make(map[string]bool)
+
+	add := func(typ IOCType, value string) {
+		if !seen[typ.String()+value] {
+			seen[typ.String()+value] = true
+			iocs = append(iocs, IOC{Type: typ, Value: value})
+		}
+	}
+
+	free := func(loc []int) bool {
+		for _, c := range claimed {
+			if loc[0] < c[1] && loc[1] > c[0] {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	find := func(regex *regexp.Regexp, check func(match string, loc []int) bool) {
+		for _, loc := range regex.FindAllStringIndex(text, -1) {
+			if free(loc) && check(text[loc[0]:loc[1]], loc) {
+				claimed = append(claimed, [2]int{loc[0], loc[1]})
+			}
+		}
+	}
+
+	find(urlRegex, func(match string, loc []int) bool {
+		match = strings.TrimRight(match, ".,;:)]'!?")
+
+		u, err := url.Parse(match)
+		if err != nil || u.Host == "" || !utilValidHost(u.Hostname()) {
+			return false
+		}
+
+		add(IOCURL, match)
+		if u.Port() != "" {
+			add(IOCPort, u.Host)
+		}
+
+		return true
+	})
+
+	find(emailRegex, func(match string, loc []int) bool {
+		domain := match[strings.LastIndex(match, "@")+1:]
+		if !utilValidDomain(domain, true) {
+			return false
+		}
+
+		add(IOCEmail, match)
+
+		return true
+	})
+
+	find(onionRegex, func(match string, loc []int) bool {
+		add(IOCOnion, strings.ToLower(match))
+
+		return true
+	})
+
+	find(ipv4Regex, func(match string, loc []int) bool {
+		if utilIsVersionContext(text, loc) {
+			return false
+		}
+
+		host, port := utilSplitPort(match)
+		if !utilValidIPv4(host) || port == 0 && strings.Contains(match, ":") {
+			return false
+		}
+
+		add(IOCIPv4, host)
+		if port != 0 {
+			add(IOCPort, match)
+		}
+
+		return true
+	})
+
+	find(ipv6Regex, func(match string, loc []int) bool {
+		// identifiers such as std::move share the shape of an address,
+		// so it must not run on from a word or another colon
+		if loc[0] > 0 && utilIsWordByte(text[loc[0]-1]) || loc[1] < len(text) && utilIsWordByte(text[loc[1]]) {
+			return false
+		}
+
+		host, port := match, uint64(0)
+		if strings.HasPrefix(match, "[") {
+			end := strings.Index(match, "]")
+			host = match[1:end]
+			if end+1 < len(match) {
+				port, _ = strconv.ParseUint(match[end+2:], 10, 16)
+			}
+		}
+
+		if !utilValidIPv6(host) {
+			return false
+		}
+
+		add(IOCIPv6, strings.ToLower(host))
+		if port != 0 {
+			add(IOCPort, match)
+		}
+
+		return true
+	})
+
+	find(domainRegex, func(match string, loc []int) bool {
+		// paths and the members of structures are not domains
+		if loc[0] > 0 && strings.ContainsRune("/\\.@-_", rune(text[loc[0]-1])) {
+			return false
+		}
+
+		if loc[1] < len(text) && strings.ContainsRune("(_-", rune(text[loc[1]])) {
+			return false
+		}
+
+		host, port := utilSplitPort(match)
+		if port == 0 && strings.Contains(match, ":") {
+			return false
+		}
+
+		if !utilValidDomain(host, false) {
+			return false
+		}
+
+		add(IOCDomain, strings.ToLower(host))
+		if port != 0 {
+			add(IOCPort, match)
+		}
+
+		return true
+	})
+
+	find(bitcoinRegex, func(match string, loc []int) bool {
+		if !utilValidBitcoin(match) {
+			return false
+		}
+
+		add(IOCBitcoin, match)
+
+		return true
+	})
+
+	find(ethereumRegex, func(match string, loc []int) bool {
+		// any hash can be written as forty hex digits, so an address must
+		// either carry its checksum in the case of its letters or be
+		// written next to a word which says what it is
+		if !utilValidEthereum(match[2:]) && !ethContext.MatchString(text) {
+			return false
+		}
+
+		add(IOCEthereum, match)
+
+		return true
+	})
+
+	find(moneroRegex, func(match string, loc []int) bool {
+		if !utilValidMonero(match) {
+			return false
+		}
+
+		add(IOCMonero, match)
+
+		return true
+	})
+
+	return iocs
+}
+
+// UtilCollectIndicators will gather the indicators of every record,
+// merging those which were found more than once into a single
+// indicator along with each of the places it was found
+func UtilCollectIndicators(records []StringRecord) []Indicator {
+	var indicators []Indicator
+
+	index := make(map[string]int)
+
+	for i := range records {
+		rec := &records[i]
+
+		for _, ioc := range rec.IOCs {
+			key := ioc.Type.String() + strings.ToLower(ioc.Value)
+
+			n, ok := index[key]
+			if !ok {
+				n = len(indicators)
+				index[key] = n
+				indicators = append(indicators, Indicator{IOC: ioc})
+			}
+
+			indicators[n].Sources = append(indicators[n].Sources, IndicatorSource{
+				Section:    rec.Section,
+				Offset:     rec.Offset,
+				FileOffset: rec.FileOffset,
+			})
+		}
+	}
+
+	sort.SliceStable(indicators, func(i, j int) bool {
+		return indicators[i].Type < indicators[j].Type
+	})
+
+	return indicators
+}
+
+// utilIsVersionContext will check if the match is part of a version
+// number rather than an address, such as v1.2.3.4 or 1.2.3.4.5
+func utilIsVersionContext(text string, loc []int) bool {
+	before := strings.ToLower(text[:loc[0]])
+	after := text[loc[1]:]
+
+	if strings.HasSuffix(before, ".") || strings.HasPrefix(after, ".") && len(after) > 1 && unicode.IsDigit(rune(after[1])) {
+		return true
+	}
+
+	before = strings.TrimRight(before, " :=")
+	if strings.HasSuffix(before, "-") || strings.HasSuffix(before, "_") {
+		return true
+	}
+
+	// the word must stand alone, so that resolver is not read as ver
+	for _, word := range []string{"v", "ver", "version", "release", "build"} {
+		rest := strings.TrimSuffix(before, word)
+		if rest != before && (rest == "" || !unicode.IsLetter(rune(rest[len(rest)-1]))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// utilSplitPort will split a host:port pair, the port is zero
+// if there is not one or it is not a valid port
+func utilSplitPort(match string) (string, uint64) {
+	i := strings.LastIndex(match, ":")
+	if i == -1 {
+		return match, 0
+	}
+
+	port, err := strconv.ParseUint(match[i+1:], 10, 16)
+	if err != nil || port == 0 {
+		return match[:i], 0
+	}
+
+	return match[:i], port
+}
+
+// utilValidHost will check that the host of a URL is an address
+// or a domain with a real top level domain
+func utilValidHost(host string) bool {
+	if net.ParseIP(host) != nil || strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	return utilValidDomain(host, true)
+}
+
+// utilValidIPv4 will check that the dotted quad is an address that could
+// be routed, leading zeros are rejected as they are rarely written in one
+func utilValidIPv4(host string) bool {
+	parts := strings.Split(host, ".")
+	for _, part := range parts {
+		if len(part) > 1 && part[0] == '0' {
+			return false
+		}
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || parts[0] == "0" {
+		return false
+	}
+
+	// a run of small numbers is nearly always a version, other
+	// than well known resolvers such as 8.8.8.8 and 1.1.1.1
+	small, same := true, true
+	for _, part := range parts {
+		if len(part) > 1 {
+			small = false
+		}
+
+		if part != parts[0] {
+			same = false
+		}
+	}
+
+	return !small || same
+}
+
+// utilValidIPv6 will check that the text is an IPv6 address which has
+// the shape of a real one rather than a run of hex words and colons,
+// such as a::b. Every group must be written out unless the address is
+// in a block that is handed out, and it may not end with the zeros
+// that are left out, as that is a network rather than an address
+func utilValidIPv6(host string) bool {
+	ip := net.ParseIP(host)
+	if ip == nil || ip.To4() != nil && !strings.Contains(host, "::") {
+		return false
+	}
+
+	lower := strings.ToLower(host)
+	if lower == "::1" || strings.HasPrefix(lower, "::ffff:") && strings.Contains(lower, ".") {
+		return true
+	}
+
+	if !strings.Contains(host, "::") {
+		return len(strings.Split(host, ":")) == 8
+	}
+
+	if strings.HasSuffix(host, "::") {
+		return false
+	}
+
+	// the blocks that are handed out all start with a full first group
+	if len(strings.Split(lower, ":")[0]) != 4 {
+		return false
+	}
+
+	// global unicast, unique local, link local and multicast
+	return ip[0]&0xe0 == 0x20 || ip[0]&0xfe == 0xfc || ip[0] == 0xfe && ip[1]&0xc0 == 0x80 || ip[0] == 0xff
+}
+
+// utilValidDomain will check that every label is valid and that the last
+// is a real top level domain, strict is set when the context already says
+// that it is a host so that names which look like files are allowed
+func utilValidDomain(host string, strict bool) bool {
+	labels := strings.Split(strings.TrimSuffix(host, "."), ".")
+	if len(labels) < 2 {
+		return false
+	}
+
+	tld := labels[len(labels)-1]
+	if !UtilIsTLD(tld) {
+		return false
+	}
+
+	if strict {
+		return true
+	}
+
+	if fileTLDs[strings.ToLower(tld)] && len(labels) == 2 {
+		return false
+	}
+
+	// identifiers such as Config.Name are mixed case,
+	// whereas domains are written in a single case
+	if host != strings.ToLower(host) && host != strings.ToUpper(host) {
+		return false
+	}
+
+	// numbers such as 1.2.com are not domains
+	allDigits := true
+	for _, label := range labels[:len(labels)-1] {
+		if strings.Trim(label, "0123456789") != "" {
+			allDigits = false
+			break
+		}
+	}
+
+	return !allDigits
+}
+
+// utilIsWordByte will check if the byte can be part of an identifier
+func utilIsWordByte(b byte) bool {
+	return b == '_' || b == ':' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// base58 is the alphabet used by bitcoin and monero addresses
+const base58 = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// utilDecodeBase58 will decode the base58 text into bytes
+func utilDecodeBase58(text string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+
+	for i := 0; i < len(text); i++ {
+		digit := strings.IndexByte(base58, text[i])
+		if digit == -1 {
+			return nil, errors.New("invalid base58 digit")
+		}
+
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+
+	out := n.Bytes()
+
+	// each leading 1 is a leading zero byte
+	for i := 0; i < len(text) && text[i] == '1'; i++ {
+		out = append([]byte{0}, out...)
+	}
+
+	return out, nil
+}
+
+// utilValidBitcoin will check the checksum of a legacy base58
+// or a segwit bech32 bitcoin address
+func utilValidBitcoin(addr string) bool {
+	lower := strings.ToLower(addr)
+	if strings.HasPrefix(lower, "bc1") || strings.HasPrefix(lower, "tb1") {
+		if addr != lower && addr != strings.ToUpper(addr) {
+			return false
+		}
+
+		return utilValidBech32(lower)
+	}
+
+	buf, err := utilDecodeBase58(addr)
+	if err != nil || len(buf) != 25 {
+		return false
+	}
+
+	first := sha256.Sum256(buf[:21])
+	second := sha256.Sum256(first[:])
+
+	return string(second[:4]) == string(buf[21:])
+}
+
+// utilValidBech32 will check the checksum of a bech32 or bech32m address
+func utilValidBech32(addr string) bool {
+	const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	sep := strings.LastIndex(addr, "1")
+	if sep < 1 || sep+7 > len(addr) {
+		return false
+	}
+
+	var values []int
+	for i := 0; i < sep; i++ {
+		values = append(values, int(addr[i]>>5))
+	}
+
+	values = append(values, 0)
+	for i := 0; i < sep; i++ {
+		values = append(values, int(addr[i]&31))
+	}
+
+	for i := sep + 1; i < len(addr); i++ {
+		v := strings.IndexByte(charset, addr[i])
+		if v == -1 {
+			return false
+		}
+
+		values = append(values, v)
+	}
+
+	generator := []int{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := 1
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ v
+
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 != 0 {
+				chk ^= generator[i]
+			}
+		}
+	}
+
+	// bech32 for version 0 and bech32m for every later version
+	return chk == 1 || chk == 0x2bc830a3
+}
+
+// utilValidEthereum will check the EIP-55 checksum of an ethereum
+// address, which is held in the case of its letters, an address in a
+// single case has no checksum and so is never valid on its own
+func utilValidEthereum(addr string) bool {
+	lower := strings.ToLower(addr)
+	if addr == lower || addr == strings.ToUpper(addr) {
+		return false
+	}
+
+	hash := utilKeccak256([]byte(lower))
+
+	for i := 0; i < len(addr); i++ {
+		if addr[i] >= '0' && addr[i] <= '9' {
+			continue
+		}
+
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0xf
+		}
+
+		if (nibble >= 8) != (addr[i] >= 'A' && addr[i] <= 'F') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// keccakRounds are the round constants of keccak-f[1600]
+var keccakRounds = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRotations and keccakLanes are the rotation of each lane and the
+// lane it moves to in the rho and pi steps, starting from lane one
+var (
+	keccakRotations = [24]uint{1, 3, 6, 10, 15, 21, 28, 36, 45, 55, 2, 14, 27, 41, 56, 8, 25, 43, 62, 18, 39, 61, 20, 44}
+	keccakLanes     = [24]int{10, 7, 11, 17, 18, 3, 5, 16, 8, 21, 24, 4, 15, 23, 19, 13, 12, 2, 20, 14, 22, 9, 6, 1}
+)
+
+// utilKeccak256 will hash the data with the keccak-256 used by ethereum,
+// which pads differently to the SHA3-256 that was standardised from it
+func utilKeccak256(data []byte) [32]byte {
+	const rate = 136
+
+	var state [25]uint64
+
+	padded := append(append([]byte{}, data...), 0x01)
+	for len(padded)%rate != 0 {
+		padded = append(padded, 0)
+	}
+
+	padded[len(padded)-1] |= 0x80
+
+	for block := 0; block < len(padded); block += rate {
+		for i := 0; i < rate/8; i++ {
+			state[i] ^= binary.LittleEndian.Uint64(padded[block+i*8:])
+		}
+
+		utilKeccakF(&state)
+	}
+
+	var out [32]byte
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint64(out[i*8:], state[i])
+	}
+
+	return out
+}
+
+// utilKeccakF will run the keccak-f[1600] permutation over the state
+func utilKeccakF(state *[25]uint64) {
+	var column [5]uint64
+
+	for round := 0; round < 24; round++ {
+		// theta
+		for i := 0; i < 5; i++ {
+			column[i] = state[i] ^ state[i+5] ^ state[i+10] ^ state[i+15] ^ state[i+20]
+		}
+
+		for i := 0; i < 5; i++ {
+			t := column[(i+4)%5] ^ bits.RotateLeft64(column[(i+1)%5], 1)
+			for j := 0; j < 25; j += 5 {
+				state[j+i] ^= t
+			}
+		}
+
+		// rho and pi
+		t := state[1]
+		for i := 0; i < 24; i++ {
+			j := keccakLanes[i]
+			t, state[j] = state[j], bits.RotateLeft64(t, int(keccakRotations[i]))
+		}
+
+		// chi
+		for j := 0; j < 25; j += 5 {
+			copy(column[:], state[j:j+5])
+			for i := 0; i < 5; i++ {
+				state[j+i] ^= ^column[(i+1)%5] & column[(i+2)%5]
+			}
+		}
+
+		// iota
+		state[0] ^= keccakRounds[round]
+	}
+}
+
+// utilValidMonero will check that a monero address decodes to the
+// length of one, with the network byte of a main net address
+func utilValidMonero(addr string) bool {
+	var out []byte
+
+	// monero encodes each eight byte block as eleven characters,
+	// with the final partial block of five bytes as seven
+	for i := 0; i < len(addr); i += 11 {
+		end := i + 11
+		size := 8
+		if end > len(addr) {
+			end = len(addr)
+			size = 5
+		}
+
+		buf, err := utilDecodeBase58(addr[i:end])
+		if err != nil || len(buf) > size {
+			return false
+		}
+
+		block := make([]byte, size-len(buf), size)
+		out = append(out, append(block, buf...)...)
+	}
+
+	return len(out) == 69 && (out[0] == 0x12 || out[0] == 0x2a)
+}
@@ -0,0 +1,117 @@
+package elfstrings
+
+import (
+	"encoding/hex"
+	"sync"
+	"testing"
+)
+
+func TestFindIOCs(t *testing.T) {
+	tests := []struct {
+		text string
+		want []IOC
+	}{
+		{"connect to https://example.com:8443/path now", []IOC{
+			{Type: IOCURL, Value: "https://example.com:8443/path"},
+			{Type: IOCPort, Value: "example.com:8443"},
+		}},
+		{"mail admin@example.org", []IOC{{Type: IOCEmail, Value: "admin@example.org"}}},
+		{"hxxp://evil[.]com/x", []IOC{{Type: IOCURL, Value: "http://evil.com/x", Defanged: true}}},
+		{"beacon 203.0.113.7:4444", []IOC{
+			{Type: IOCIPv4, Value: "203.0.113.7"},
+			{Type: IOCPort, Value: "203.0.113.7:4444"},
+		}},
+		{"resolver 8.8.8.8", []IOC{{Type: IOCIPv4, Value: "8.8.8.8"}}},
+		{"version 1.2.3.4", nil},
+		{"dns 2001:4860:4860::8888", []IOC{{Type: IOCIPv6, Value: "2001:4860:4860::8888"}}},
+		{"bind [fe80::1]:22", []IOC{
+			{Type: IOCIPv6, Value: "fe80::1"},
+			{Type: IOCPort, Value: "[fe80::1]:22"},
+		}},
+		{"a::b", nil},
+		{"dead:beef::", nil},
+		{"std::vector<int>", nil},
+		{"usr/lib/libc.so", nil},
+		{"the host is c2.example.net", []IOC{{Type: IOCDomain, Value: "c2.example.net"}}},
+		{"Config.Name", nil},
+		{"pay 1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", []IOC{{Type: IOCBitcoin, Value: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"}}},
+		{"pay 1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3", nil},
+		{"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", []IOC{{Type: IOCBitcoin, Value: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"}}},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", []IOC{{Type: IOCEthereum, Value: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"}}},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", nil},
+		{"eth wallet 0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", []IOC{{Type: IOCEthereum, Value: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"}}},
+		{"0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", nil},
+		{"expgvgmv2jp3cnzkhz7znptpwlpz5k6lt3gtqybvvrwqtmsvr7vbxmid.onion", []IOC{{Type: IOCOnion, Value: "expgvgmv2jp3cnzkhz7znptpwlpz5k6lt3gtqybvvrwqtmsvr7vbxmid.onion"}}},
+	}
+
+	for _, tt := range tests {
+		got := UtilFindIOCs(tt.text)
+		if len(got) != len(tt.want) {
+			t.Errorf("UtilFindIOCs(%q) = %v, want %v", tt.text, got, tt.want)
+			continue
+		}
+
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("UtilFindIOCs(%q)[%d] = %v, want %v", tt.text, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestValidIPv6(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"::1", true},
+		{"::ffff:192.0.2.1", true},
+		{"2001:db8:85a3:0:0:8a2e:370:7334", true},
+		{"2001:db8::1", true},
+		{"fd12:3456:789a::1", true},
+		{"ff02::1", true},
+		{"fe80::", false},
+		{"a::b", false},
+		{"dead:beef::", false},
+		{"beef::cafe", false},
+		{"1:2:3", false},
+	}
+
+	for _, tt := range tests {
+		if got := utilValidIPv6(tt.host); got != tt.want {
+			t.Errorf("utilValidIPv6(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestKeccak256(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"abc", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+	}
+
+	for _, tt := range tests {
+		sum := utilKeccak256([]byte(tt.data))
+		if got := hex.EncodeToString(sum[:]); got != tt.want {
+			t.Errorf("utilKeccak256(%q) = %s, want %s", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestIsTLDConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !UtilIsTLD("com") || UtilIsTLD("notatld") {
+				t.Error("UtilIsTLD gave the wrong answer")
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,158 @@
+//go:build ignore
+// +build ignore
+
+// makemodel builds the character bigram model used by the readability
+// scorer from a corpus of text. Go source files given are reduced to their
+// string literals and comments, as those are the closest to the strings
+// that are found in binaries, any other file is used as it is.
+//
+// The model that ships was built from the licenses in
+// /usr/share/common-licenses and the Go standard library:
+//
+//	go run makemodel.go -o model.go /usr/share/common-licenses/* $(find $(go env GOROOT)/src -name '*.go' -not -name '*_test.go' -not -path '*/testdata/*')
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// these must match the constants in score.go
+const (
+	modelOther    = 95
+	modelBoundary = 96
+	modelSize     = 97
+	modelScale    = 16
+)
+
+var outOpt = flag.String("o", "model.go", "the path of the generated file")
+
+// modelClass converts a byte to its index in the model
+func modelClass(b byte) int {
+	if b >= ' ' && b <= '~' {
+		return int(b - ' ')
+	}
+
+	return modelOther
+}
+
+// count will add the bigrams of each line of the text to the counts
+func count(counts *[modelSize][modelSize]float64, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		prev := modelBoundary
+		for i := 0; i < len(line); i++ {
+			cur := modelClass(line[i])
+			counts[prev][cur]++
+			prev = cur
+		}
+
+		counts[prev][modelBoundary]++
+	}
+}
+
+// goText will reduce a Go source file to its string literals and comments
+func goText(src []byte) string {
+	var s scanner.Scanner
+	var out bytes.Buffer
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	s.Init(file, src, nil, scanner.ScanComments)
+
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		switch tok {
+		case token.COMMENT:
+			lit = strings.TrimPrefix(lit, "//")
+			lit = strings.TrimPrefix(lit, "/*")
+			lit = strings.TrimSuffix(lit, "*/")
+		case token.STRING:
+			if unquoted, err := strconv.Unquote(lit); err == nil {
+				lit = unquoted
+			}
+		default:
+			continue
+		}
+
+		out.WriteString(lit)
+		out.WriteByte('\n')
+	}
+
+	return out.String()
+}
+
+func main() {
+	var counts [modelSize][modelSize]float64
+
+	flag.Parse()
+
+	for _, path := range flag.Args() {
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if strings.HasSuffix(path, ".go") {
+			count(&counts, goText(buf))
+		} else {
+			count(&counts, string(buf))
+		}
+	}
+
+	var out bytes.Buffer
+
+	fmt.Fprintf(&out, "// Code generated by makemodel.go; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package elfstrings\n\n")
+	fmt.Fprintf(&out, "// model is the cost of each bigram in bits, scaled by modelScale\n")
+	fmt.Fprintf(&out, "var model = [modelSize][modelSize]uint8{\n")
+
+	for prev := 0; prev < modelSize; prev++ {
+		var total float64
+		for cur := 0; cur < modelSize; cur++ {
+			total += counts[prev][cur]
+		}
+
+		fmt.Fprintf(&out, "\t{")
+		for cur := 0; cur < modelSize; cur++ {
+			// add-one smoothing, so that bigrams never seen
+			// have a high but not infinite cost
+			p := (counts[prev][cur] + 1) / (total + modelSize)
+
+			cost := math.Round(-math.Log2(p) * modelScale)
+			if cost > 255 {
+				cost = 255
+			}
+
+			if cur != 0 {
+				out.WriteString(", ")
+			}
+
+			fmt.Fprintf(&out, "%d", int(cost))
+		}
+		fmt.Fprintf(&out, "},\n")
+	}
+
+	fmt.Fprintf(&out, "}\n")
+
+	err := ioutil.WriteFile(*outOpt, out.Bytes(), 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
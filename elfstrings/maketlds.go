@@ -0,0 +1,105 @@
+//go:build ignore
+// +build ignore
+
+// maketlds builds the list of top level domains used to validate domain
+// indicators from the ICANN section of the public suffix list, the
+// internationalised domains are left out as only ASCII is matched.
+//
+//	go run maketlds.go -o tlds.go /usr/share/publicsuffix/public_suffix_list.dat
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+var outOpt = flag.String("o", "tlds.go", "the path of the generated file")
+
+func main() {
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		log.Fatal("the path of the public suffix list is required")
+	}
+
+	fd, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer fd.Close()
+
+	seen := make(map[string]bool)
+	var tlds []string
+
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.Contains(line, "===END ICANN DOMAINS===") {
+			break
+		}
+
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		// the rules are always lowercase, and the last
+		// label of each is the top level domain
+		labels := strings.Split(strings.TrimLeft(line, "!*."), ".")
+		tld := labels[len(labels)-1]
+
+		ascii := true
+		for i := 0; i < len(tld); i++ {
+			if tld[i] >= 0x80 {
+				ascii = false
+				break
+			}
+		}
+
+		if !ascii || seen[tld] {
+			continue
+		}
+
+		seen[tld] = true
+		tlds = append(tlds, tld)
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	sort.Strings(tlds)
+
+	var out bytes.Buffer
+
+	fmt.Fprintf(&out, "// Code generated by maketlds.go; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package elfstrings\n\n")
+	fmt.Fprintf(&out, "// tldList is the space separated list of top level domains\n")
+	fmt.Fprintf(&out, "const tldList = \"\" +\n")
+
+	line := ""
+	for i, tld := range tlds {
+		line += tld + " "
+		if len(line) > 64 || i == len(tlds)-1 {
+			end := " +"
+			if i == len(tlds)-1 {
+				end = ""
+			}
+
+			fmt.Fprintf(&out, "\t%q%s\n", line, end)
+			line = ""
+		}
+	}
+
+	err = ioutil.WriteFile(*outOpt, out.Bytes(), 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
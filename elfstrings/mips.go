@@ -0,0 +1,123 @@
+package elfstrings
+
+import (
+	"debug/elf"
+)
+
+// mipsGPOffset is how far past the start of the GOT the global pointer
+// is set, so that the whole of it can be reached with a signed offset
+const mipsGPOffset = 0x7ff0
+
+// utilXrefsMIPS will decode the MIPS code at addr and pass each address that
+// it references to add. Addresses are built from a lui of the upper half
+// followed by an addiu, ori or load of the lower half, or are loaded from
+// the GOT relative to gp in position independent code, where the page of a
+// local string is loaded and the offset into it added afterwards
+func utilXrefsMIPS(code []byte, addr uint64, gp uint64, mem *memory, add func(from uint64, to uint64)) {
+	var regs pairs
+
+	mask := ^uint64(0)
+	size := 8
+	if mem.r.ExecReader.Class == elf.ELFCLASS32 {
+		mask, size = 0xffffffff, 4
+	}
+
+	for off := 0; off+4 <= len(code); off += 4 {
+		w := mem.order.Uint32(code[off:])
+		pc := addr + uint64(off)
+		rs := (w >> 21) & 31
+		rt := (w >> 16) & 31
+		imm := utilSignExtend(uint64(w&0xffff), 16)
+
+		switch w >> 26 {
+		case 0x0f:
+			// lui rt, imm16
+			regs.set(rt, (imm<<16)&mask, pc)
+			continue
+		case 0x09, 0x19:
+			// addiu and daddiu rt, rs, imm16
+			if base, ok := regs.get(rs, pc); ok {
+				target := (base + imm) & mask
+				add(pc, target)
+				regs.set(rt, target, pc)
+				continue
+			}
+		case 0x0d:
+			// ori rt, rs, imm16, which is not sign extended
+			if base, ok := regs.get(rs, pc); ok {
+				target := base | uint64(w&0xffff)
+				add(pc, target)
+				regs.set(rt, target, pc)
+				continue
+			}
+		case 0x23, 0x37:
+			// lw and ld rt, imm16(rs)
+			if rs == 28 && gp != 0 {
+				slot := (gp + imm) & mask
+				if ptr, ok := mem.load(slot, size); ok {
+					add(pc, ptr&mask)
+					regs.set(rt, ptr&mask, pc)
+					continue
+				}
+			} else if base, ok := regs.get(rs, pc); ok {
+				add(pc, (base+imm)&mask)
+			}
+		case 0x20, 0x21, 0x24, 0x25, 0x27:
+			// the smaller loads of a byte or half, such as of a character
+			if base, ok := regs.get(rs, pc); ok {
+				add(pc, (base+imm)&mask)
+			}
+		case 0x28, 0x29, 0x2b, 0x3f, 0x04, 0x05, 0x01, 0x02, 0x03:
+			// stores and branches do not write to rt
+			continue
+		case 0x00:
+			// the special instructions write to rd, except for jr
+			rd := (w >> 11) & 31
+			if rd == 0 {
+				continue
+			}
+
+			// addu and daddu of the upper half and gp, which is how
+			// Go addresses its data relative to a static base
+			if funct := w & 0x3f; funct == 0x21 || funct == 0x2d {
+				other := rs
+				if other == 28 {
+					other = rt
+				}
+
+				if base, ok := regs.get(other, pc); ok && (rs == 28 || rt == 28) {
+					regs.set(rd, (base+gp)&mask, pc)
+					continue
+				}
+			}
+
+			regs.clear(rd)
+			continue
+		}
+
+		regs.clear(rt)
+	}
+}
+
+// readerMIPSGP will find the value of the global pointer, from the _gp
+// symbol or otherwise from the address of the GOT
+func (r *ElfReader) readerMIPSGP() uint64 {
+	for _, load := range []func() ([]elf.Symbol, error){r.ExecReader.Symbols, r.ExecReader.DynamicSymbols} {
+		syms, err := load()
+		if err != nil {
+			continue
+		}
+
+		for _, sym := range syms {
+			if sym.Name == "_gp" {
+				return sym.Value
+			}
+		}
+	}
+
+	if got := r.ExecReader.Section(".got"); got != nil {
+		return got.Addr + mipsGPOffset
+	}
+
+	return 0
+}
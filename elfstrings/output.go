@@ -0,0 +1,521 @@
+package elfstrings
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"math"
+	"os"
+	"strings"
+)
+
+// WriterFormat to emulate an emum to make my constants nicer
+type WriterFormat int32
+
+// The types of the objects that are output, which tell them apart when
+// more than one kind is written to the same file
+const (
+	outputTypeString    = "string"
+	outputTypeFunction  = "function"
+	outputTypeIndicator = "indicator"
+	outputTypeGoInfo    = "go_info"
+	outputTypeRustInfo  = "rust_info"
+	outputTypeClass     = "class"
+	outputTypeSwiftType = "swift_type"
+)
+
+// OutputStructure is the structure of that data that will be output
+type OutputStructure struct {
+	Type       string          `json:"type" xml:"type,attr"`
+	ID         uint64          `json:"id" xml:"id"`
+	Section    string          `json:"section" xml:"section"`
+	Content    string          `json:"content" xml:"content"`
+	Offset     uint64          `json:"offset" xml:"offset"`
+	FileOffset uint64          `json:"file_offset" xml:"file_offset"`
+	Address    uint64          `json:"address,omitempty" xml:"address,omitempty"`
+	Segment    string          `json:"segment,omitempty" xml:"segment,omitempty"`
+	Perms      string          `json:"perms,omitempty" xml:"perms,omitempty"`
+	Encoding   string          `json:"encoding" xml:"encoding"`
+	Source     string          `json:"source,omitempty" xml:"source,omitempty"`
+	Prefix     string          `json:"prefix,omitempty" xml:"prefix,omitempty"`
+	Language   string          `json:"language,omitempty" xml:"language,omitempty"`
+	Mangled    []OutputMangled `json:"mangled,omitempty" xml:"mangled,omitempty"`
+	Function   uint64          `json:"function,omitempty" xml:"function,omitempty"`
+	Decodings  []string        `json:"decodings,omitempty" xml:"decoding,omitempty"`
+	Parent     uint64          `json:"parent,omitempty" xml:"parent,omitempty"`
+	Score      float64         `json:"score" xml:"score"`
+	Rank       *Rank           `json:"rank,omitempty" xml:"rank,omitempty"`
+	IOCs       []OutputIOC     `json:"iocs,omitempty" xml:"ioc,omitempty"`
+	Tags       []string        `json:"tags,omitempty" xml:"tag,omitempty"`
+	Xrefs      []OutputXref    `json:"xrefs,omitempty" xml:"xref,omitempty"`
+}
+
+// OutputMangled is the structure of a symbol which was demangled
+type OutputMangled struct {
+	Offset    int    `json:"offset" xml:"offset"`
+	Mangled   string `json:"mangled" xml:"mangled"`
+	Demangled string `json:"demangled" xml:"demangled"`
+	Language  string `json:"language" xml:"language"`
+}
+
+// OutputXref is the structure of an instruction referencing a string
+type OutputXref struct {
+	Address  uint64 `json:"address" xml:"address"`
+	Function string `json:"function,omitempty" xml:"function,omitempty"`
+}
+
+// OutputFunction is the structure of a function and the strings it uses
+type OutputFunction struct {
+	Type    string            `json:"type" xml:"type,attr"`
+	Name    string            `json:"name" xml:"name"`
+	Address uint64            `json:"address" xml:"address"`
+	Size    uint64            `json:"size,omitempty" xml:"size,omitempty"`
+	Strings []OutputStructure `json:"strings" xml:"string"`
+}
+
+// OutputIOC is the structure of an indicator of compromise within a string
+type OutputIOC struct {
+	Type     string `json:"type" xml:"type"`
+	Value    string `json:"value" xml:"value"`
+	Defanged bool   `json:"defanged,omitempty" xml:"defanged,omitempty"`
+}
+
+// OutputIndicator is the structure of an indicator of compromise that is
+// output on its own, along with every place it was found
+type OutputIndicator struct {
+	Type     string          `json:"type" xml:"type,attr"`
+	Kind     string          `json:"kind" xml:"kind"`
+	Value    string          `json:"value" xml:"value"`
+	Defanged bool            `json:"defanged,omitempty" xml:"defanged,omitempty"`
+	Sources  []OutputIOCFrom `json:"sources,omitempty" xml:"source,omitempty"`
+}
+
+// OutputIOCFrom is the structure of where an indicator was found
+type OutputIOCFrom struct {
+	Section    string `json:"section" xml:"section"`
+	Offset     uint64 `json:"offset" xml:"offset"`
+	FileOffset uint64 `json:"file_offset" xml:"file_offset"`
+}
+
+// OutputGoInfo is the structure of the build information of a Go binary,
+// along with its functions and source files
+type OutputGoInfo struct {
+	Type      string            `json:"type" xml:"type,attr"`
+	GoVersion string            `json:"go_version,omitempty" xml:"go_version,omitempty"`
+	Path      string            `json:"path,omitempty" xml:"path,omitempty"`
+	Main      *OutputGoModule   `json:"main,omitempty" xml:"main,omitempty"`
+	Deps      []OutputGoModule  `json:"deps,omitempty" xml:"dep,omitempty"`
+	Settings  []OutputGoSetting `json:"settings,omitempty" xml:"setting,omitempty"`
+	Functions []string          `json:"functions,omitempty" xml:"function,omitempty"`
+	Files     []string          `json:"files,omitempty" xml:"file,omitempty"`
+}
+
+// OutputGoModule is the structure of a module of a Go binary
+type OutputGoModule struct {
+	Path    string          `json:"path" xml:"path"`
+	Version string          `json:"version,omitempty" xml:"version,omitempty"`
+	Sum     string          `json:"sum,omitempty" xml:"sum,omitempty"`
+	Replace *OutputGoModule `json:"replace,omitempty" xml:"replace,omitempty"`
+}
+
+// OutputGoSetting is the structure of a build setting of a Go binary
+type OutputGoSetting struct {
+	Key   string `json:"key" xml:"key"`
+	Value string `json:"value" xml:"value"`
+}
+
+// OutputRustInfo is the structure of what a Rust binary records about the
+// compiler and crates that built it, along with its panic locations
+type OutputRustInfo struct {
+	Type        string                `json:"type" xml:"type,attr"`
+	RustVersion string                `json:"rustc_version,omitempty" xml:"rustc_version,omitempty"`
+	Commit      string                `json:"commit,omitempty" xml:"commit,omitempty"`
+	Crates      []OutputRustCrate     `json:"crates,omitempty" xml:"crate,omitempty"`
+	Locations   []OutputPanicLocation `json:"locations,omitempty" xml:"location,omitempty"`
+}
+
+// OutputRustCrate is the structure of a crate of a Rust binary
+type OutputRustCrate struct {
+	Name    string `json:"name" xml:"name"`
+	Version string `json:"version,omitempty" xml:"version,omitempty"`
+	Source  string `json:"source" xml:"source"`
+}
+
+// OutputPanicLocation is the structure of a panic location of a Rust binary
+type OutputPanicLocation struct {
+	Address uint64 `json:"address" xml:"address"`
+	File    string `json:"file" xml:"file"`
+	Line    uint32 `json:"line" xml:"line"`
+	Column  uint32 `json:"column" xml:"column"`
+}
+
+// OutputClass is the structure of a C++ class recovered from its run time
+// type information, along with its bases and vtables
+type OutputClass struct {
+	Type     string            `json:"type" xml:"type,attr"`
+	Name     string            `json:"name" xml:"name"`
+	Mangled  string            `json:"mangled" xml:"mangled"`
+	TypeInfo uint64            `json:"typeinfo,omitempty" xml:"typeinfo,omitempty"`
+	Kind     string            `json:"kind" xml:"kind"`
+	Bases    []OutputClassBase `json:"bases,omitempty" xml:"base,omitempty"`
+	Derived  []string          `json:"derived,omitempty" xml:"derived,omitempty"`
+	VTables  []OutputVTable    `json:"vtables,omitempty" xml:"vtable,omitempty"`
+}
+
+// OutputClassBase is the structure of a base that a class inherits from
+type OutputClassBase struct {
+	Name    string `json:"name" xml:"name"`
+	Offset  int64  `json:"offset" xml:"offset"`
+	Virtual bool   `json:"virtual,omitempty" xml:"virtual,omitempty"`
+	Public  bool   `json:"public" xml:"public"`
+}
+
+// OutputVTable is the structure of a vtable of a class
+type OutputVTable struct {
+	Address     uint64 `json:"address" xml:"address"`
+	OffsetToTop int64  `json:"offset_to_top" xml:"offset_to_top"`
+}
+
+// OutputSwiftType is the structure of a type of a Swift binary, along with
+// its fields and the protocols it conforms to
+type OutputSwiftType struct {
+	Type         string             `json:"type" xml:"type,attr"`
+	Name         string             `json:"name" xml:"name"`
+	Kind         string             `json:"kind" xml:"kind"`
+	Superclass   string             `json:"superclass,omitempty" xml:"superclass,omitempty"`
+	Fields       []OutputSwiftField `json:"fields,omitempty" xml:"field,omitempty"`
+	Conformances []string           `json:"conformances,omitempty" xml:"conformance,omitempty"`
+}
+
+// OutputSwiftField is the structure of a field of a Swift type
+type OutputSwiftField struct {
+	Name     string `json:"name" xml:"name"`
+	Type     string `json:"type,omitempty" xml:"type,omitempty"`
+	Var      bool   `json:"var,omitempty" xml:"var,omitempty"`
+	Indirect bool   `json:"indirect,omitempty" xml:"indirect,omitempty"`
+}
+
+// OutWriter is the context that the output module utilises
+type OutWriter struct {
+	fd     *os.File
+	format WriterFormat
+}
+
+// Types of formatting that may be used
+const (
+	JSON WriterFormat = iota
+	XML
+	plain
+	end
+)
+
+// NewOutWriter creates a new instance of OutWriter
+// with the desired format
+func NewOutWriter(path string, format WriterFormat) (*OutWriter, error) {
+	var writer OutWriter
+	var err error
+
+	if !ValidType(format) {
+		return nil, errors.New("this output type does not exist")
+	}
+
+	writer.format = format
+
+	writer.fd, err = os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0777)
+	if err != nil {
+		return nil, err
+	}
+
+	return &writer, nil
+}
+
+// WriteResult appends to the currently opened file
+// using the specified format, with the result.
+func (o *OutWriter) WriteResult(rec *StringRecord) bool {
+	return o.write(utilOutputRecord(rec), rec.Text)
+}
+
+// WriteFunction appends the function to the currently opened file
+// using the specified format, along with the strings it references
+func (o *OutWriter) WriteFunction(fn *FunctionStrings) bool {
+	output := &OutputFunction{
+		Type:    outputTypeFunction,
+		Name:    fn.Name,
+		Address: fn.Address,
+		Size:    fn.Size,
+	}
+
+	var texts []string
+	for i := range fn.Records {
+		output.Strings = append(output.Strings, *utilOutputRecord(&fn.Records[i]))
+		texts = append(texts, fn.Records[i].Text)
+	}
+
+	return o.write(output, fn.Name+": "+strings.Join(texts, ", "))
+}
+
+// utilOutputRecord will convert the record into the structure that is output
+func utilOutputRecord(rec *StringRecord) *OutputStructure {
+	output := &OutputStructure{
+		Type:       outputTypeString,
+		ID:         rec.ID,
+		Section:    rec.Section,
+		Content:    rec.Text,
+		Offset:     rec.Offset,
+		FileOffset: rec.FileOffset,
+		Address:    rec.Address,
+		Encoding:   rec.Encoding.String(),
+		Score:      utilRound(rec.Score.Total),
+		Function:   rec.FunctionAddress,
+	}
+
+	if rec.Source != SourceSection {
+		output.Source = rec.Source.String()
+	}
+
+	if rec.Prefix != PrefixNone {
+		output.Prefix = rec.Prefix.String()
+	}
+
+	if rec.Language != LanguageNone {
+		output.Language = rec.Language.String()
+	}
+
+	for _, tok := range rec.Mangled {
+		output.Mangled = append(output.Mangled, OutputMangled{
+			Offset:    tok.Offset,
+			Mangled:   tok.Mangled,
+			Demangled: tok.Demangled,
+			Language:  tok.Language.String(),
+		})
+	}
+
+	if rec.Parent != nil {
+		output.Parent = rec.Parent.ID
+	}
+
+	if rec.Rank != nil {
+		output.Rank = &Rank{
+			Readability: utilRound(rec.Rank.Readability),
+			Length:      utilRound(rec.Rank.Length),
+			Uniqueness:  utilRound(rec.Rank.Uniqueness),
+			IOC:         utilRound(rec.Rank.IOC),
+			Format:      utilRound(rec.Rank.Format),
+			Referenced:  utilRound(rec.Rank.Referenced),
+			Boilerplate: utilRound(rec.Rank.Boilerplate),
+			Total:       utilRound(rec.Rank.Total),
+		}
+	}
+
+	for _, decoding := range rec.Decodings {
+		output.Decodings = append(output.Decodings, decoding.String())
+	}
+
+	for _, tag := range rec.Tags {
+		output.Tags = append(output.Tags, tag.String())
+	}
+
+	for _, xref := range rec.Xrefs {
+		output.Xrefs = append(output.Xrefs, OutputXref{Address: xref.Address, Function: xref.Function})
+	}
+
+	for _, ioc := range rec.IOCs {
+		output.IOCs = append(output.IOCs, OutputIOC{
+			Type:     ioc.Type.String(),
+			Value:    ioc.Value,
+			Defanged: ioc.Defanged,
+		})
+	}
+
+	if rec.Segment != nil {
+		output.Segment = rec.Segment.Name()
+		output.Perms = rec.Segment.Perms()
+	}
+
+	return output
+}
+
+// WriteIndicator appends the indicator to the currently opened file
+// using the specified format, along with where it was found
+func (o *OutWriter) WriteIndicator(ind *Indicator) bool {
+	output := &OutputIndicator{
+		Type:     outputTypeIndicator,
+		Kind:     ind.Type.String(),
+		Value:    ind.Value,
+		Defanged: ind.Defanged,
+	}
+
+	for _, src := range ind.Sources {
+		output.Sources = append(output.Sources, OutputIOCFrom{
+			Section:    src.Section,
+			Offset:     src.Offset,
+			FileOffset: src.FileOffset,
+		})
+	}
+
+	return o.write(output, ind.Value)
+}
+
+// WriteGoInfo appends the build information of a Go binary to the
+// currently opened file using the specified format
+func (o *OutWriter) WriteGoInfo(info *GoInfo) bool {
+	output := &OutputGoInfo{
+		Type:      outputTypeGoInfo,
+		GoVersion: info.Version,
+		Path:      info.Path,
+		Functions: info.Functions,
+		Files:     info.Files,
+	}
+
+	if info.Main.Path != "" {
+		output.Main = utilOutputGoModule(&info.Main)
+	}
+
+	for i := range info.Deps {
+		output.Deps = append(output.Deps, *utilOutputGoModule(&info.Deps[i]))
+	}
+
+	for _, setting := range info.Settings {
+		output.Settings = append(output.Settings, OutputGoSetting{Key: setting.Key, Value: setting.Value})
+	}
+
+	return o.write(output, strings.TrimSpace(info.Version+" "+info.Path))
+}
+
+// WriteRustInfo appends what a Rust binary records about its compiler,
+// crates and panic locations to the currently opened file using the
+// specified format
+func (o *OutWriter) WriteRustInfo(info *RustInfo) bool {
+	output := &OutputRustInfo{Type: outputTypeRustInfo, RustVersion: info.Version, Commit: info.Commit}
+
+	for _, crate := range info.Crates {
+		output.Crates = append(output.Crates, OutputRustCrate{Name: crate.Name, Version: crate.Version, Source: crate.Source})
+	}
+
+	for _, loc := range info.Locations {
+		output.Locations = append(output.Locations, OutputPanicLocation{
+			Address: loc.Address,
+			File:    loc.File,
+			Line:    loc.Line,
+			Column:  loc.Column,
+		})
+	}
+
+	return o.write(output, strings.TrimSpace("rustc "+info.Version))
+}
+
+// WriteClass appends the C++ class with its bases and vtables to the
+// currently opened file using the specified format
+func (o *OutWriter) WriteClass(cls *CxxClass) bool {
+	output := &OutputClass{
+		Type:     outputTypeClass,
+		Name:     cls.Name,
+		Mangled:  cls.Mangled,
+		TypeInfo: cls.TypeInfo,
+		Kind:     cls.Kind.String(),
+		Derived:  cls.Derived,
+	}
+
+	for _, base := range cls.Bases {
+		output.Bases = append(output.Bases, OutputClassBase{
+			Name:    base.Name,
+			Offset:  base.Offset,
+			Virtual: base.Virtual,
+			Public:  base.Public,
+		})
+	}
+
+	for _, vt := range cls.VTables {
+		output.VTables = append(output.VTables, OutputVTable{Address: vt.Address, OffsetToTop: vt.OffsetToTop})
+	}
+
+	return o.write(output, cls.Name)
+}
+
+// WriteSwiftType appends the Swift type with its fields and conformances
+// to the currently opened file using the specified format
+func (o *OutWriter) WriteSwiftType(ty *SwiftType) bool {
+	output := &OutputSwiftType{
+		Type:         outputTypeSwiftType,
+		Name:         ty.Name,
+		Kind:         ty.Kind.String(),
+		Superclass:   ty.Superclass,
+		Conformances: ty.Conformances,
+	}
+
+	for _, field := range ty.Fields {
+		output.Fields = append(output.Fields, OutputSwiftField{
+			Name:     field.Name,
+			Type:     field.Type,
+			Var:      field.Var,
+			Indirect: field.Indirect,
+		})
+	}
+
+	return o.write(output, ty.Kind.String()+" "+ty.Name)
+}
+
+// utilOutputGoModule will convert the module into the structure that is
+// output, along with the module that replaced it
+func utilOutputGoModule(mod *GoModule) *OutputGoModule {
+	output := &OutputGoModule{Path: mod.Path, Version: mod.Version, Sum: mod.Sum}
+	if mod.Replace != nil {
+		output.Replace = utilOutputGoModule(mod.Replace)
+	}
+
+	return output
+}
+
+// write will marshal the output in the format of the writer,
+// text is what is written in the plain format
+func (o *OutWriter) write(output interface{}, text string) bool {
+	buf := text
+
+	if o.format == JSON {
+		j, err := json.Marshal(output)
+		if err != nil {
+			return false
+		}
+
+		buf = string(j)
+	} else if o.format == XML {
+		x, err := xml.Marshal(output)
+		if err != nil {
+			return false
+		}
+
+		buf = string(x)
+	}
+
+	o.fd.WriteString(buf + "\n")
+
+	return true
+}
+
+// OutParseTypeStr converts from a string to a constant type
+// default is plaintext output
+func OutParseTypeStr(typ string) WriterFormat {
+	outType := strings.ToUpper(typ)
+	if outType == "JSON" {
+		return JSON
+	} else if outType == "XML" {
+		return XML
+	}
+
+	return plain
+}
+
+// ValidType makes sure that the passed type exists
+func ValidType(val WriterFormat) bool {
+	return val < end
+}
+
+// Close will close the file associated with the OutWriter
+func (o *OutWriter) Close() {
+	o.fd.Close()
+}
+
+// utilRound will round the score to two decimal places for output
+func utilRound(v float64) float64 {
+	return math.Round(v*100) / 100
+}
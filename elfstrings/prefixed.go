@@ -0,0 +1,310 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Prefix to emulate an enum of the lengths that strings may be prefixed by
+type Prefix int32
+
+// Prefixes of the strings that are not terminated, but stored after their
+// length as Pascal shortstrings and many serialization formats are
+const (
+	// PrefixNone is a string which is terminated rather than prefixed
+	PrefixNone Prefix = iota
+	// Prefix8 is a string after its length in a byte
+	Prefix8
+	// Prefix16LE is a string after its length in two bytes, little endian
+	Prefix16LE
+	// Prefix16BE is a string after its length in two bytes, big endian
+	Prefix16BE
+	// Prefix32LE is a string after its length in four bytes, little endian
+	Prefix32LE
+	// Prefix32BE is a string after its length in four bytes, big endian
+	Prefix32BE
+	prefixEnd
+)
+
+var prefixNames = map[Prefix]string{
+	PrefixNone: "none",
+	Prefix8:    "u8",
+	Prefix16LE: "u16le",
+	Prefix16BE: "u16be",
+	Prefix32LE: "u32le",
+	Prefix32BE: "u32be",
+}
+
+// prefixMaxLength is the longest prefixed string that is recovered, a
+// length any longer is more likely to be something else
+const prefixMaxLength = 0x10000
+
+// fpcPrefixes are the prefixes that are recovered from Free Pascal binaries
+// when none are given, its shortstrings are prefixed by a byte while its
+// other strings are terminated too
+var fpcPrefixes = []Prefix{Prefix8}
+
+// String will return the name of the prefix
+func (p Prefix) String() string {
+	if name, ok := prefixNames[p]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("prefix(%d)", int32(p))
+}
+
+// Width will return the size in bytes of the length
+func (p Prefix) Width() int {
+	switch p {
+	case Prefix8:
+		return 1
+	case Prefix16LE, Prefix16BE:
+		return 2
+	case Prefix32LE, Prefix32BE:
+		return 4
+	}
+
+	return 0
+}
+
+// length will read the length at the start of the buffer
+func (p Prefix) length(buf []byte) uint64 {
+	switch p {
+	case Prefix8:
+		return uint64(buf[0])
+	case Prefix16LE:
+		return uint64(binary.LittleEndian.Uint16(buf))
+	case Prefix16BE:
+		return uint64(binary.BigEndian.Uint16(buf))
+	case Prefix32LE:
+		return uint64(binary.LittleEndian.Uint32(buf))
+	case Prefix32BE:
+		return uint64(binary.BigEndian.Uint32(buf))
+	}
+
+	return 0
+}
+
+// PrefixedString is a string which was found after its length
+type PrefixedString struct {
+	// Offset is the offset of the text, just after its length
+	Offset uint64
+	Prefix Prefix
+	Raw    []byte
+}
+
+// ReaderIsFPC will check whether the binary was built by Free Pascal, which
+// leaves its version in the .fpc section and prefixes its own symbols
+func (r *ElfReader) ReaderIsFPC() bool {
+	if r.ExecReader.Section(".fpc") != nil {
+		return true
+	}
+
+	for _, load := range []func() ([]elf.Symbol, error){r.ExecReader.Symbols, r.ExecReader.DynamicSymbols} {
+		syms, err := load()
+		if err != nil {
+			continue
+		}
+
+		for _, sym := range syms {
+			if strings.HasPrefix(sym.Name, "FPC_") || strings.HasPrefix(sym.Name, "fpc_") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// readerPrefixes will return the prefixes of the strings to recover, those
+// of Free Pascal when none are given and the binary was built by it
+func (r *ElfReader) readerPrefixes(opts *Options) []Prefix {
+	if opts.Prefixes != nil {
+		return opts.Prefixes
+	}
+
+	if r.fpc == nil {
+		fpc := r.ReaderIsFPC()
+		r.fpc = &fpc
+	}
+
+	if *r.fpc {
+		return fpcPrefixes
+	}
+
+	return nil
+}
+
+// UtilPrefixedStrings will find the printable strings within the buffer
+// which are prefixed by their length, in any of the given prefixes. The
+// text must end just where its length says, at a byte that is not
+// printable, so that the runs of plain text are not cut into pieces
+func UtilPrefixedStrings(buf []byte, prefixes []Prefix, minLength uint64) []PrefixedString {
+	var found []PrefixedString
+
+	if minLength == 0 {
+		minLength = ScanMinLength
+	}
+
+	for i := 0; i < len(buf); {
+		var match *PrefixedString
+
+		for _, prefix := range prefixes {
+			width := prefix.Width()
+			if width == 0 || i+width > len(buf) {
+				continue
+			}
+
+			length := prefix.length(buf[i:])
+			start := i + width
+			end := start + int(length)
+			if length < minLength || length > prefixMaxLength || end > len(buf) {
+				continue
+			}
+
+			if end < len(buf) && UtilIsPrintable(buf[end]) {
+				continue
+			}
+
+			// a printable length just before the text could as well
+			// be the first character of a C string, which is how it
+			// is taken when it follows other text or ends at a NUL
+			if utilIsTextByte(buf[start-1]) {
+				if start > 1 && utilIsTextByte(buf[start-2]) || end == len(buf) || buf[end] == 0 {
+					continue
+				}
+			}
+
+			if raw := buf[start:end]; utilIsPrefixedText(raw) {
+				match = &PrefixedString{Offset: uint64(start), Prefix: prefix, Raw: raw}
+				break
+			}
+		}
+
+		if match == nil {
+			i++
+			continue
+		}
+
+		found = append(found, *match)
+		i = int(match.Offset) + len(match.Raw)
+	}
+
+	return found
+}
+
+// utilIsTextByte will check whether the byte is a character of text, the
+// printable characters and the whitespace that breaks them into lines
+func utilIsTextByte(b byte) bool {
+	return b >= ' ' && b <= '~' || b == '\n' || b == '\r'
+}
+
+// utilIsPrefixedText will check whether the bytes are printable ASCII or
+// UTF-8 throughout, without the terminator of a C string
+func utilIsPrefixedText(raw []byte) bool {
+	if !utf8.Valid(raw) {
+		return false
+	}
+
+	for _, b := range raw {
+		if !UtilIsPrintable(b) && b < utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PrefixParseStr converts a comma separated list of prefixes such as
+// "u8,u32le" into the prefixes, "all" selects every prefix and "none"
+// none of them. nil is returned for an empty list, which leaves the
+// prefixes to be picked for the binary
+func PrefixParseStr(list string) ([]Prefix, error) {
+	switch strings.TrimSpace(strings.ToLower(list)) {
+	case "":
+		return nil, nil
+	case "none":
+		return []Prefix{}, nil
+	case "all":
+		return []Prefix{Prefix32LE, Prefix32BE, Prefix16LE, Prefix16BE, Prefix8}, nil
+	}
+
+	prefixes := []Prefix{}
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		found := false
+		for prefix, prefixName := range prefixNames {
+			if prefixName == name && prefix != PrefixNone {
+				prefixes = append(prefixes, prefix)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unknown prefix %q", name)
+		}
+	}
+
+	return prefixes, nil
+}
+
+// readerExtractPrefixed will recover the prefixed strings of the section
+// and filter them, returning them along with the offsets which they and
+// their lengths cover, as the terminated strings found there are the
+// same strings run together with their lengths
+func (r *ElfReader) readerExtractPrefixed(s *elf.Section, sect []byte, opts *Options) ([]StringRecord, map[uint64]bool) {
+	var records []StringRecord
+
+	covered := make(map[uint64]bool)
+
+	prefixes := r.readerPrefixes(opts)
+	if len(prefixes) == 0 {
+		return nil, covered
+	}
+
+	for _, found := range UtilPrefixedStrings(sect, prefixes, opts.MinLength) {
+		raw, off, prefix := found.Raw, found.Offset, found.Prefix
+
+		kept := r.readerWithUnwrapped(raw, UtilFilterString(raw, opts), func(f *FilteredString) StringRecord {
+			rec := r.readerRecord(s, off, raw, f)
+			rec.Prefix = prefix
+
+			return rec
+		}, opts)
+
+		if len(kept) == 0 {
+			continue
+		}
+
+		records = append(records, kept...)
+		for i := off - uint64(prefix.Width()); i < off+uint64(len(raw)); i++ {
+			covered[i] = true
+		}
+	}
+
+	return records, covered
+}
+
+// utilIsCovered will check whether every byte of the string at the offset
+// is covered by the prefixed strings
+func utilIsCovered(covered map[uint64]bool, off uint64, size int) bool {
+	if len(covered) == 0 {
+		return false
+	}
+
+	for i := uint64(0); i < uint64(size); i++ {
+		if !covered[off+i] {
+			return false
+		}
+	}
+
+	return true
+}
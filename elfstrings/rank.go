@@ -0,0 +1,209 @@
+package elfstrings
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// weights of each of the signals in the relevance rank
+const (
+	rankReadabilityWeight = 0.25
+	rankLengthWeight      = 0.1
+	rankUniquenessWeight  = 0.1
+	rankIOCWeight         = 0.25
+	rankFormatWeight      = 0.1
+	rankReferencedWeight  = 0.2
+	// rankBoilerplateWeight is how much of the rank is taken away
+	// from strings which are certainly boilerplate
+	rankBoilerplateWeight = 0.8
+)
+
+// Rank is the relevance of a string to an analyst, broken down
+// into each of the signals it is made up of
+type Rank struct {
+	// Readability is the readability score of the string
+	Readability float64 `json:"readability" xml:"readability"`
+	// Length is how long the string is, longer strings carry more meaning
+	Length float64 `json:"length" xml:"length"`
+	// Uniqueness is how rarely the same text appears in the binary
+	Uniqueness float64 `json:"uniqueness" xml:"uniqueness"`
+	// IOC is how much the string looks like an indicator of compromise,
+	// such as a URL, an IP address or a file path
+	IOC float64 `json:"ioc" xml:"ioc"`
+	// Format is set when the string is a printf style format string
+	Format float64 `json:"format" xml:"format"`
+	// Referenced is set when the address of the string is referenced
+	Referenced float64 `json:"referenced" xml:"referenced"`
+	// Boilerplate is how likely the string is to come from the C
+	// library, the compiler or the linker rather than the program
+	Boilerplate float64 `json:"boilerplate" xml:"boilerplate"`
+	// Total is the weighted rank between 0 and 1
+	Total float64 `json:"total" xml:"total"`
+}
+
+// iocWeights are how strongly each type of indicator suggests that
+// a string is of interest when triaging a binary
+var iocWeights = map[IOCType]float64{
+	IOCURL:      1,
+	IOCIPv4:     1,
+	IOCIPv6:     1,
+	IOCEmail:    1,
+	IOCOnion:    1,
+	IOCBitcoin:  1,
+	IOCEthereum: 1,
+	IOCMonero:   1,
+	IOCPort:     0.9,
+	IOCDomain:   0.8,
+}
+
+// hintRegexes are the patterns of strings which are not indicators in
+// themselves but are often of interest, along with how strongly so
+var hintRegexes = []struct {
+	regex  *regexp.Regexp
+	weight float64
+}{
+	{regexp.MustCompile(`(?i)\bHKEY_[A-Z_]+\\|\\(Software|System)\\`), 0.8},
+	{regexp.MustCompile(`(?i)\b(bin/sh|bin/bash|cmd\.exe|powershell|wget|curl|chmod|crontab|/etc/passwd|/etc/shadow)\b`), 0.8},
+	{regexp.MustCompile(`^(/[\w.+-]+){2,}/?$|^[A-Za-z]:\\`), 0.5},
+}
+
+// boilerplateRegexes are the patterns of strings which are put into
+// nearly every binary by the C library, the compiler or the linker
+var boilerplateRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`^(GLIBC|GLIBCXX|CXXABI|GCC|GNU|LIBC)(_[\d.]+)?$`),
+	regexp.MustCompile(`^(GLIBC|GLIBCXX|CXXABI|GCC)_[\w.]+$`),
+	regexp.MustCompile(`^GCC: \(|^clang version |^Linker: |^GNU$`),
+	regexp.MustCompile(`^_ITM_|^__gmon_start__$|^_Jv_RegisterClasses$|^__cxa_|^__libc_|^__stack_chk_|^__gxx_personality|^_Unwind_|^__dso_handle$|^_(init|fini)$|^__(bss|data|init_array|fini_array)_(start|end)`),
+	regexp.MustCompile(`^(crtstuff|crti|crtn|crt1|start)\.[cS]$|^deregister_tm_clones$|^register_tm_clones$|^__do_global_dtors_aux|^frame_dummy`),
+	regexp.MustCompile(`^lib[\w+-]+\.so(\.\d+)*$|^/lib(64)?/ld-linux[\w.-]*\.so(\.\d+)*$`),
+	regexp.MustCompile(`^\.(text|data|bss|rodata|dynamic|dynsym|dynstr|symtab|strtab|shstrtab|interp|init|fini|plt|got|eh_frame|gnu|note|rela?|comment|tbss|tdata|init_array|fini_array|debug)[\w.]*$`),
+}
+
+// boilerplateSections are the sections whose strings all come from the
+// toolchain, and those which are made up of symbol names
+var boilerplateSections = map[string]float64{
+	".shstrtab":          1,
+	".comment":           1,
+	".interp":            1,
+	".note.gnu.build-id": 1,
+	".note.ABI-tag":      1,
+	".gnu.version_r":     1,
+	".dynstr":            0.3,
+	".strtab":            0.3,
+}
+
+// ReaderRank will rank each of the records by how relevant they are to an
+// analyst triaging the binary, and order them with the most relevant first.
+// The code is only taken as referencing a record through the xrefs already
+// attached to it, as disassembling every executable section again for the
+// ranking alone would cost as much as finding them
+func (r *ElfReader) ReaderRank(records []StringRecord) []StringRecord {
+	return UtilRankRecords(records, r.ReaderReferences())
+}
+
+// UtilRankRecords will rank each of the records by how relevant they are,
+// refs are the referenced addresses such as those from ReaderReferences,
+// and order them with the most relevant first
+func UtilRankRecords(records []StringRecord, refs map[uint64]int) []StringRecord {
+	counts := make(map[string]int)
+	for i := range records {
+		counts[records[i].Text]++
+	}
+
+	for i := range records {
+		rec := &records[i]
+		referenced := refs[rec.Address] != 0 && rec.Address != 0 || len(rec.Xrefs) != 0
+		rec.Rank = UtilRank(rec, counts[rec.Text], referenced)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Rank.Total != records[j].Rank.Total {
+			return records[i].Rank.Total > records[j].Rank.Total
+		}
+
+		return records[i].FileOffset < records[j].FileOffset
+	})
+
+	return records
+}
+
+// UtilRank will rank the record, count is the amount of times the same
+// text was found in the binary and referenced is whether its address
+// is referenced
+func UtilRank(rec *StringRecord, count int, referenced bool) *Rank {
+	var rank Rank
+
+	rank.Readability = rec.Score.Total
+
+	// a handful of characters say very little, while a sentence
+	// is as meaningful as a string is going to get
+	n := float64(utf8.RuneCountInString(rec.Text))
+	rank.Length = utilClamp(math.Log2(n/4) / 4)
+
+	if count != 0 {
+		rank.Uniqueness = 1 / float64(count)
+	}
+
+	rank.IOC = UtilIOCLikeness(rec.Text, rec.IOCs)
+
+	if formatRegex.MatchString(rec.Text) {
+		rank.Format = 1
+	}
+
+	if referenced {
+		rank.Referenced = 1
+	}
+
+	rank.Boilerplate = UtilBoilerplate(rec.Section, rec.Text)
+
+	rank.Total = rank.Readability*rankReadabilityWeight +
+		rank.Length*rankLengthWeight +
+		rank.Uniqueness*rankUniquenessWeight +
+		rank.IOC*rankIOCWeight +
+		rank.Format*rankFormatWeight +
+		rank.Referenced*rankReferencedWeight
+
+	rank.Total *= 1 - rank.Boilerplate*rankBoilerplateWeight
+
+	return &rank
+}
+
+// UtilIOCLikeness will score how much the text looks like an indicator
+// of compromise, between 0 and 1, iocs are those found within it
+func UtilIOCLikeness(text string, iocs []IOC) float64 {
+	var best float64
+
+	for _, ioc := range iocs {
+		if iocWeights[ioc.Type] > best {
+			best = iocWeights[ioc.Type]
+		}
+	}
+
+	for _, hint := range hintRegexes {
+		if hint.weight > best && hint.regex.MatchString(text) {
+			best = hint.weight
+		}
+	}
+
+	return best
+}
+
+// UtilBoilerplate will score how likely the string is to come from the
+// toolchain rather than the program itself, between 0 and 1
+func UtilBoilerplate(section string, text string) float64 {
+	score := boilerplateSections[section]
+	if strings.HasPrefix(section, ".note") || strings.HasPrefix(section, ".gnu.version") {
+		score = 1
+	}
+
+	for _, boilerplate := range boilerplateRegexes {
+		if boilerplate.MatchString(text) {
+			return 1
+		}
+	}
+
+	return score
+}
@@ -0,0 +1,145 @@
+package elfstrings
+
+import (
+	"bytes"
+	"debug/elf"
+	"errors"
+	"os"
+)
+
+// ElfReader instance containing information
+// about said ELF binary
+type ElfReader struct {
+	ExecReader *elf.File
+	File       *os.File
+
+	// functions and xrefs are built on first use
+	functions []function
+	xrefs     *xrefIndex
+	// fpc is whether the binary was built by Free Pascal, once checked
+	fpc *bool
+	// packed is whether the binary was built by Go or Rust, which pack
+	// their string literals back to back, once checked, and headers are
+	// its string headers which are found on first use
+	packed  *bool
+	headers map[uint64][]uint64
+	// lastID is the ID of the last record that was created
+	lastID uint64
+}
+
+// NewELFReader will create a new instance of ElfReader
+func NewELFReader(path string) (*ElfReader, error) {
+	var r ElfReader
+	var err error
+
+	r.File, err = os.OpenFile(path, os.O_RDONLY, os.ModePerm)
+	if err != nil {
+		return nil, errors.New("failed to open the file")
+	}
+
+	r.ExecReader, err = elf.NewFile(r.File)
+	if err != nil {
+		return nil, errors.New("failed to parse the ELF file succesfully")
+	}
+
+	return &r, nil
+}
+
+// ReaderParseSection will parse the ELF section and
+// return an array of bytes containing the content
+// of the section, using the file instance..
+func (r *ElfReader) ReaderParseSection(name string) []byte {
+	return r.ReaderReadSection(r.ExecReader.Section(name))
+}
+
+// ReaderReadSection will return the content of the section, using the
+// file instance, or nil when it has no content in the file
+func (r *ElfReader) ReaderReadSection(s *elf.Section) []byte {
+	if s == nil {
+		return nil
+	}
+
+	if s.Type == elf.SHT_NOBITS {
+		return nil
+	}
+
+	buf, err := r.ReaderReadAt(s.Offset, s.Size)
+	if err != nil {
+		return nil
+	}
+
+	return buf
+}
+
+// ReaderReadAt will read size bytes from the given offset in the file,
+// this does not depend on the section headers so it can be used to read
+// any region of the binary
+func (r *ElfReader) ReaderReadAt(offset uint64, size uint64) ([]byte, error) {
+	stat, err := r.File.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if offset > uint64(stat.Size()) || size > uint64(stat.Size())-offset {
+		return nil, errors.New("the region is outside of the file")
+	}
+
+	buf := make([]byte, size)
+
+	_, err = r.File.ReadAt(buf, int64(offset))
+	if err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// ReaderSectionAt will find the section which contains the
+// given file offset, or nil if it is not within a section
+func (r *ElfReader) ReaderSectionAt(fileOff uint64) *elf.Section {
+	for _, s := range r.ExecReader.Sections {
+		if s.Type == elf.SHT_NULL || s.Type == elf.SHT_NOBITS {
+			continue
+		}
+
+		if fileOff >= s.Offset && fileOff < s.Offset+s.Size {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// ReaderParseStrings will parse the strings by a null terminator
+// and then place them into an [offset => string] type map
+// alignment does not matter here, as when \x00 exists more than once
+// it will simply be skipped, while still being counted in the offset.
+func (r *ElfReader) ReaderParseStrings(buf []byte) map[uint64][]byte {
+	var slice [][]byte
+	if slice = bytes.Split(buf, []byte("\x00")); slice == nil {
+		return nil
+	}
+
+	strings := make(map[uint64][]byte, len(slice))
+	length := uint64(len(slice))
+
+	var offset uint64
+
+	for i := uint64(0); i < length; i++ {
+		if len(slice[i]) != 0 {
+			strings[offset] = slice[i]
+		}
+
+		// every chunk, even an empty one, is followed by a terminator
+		offset += (uint64(len(slice[i])) + 1)
+	}
+
+	return strings
+}
+
+// Close softly close all of the instances associated
+// with the ElfReader
+func (r *ElfReader) Close() {
+	r.ExecReader.Close()
+	r.File.Close()
+}
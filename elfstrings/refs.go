@@ -0,0 +1,82 @@
+package elfstrings
+
+import (
+	"debug/elf"
+)
+
+// ReaderReferences will find the virtual addresses which are referenced
+// by the binary, along with how many times each is referenced. These are
+// the pointers stored in the loaded data sections, and the addends of the
+// dynamic relocations which fill in those pointers in position
+// independent binaries
+func (r *ElfReader) ReaderReferences() map[uint64]int {
+	refs := make(map[uint64]int)
+
+	if r.ExecReader.Type == elf.ET_REL {
+		return refs
+	}
+
+	size := 4
+	if r.ExecReader.Class == elf.ELFCLASS64 {
+		size = 8
+	}
+
+	order := r.ExecReader.ByteOrder
+
+	for _, s := range r.ExecReader.Sections {
+		if s.Type == elf.SHT_NOBITS {
+			continue
+		}
+
+		switch {
+		case s.Type == elf.SHT_RELA:
+			r.readerRelaAddends(s, size, refs)
+		case s.Flags&elf.SHF_ALLOC != 0 && s.Flags&elf.SHF_EXECINSTR == 0:
+			buf, err := r.ReaderReadAt(s.Offset, s.Size)
+			if err != nil {
+				continue
+			}
+
+			for i := 0; i+size <= len(buf); i += size {
+				var ptr uint64
+				if size == 8 {
+					ptr = order.Uint64(buf[i:])
+				} else {
+					ptr = uint64(order.Uint32(buf[i:]))
+				}
+
+				if ptr != 0 {
+					refs[ptr]++
+				}
+			}
+		}
+	}
+
+	return refs
+}
+
+// readerRelaAddends will add the addends of the relocations in the
+// section to the references, size is the size of a pointer
+func (r *ElfReader) readerRelaAddends(s *elf.Section, size int, refs map[uint64]int) {
+	buf, err := r.ReaderReadAt(s.Offset, s.Size)
+	if err != nil {
+		return
+	}
+
+	order := r.ExecReader.ByteOrder
+
+	// each entry is the offset, the info and then the addend
+	entry := size * 3
+	for i := 0; i+entry <= len(buf); i += entry {
+		var addend uint64
+		if size == 8 {
+			addend = order.Uint64(buf[i+16:])
+		} else {
+			addend = uint64(order.Uint32(buf[i+8:]))
+		}
+
+		if addend != 0 {
+			refs[addend]++
+		}
+	}
+}
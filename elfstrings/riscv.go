@@ -0,0 +1,180 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"encoding/binary"
+)
+
+// utilXrefsRISCV will decode the RISC-V code at addr and pass each address
+// that it references to add. Addresses are built from an auipc or lui of
+// the upper bits followed by an addi or a load of the lower twelve bits,
+// which may be compressed, or are relative to gp once the linker has
+// relaxed the pair into a single instruction
+func utilXrefsRISCV(code []byte, addr uint64, gp uint64, mem *memory, add func(from uint64, to uint64)) {
+	var regs pairs
+
+	mask := ^uint64(0)
+	size := 8
+	if mem.r.ExecReader.Class == elf.ELFCLASS32 {
+		mask, size = 0xffffffff, 4
+	}
+
+	// base will return the value of rs1, gp is known everywhere
+	base := func(rs1 uint32, pc uint64) (uint64, bool) {
+		if rs1 == 3 && gp != 0 {
+			return gp, true
+		}
+
+		return regs.get(rs1, pc)
+	}
+
+	// load will add the target of a load, and the pointer it loads
+	// when it is the size of one as it may be from the GOT
+	load := func(pc uint64, target uint64, width int) {
+		add(pc, target)
+
+		if width == size {
+			if ptr, ok := mem.load(target, size); ok {
+				add(pc, ptr)
+			}
+		}
+	}
+
+	for off := 0; off+2 <= len(code); {
+		pc := addr + uint64(off)
+
+		// instructions are always little endian
+		if code[off]&3 != 3 {
+			h := uint32(binary.LittleEndian.Uint16(code[off:]))
+			off += 2
+
+			utilXrefsRVC(h, pc, size, mask, &regs, base, load, add)
+			continue
+		}
+
+		if off+4 > len(code) {
+			break
+		}
+
+		w := binary.LittleEndian.Uint32(code[off:])
+		rd := (w >> 7) & 31
+		rs1 := (w >> 15) & 31
+		imm := utilSignExtend(uint64(w>>20), 12)
+
+		off += 4
+
+		switch w & 0x7f {
+		case 0x17:
+			// auipc rd, imm20
+			regs.set(rd, (pc+utilSignExtend(uint64(w&0xfffff000), 32))&mask, pc)
+			continue
+		case 0x37:
+			// lui rd, imm20
+			regs.set(rd, utilSignExtend(uint64(w&0xfffff000), 32)&mask, pc)
+			continue
+		case 0x13:
+			// addi rd, rs1, imm12
+			if v, ok := base(rs1, pc); ok && (w>>12)&7 == 0 {
+				target := (v + imm) & mask
+				add(pc, target)
+				regs.set(rd, target, pc)
+				continue
+			}
+		case 0x03:
+			// loads, with the width in the lower bits of funct3
+			if v, ok := base(rs1, pc); ok {
+				load(pc, (v+imm)&mask, 1<<((w>>12)&3))
+			}
+		case 0x23, 0x63:
+			// stores and branches do not write to a register
+			continue
+		}
+
+		if rd != 0 {
+			regs.clear(rd)
+		}
+	}
+}
+
+// utilXrefsRVC will decode the compressed instruction h, only the forms
+// that can complete a pair are followed, the rest forget the register they
+// write to which is in bits 7-11 for most of them
+func utilXrefsRVC(h uint32, pc uint64, size int, mask uint64, regs *pairs, base func(uint32, uint64) (uint64, bool), load func(uint64, uint64, int), add func(uint64, uint64)) {
+	rd := (h >> 7) & 31
+
+	// the registers x8-x15 of the instructions with 3 bit fields
+	rs1c := 8 + (h>>7)&7
+	rdc := 8 + (h>>2)&7
+
+	switch h&3 | (h>>13)<<2 {
+	case 0x01:
+		// c.addi rd, imm6
+		if v, ok := regs.get(rd, pc); ok && rd != 0 {
+			imm := utilSignExtend(uint64((h>>12)&1)<<5|uint64((h>>2)&31), 6)
+			target := (v + imm) & mask
+			add(pc, target)
+			regs.set(rd, target, pc)
+			return
+		}
+	case 0x0d:
+		// c.lui rd, imm6
+		if rd != 0 && rd != 2 {
+			imm := utilSignExtend(uint64((h>>12)&1)<<17|uint64((h>>2)&31)<<12, 18)
+			regs.set(rd, imm&mask, pc)
+			return
+		}
+	case 0x08:
+		// c.lw rd', uimm(rs1')
+		if v, ok := base(rs1c, pc); ok {
+			imm := uint64((h>>10)&7)<<3 | uint64((h>>6)&1)<<2 | uint64((h>>5)&1)<<6
+			load(pc, (v+imm)&mask, 4)
+		}
+
+		regs.clear(rdc)
+		return
+	case 0x0c:
+		// c.ld rd', uimm(rs1') on RV64, c.flw on RV32
+		if v, ok := base(rs1c, pc); ok && size == 8 {
+			imm := uint64((h>>10)&7)<<3 | uint64((h>>5)&3)<<6
+			load(pc, (v+imm)&mask, 8)
+		}
+
+		regs.clear(rdc)
+		return
+	case 0x00:
+		// c.addi4spn rd', uimm
+		regs.clear(rdc)
+		return
+	case 0x11:
+		// the arithmetic of rd', rs2'
+		regs.clear(rs1c)
+		return
+	case 0x04, 0x14, 0x18, 0x1c, 0x15, 0x19, 0x1d, 0x16, 0x1a, 0x1e:
+		// the floating point loads, stores, jumps and branches do not
+		// write to an integer register
+		return
+	}
+
+	if rd != 0 {
+		regs.clear(rd)
+	}
+}
+
+// readerRISCVGP will find the value of the global pointer that the linker
+// relaxes references to, from the __global_pointer$ symbol
+func (r *ElfReader) readerRISCVGP() uint64 {
+	for _, load := range []func() ([]elf.Symbol, error){r.ExecReader.Symbols, r.ExecReader.DynamicSymbols} {
+		syms, err := load()
+		if err != nil {
+			continue
+		}
+
+		for _, sym := range syms {
+			if sym.Name == "__global_pointer$" {
+				return sym.Value
+			}
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,590 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The limits of the fields of a typeinfo and a vtable, which hold back the
+// words of the data sections that only look like one
+const (
+	rttiMaxName        = 0x1000
+	rttiMaxBases       = 64
+	rttiMaxOffsetToTop = 1 << 20
+)
+
+// TypeInfoKind to emulate an enum of the kinds of typeinfo of a class
+type TypeInfoKind int32
+
+// Kinds of the typeinfo of a class in the Itanium C++ ABI
+const (
+	// TypeInfoExternal is a class whose typeinfo is in a shared library,
+	// which is only known from the relocations against it
+	TypeInfoExternal TypeInfoKind = iota
+	// TypeInfoClass is a __class_type_info, of a class without bases
+	TypeInfoClass
+	// TypeInfoSingle is a __si_class_type_info, of a class with a single
+	// public base at the start of it
+	TypeInfoSingle
+	// TypeInfoMultiple is a __vmi_class_type_info, of a class with more
+	// than one base, or with a virtual or non public base
+	TypeInfoMultiple
+	typeInfoEnd
+)
+
+var typeInfoNames = map[TypeInfoKind]string{
+	TypeInfoExternal: "external",
+	TypeInfoClass:    "class",
+	TypeInfoSingle:   "si_class",
+	TypeInfoMultiple: "vmi_class",
+}
+
+// rttiVTables are the vtables of the typeinfo classes of the C++ runtime,
+// which the typeinfo of each class points two words into
+var rttiVTables = map[string]TypeInfoKind{
+	"_ZTVN10__cxxabiv117__class_type_infoE":     TypeInfoClass,
+	"_ZTVN10__cxxabiv120__si_class_type_infoE":  TypeInfoSingle,
+	"_ZTVN10__cxxabiv121__vmi_class_type_infoE": TypeInfoMultiple,
+}
+
+// String will return the name of the kind of typeinfo
+func (k TypeInfoKind) String() string {
+	if name, ok := typeInfoNames[k]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("typeinfo(%d)", int32(k))
+}
+
+// ClassBase is a class that a C++ class inherits from
+type ClassBase struct {
+	Name string
+	// Offset is where the base is within the class, or for a virtual base
+	// where the vtable holds where it is
+	Offset  int64
+	Virtual bool
+	Public  bool
+}
+
+// ClassVTable is a vtable that the objects of a C++ class point to
+type ClassVTable struct {
+	// Address is the virtual address of the offset to the top of the
+	// object, the offsets of the virtual bases come before it
+	Address uint64
+	// OffsetToTop is where the pointer to the vtable is within the
+	// object, zero for its primary vtable and negative for those of its
+	// bases after the first
+	OffsetToTop int64
+}
+
+// CxxClass is a C++ class recovered from its run time type information
+type CxxClass struct {
+	Name string
+	// Mangled is the name that the typeinfo holds, the class as it is
+	// mangled without the _Z that symbols start with
+	Mangled string
+	// TypeInfo is the virtual address of the typeinfo, zero when it is in
+	// a shared library
+	TypeInfo uint64
+	Kind     TypeInfoKind
+	Bases    []ClassBase
+	// Derived are the names of the classes which inherit from it
+	Derived []string
+	VTables []ClassVTable
+}
+
+// ReaderClasses will recover the C++ classes of the binary from their
+// typeinfo, along with the classes they inherit from and their vtables.
+// The kind of each typeinfo is known from the vtable of the C++ runtime
+// that it points to, which the symbols and relocations name. In stripped
+// static binaries it is told from how the typeinfo is laid out instead
+func (r *ElfReader) ReaderClasses(opts *DemangleOptions) ([]CxxClass, error) {
+	if opts == nil {
+		opts = &DemangleOptions{}
+	}
+
+	if r.ExecReader.Type == elf.ET_REL {
+		return nil, errors.New("no C++ run time type information")
+	}
+
+	word := uint64(4)
+	if r.ExecReader.Class == elf.ELFCLASS64 {
+		word = 8
+	}
+
+	mem := r.readerMemory()
+	named := r.readerSymbolRelocs()
+
+	// the typeinfo points two words into the vtable of its kind, past the
+	// offset to the top and the typeinfo of the typeinfo
+	kinds := make(map[uint64]TypeInfoKind)
+	others := make(map[uint64]bool)
+	for _, load := range []func() ([]elf.Symbol, error){r.ExecReader.Symbols, r.ExecReader.DynamicSymbols} {
+		syms, err := load()
+		if err != nil {
+			continue
+		}
+
+		for _, sym := range syms {
+			if sym.Value == 0 || !strings.HasPrefix(sym.Name, "_ZTVN10__cxxabiv1") {
+				continue
+			}
+
+			if kind, ok := rttiVTables[sym.Name]; ok {
+				kinds[sym.Value+2*word] = kind
+			} else {
+				others[sym.Value+2*word] = true
+			}
+		}
+	}
+
+	// the words are walked over what could be read of each section, as
+	// the size in the section header need not fit the file or the
+	// address space
+	var data []dataRange
+	for _, s := range r.ExecReader.Sections {
+		if s.Type != elf.SHT_PROGBITS || s.Flags&elf.SHF_ALLOC == 0 || s.Flags&elf.SHF_EXECINSTR != 0 || s.Addr == 0 {
+			continue
+		}
+
+		if s.Addr+s.Size < s.Addr {
+			continue
+		}
+
+		buf, err := r.ReaderReadAt(s.Offset, s.Size)
+		if err != nil {
+			continue
+		}
+
+		data = append(data, dataRange{start: s.Addr, end: s.Addr + uint64(len(buf))})
+	}
+
+	pointer := func(addr uint64) uint64 {
+		ptr, _ := mem.load(addr, int(word))
+		return ptr
+	}
+
+	signed := func(addr uint64) int64 {
+		if word == 4 {
+			return int64(int32(pointer(addr)))
+		}
+
+		return int64(pointer(addr))
+	}
+
+	// every pair of words where the second points to the mangled name of
+	// a class may be a typeinfo, those whose kind is not named are put
+	// together by the vtable they point to
+	mangled := make(map[uint64]string)
+	found := make(map[uint64]TypeInfoKind)
+	groups := make(map[uint64][]uint64)
+	for _, rng := range data {
+		for addr := rng.start; addr+2*word <= rng.end; addr += word {
+			name, ok := mem.loadString(pointer(addr+word), rttiMaxName)
+			if !ok || !utilIsClassName(name) {
+				continue
+			}
+
+			if sym, ok := named[addr]; ok {
+				if kind, ok := rttiVTables[sym]; ok {
+					mangled[addr], found[addr] = name, kind
+				}
+
+				continue
+			}
+
+			vptr := pointer(addr)
+			if kind, ok := kinds[vptr]; ok {
+				mangled[addr], found[addr] = name, kind
+			} else if !others[vptr] && vptr != 0 {
+				mangled[addr] = name
+				groups[vptr] = append(groups[vptr], addr)
+			}
+		}
+	}
+
+	isTypeInfo := func(addr uint64) bool {
+		_, ok := mangled[addr]
+		return ok
+	}
+
+	isBase := func(addr uint64) bool {
+		if sym, ok := named[addr]; ok {
+			return strings.HasPrefix(sym, "_ZTI")
+		}
+
+		return isTypeInfo(pointer(addr))
+	}
+
+	// the bases of a __vmi_class_type_info follow its flags and how many
+	// there are, each as a pointer to its typeinfo and a word of flags
+	vmiBases := func(addr uint64) (uint64, bool) {
+		count, ok := mem.load(addr+2*word+4, 4)
+		if !ok || count == 0 || count > rttiMaxBases {
+			return 0, false
+		}
+
+		for i := uint64(0); i < count; i++ {
+			if !isBase(addr + 2*word + 8 + i*2*word) {
+				return 0, false
+			}
+		}
+
+		return count, true
+	}
+
+	// without the symbols the kinds are told apart by their layout, those
+	// whose every typeinfo is followed by a pointer to another are of a
+	// single base, and those whose every typeinfo is followed by a list
+	// of them are of multiple bases. The rest are of classes without bases
+	// when any typeinfo of them is a base, which leaves out the typeinfo
+	// of enums and that of the data which only looks like it
+	var rest []uint64
+	for vptr, addrs := range groups {
+		single, multiple := true, true
+		for _, addr := range addrs {
+			single = single && isBase(addr+2*word)
+			if _, ok := vmiBases(addr); !ok {
+				multiple = false
+			}
+		}
+
+		switch {
+		case single:
+			for _, addr := range addrs {
+				found[addr] = TypeInfoSingle
+			}
+		case multiple:
+			for _, addr := range addrs {
+				found[addr] = TypeInfoMultiple
+			}
+		default:
+			rest = append(rest, vptr)
+		}
+	}
+
+	bases := make(map[uint64]bool)
+	for addr, kind := range found {
+		switch kind {
+		case TypeInfoSingle:
+			bases[pointer(addr+2*word)] = true
+		case TypeInfoMultiple:
+			count, _ := vmiBases(addr)
+			for i := uint64(0); i < count; i++ {
+				bases[pointer(addr+2*word+8+i*2*word)] = true
+			}
+		}
+	}
+
+	for _, vptr := range rest {
+		var isClass bool
+		for _, addr := range groups[vptr] {
+			isClass = isClass || bases[addr]
+		}
+
+		for _, addr := range groups[vptr] {
+			if isClass {
+				found[addr] = TypeInfoClass
+			}
+		}
+	}
+
+	classes := make(map[uint64]*CxxClass)
+	for addr, kind := range found {
+		name, err := utilDemangleType(strings.TrimPrefix(mangled[addr], "*"), opts)
+		if err != nil {
+			continue
+		}
+
+		classes[addr] = &CxxClass{Name: name, Mangled: strings.TrimPrefix(mangled[addr], "*"), TypeInfo: addr, Kind: kind}
+	}
+
+	// the bases in shared libraries are only known from the symbols of
+	// the relocations against their typeinfo
+	external := make(map[string]*CxxClass)
+	baseName := func(addr uint64) (string, bool) {
+		if sym, ok := named[addr]; ok {
+			if !strings.HasPrefix(sym, "_ZTI") {
+				return "", false
+			}
+
+			cls, ok := external[sym]
+			if !ok {
+				name, err := utilDemangleType(sym[4:], opts)
+				if err != nil {
+					return "", false
+				}
+
+				cls = &CxxClass{Name: name, Mangled: sym[4:], Kind: TypeInfoExternal}
+				external[sym] = cls
+			}
+
+			return cls.Name, true
+		}
+
+		if cls, ok := classes[pointer(addr)]; ok {
+			return cls.Name, true
+		}
+
+		return "", false
+	}
+
+	// the words of the typeinfo are not vtables even when they look like
+	// them, as the bases of a __vmi_class_type_info do
+	covered := make(map[uint64]bool)
+	for addr, cls := range classes {
+		size := 2 * word
+		switch cls.Kind {
+		case TypeInfoSingle:
+			size = 3 * word
+			if name, ok := baseName(addr + 2*word); ok {
+				cls.Bases = append(cls.Bases, ClassBase{Name: name, Public: true})
+			}
+		case TypeInfoMultiple:
+			count, _ := vmiBases(addr)
+			size = 2*word + 8 + count*2*word
+			for i := uint64(0); i < count; i++ {
+				at := addr + 2*word + 8 + i*2*word
+				name, ok := baseName(at)
+				if !ok {
+					continue
+				}
+
+				flags := signed(at + word)
+				cls.Bases = append(cls.Bases, ClassBase{
+					Name:    name,
+					Offset:  flags >> 8,
+					Virtual: flags&1 != 0,
+					Public:  flags&2 != 0,
+				})
+			}
+		}
+
+		for off := uint64(0); off < size; off += word {
+			covered[addr+off] = true
+		}
+	}
+
+	// a vtable holds the offset to the top of the object and then the
+	// typeinfo, which is followed by the virtual functions. The first of
+	// them is either in the code or filled in by a relocation, as it is
+	// for the pure virtual functions, after the empty slot that those of
+	// the virtual bases may have
+	isFunction := func(slot uint64) bool {
+		for end := slot + 2*word; slot < end; slot += word {
+			if _, ok := named[slot]; ok {
+				return true
+			}
+
+			if ptr := pointer(slot); ptr != 0 {
+				fn := r.readerSectionOfAddress(ptr)
+				return fn != nil && fn.Flags&elf.SHF_EXECINSTR != 0
+			}
+		}
+
+		return false
+	}
+
+	for _, rng := range data {
+		for addr := rng.start + word; addr+2*word <= rng.end; addr += word {
+			cls, ok := classes[pointer(addr)]
+			if !ok || covered[addr] {
+				continue
+			}
+
+			top := signed(addr - word)
+			if top > 0 || top < -rttiMaxOffsetToTop || top%int64(word) != 0 {
+				continue
+			}
+
+			if !isFunction(addr + word) {
+				continue
+			}
+
+			cls.VTables = append(cls.VTables, ClassVTable{Address: addr - word, OffsetToTop: top})
+		}
+	}
+
+	var all []CxxClass
+	for _, cls := range classes {
+		all = append(all, *cls)
+	}
+
+	for _, cls := range external {
+		all = append(all, *cls)
+	}
+
+	if len(all) == 0 {
+		return nil, errors.New("no C++ run time type information")
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Name != all[j].Name {
+			return all[i].Name < all[j].Name
+		}
+
+		return all[i].TypeInfo < all[j].TypeInfo
+	})
+
+	index := make(map[string]int)
+	for i := range all {
+		index[all[i].Name] = i
+	}
+
+	for i := range all {
+		sort.Slice(all[i].VTables, func(a, b int) bool {
+			return all[i].VTables[a].Address < all[i].VTables[b].Address
+		})
+
+		for _, base := range all[i].Bases {
+			if j, ok := index[base.Name]; ok {
+				all[j].Derived = append(all[j].Derived, all[i].Name)
+			}
+		}
+	}
+
+	return all, nil
+}
+
+// readerSymbolRelocs will name the words of the binary that relocations
+// against a symbol fill in by that symbol, as the file only holds zero or
+// the addend for them
+func (r *ElfReader) readerSymbolRelocs() map[uint64]string {
+	named := make(map[uint64]string)
+
+	size := uint64(4)
+	if r.ExecReader.Class == elf.ELFCLASS64 {
+		size = 8
+	}
+
+	order := r.ExecReader.ByteOrder
+	for _, s := range r.ExecReader.Sections {
+		if s.Type != elf.SHT_RELA || int(s.Link) >= len(r.ExecReader.Sections) {
+			continue
+		}
+
+		var syms []elf.Symbol
+		var err error
+		switch r.ExecReader.Sections[s.Link].Type {
+		case elf.SHT_DYNSYM:
+			syms, err = r.ExecReader.DynamicSymbols()
+		case elf.SHT_SYMTAB:
+			syms, err = r.ExecReader.Symbols()
+		default:
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		buf, err := r.ReaderReadAt(s.Offset, s.Size)
+		if err != nil {
+			continue
+		}
+
+		for i := uint64(0); i+size*3 <= uint64(len(buf)); i += size * 3 {
+			var off, sym uint64
+			if size == 8 {
+				off, sym = order.Uint64(buf[i:]), order.Uint64(buf[i+8:])>>32
+			} else {
+				off, sym = uint64(order.Uint32(buf[i:])), uint64(order.Uint32(buf[i+4:])>>8)
+			}
+
+			if sym != 0 && sym <= uint64(len(syms)) {
+				named[off] = syms[sym-1].Name
+			}
+		}
+	}
+
+	return named
+}
+
+// utilIsClassName will check whether the name in a typeinfo is that of a
+// class, which is a name, a nested name, one in std or a local name. The
+// names of the types which are not classes start with other letters. A *
+// before it marks a name which is compared by its address
+func utilIsClassName(name string) bool {
+	name = strings.TrimPrefix(name, "*")
+	if len(name) < 2 || !strings.ContainsRune("0123456789NSZ", rune(name[0])) {
+		return false
+	}
+
+	return tokenRegex.FindString(name) == name
+}
+
+// UtilClassGraph will write the classes as a graph in the DOT language,
+// with an edge from each class to each of its bases
+func UtilClassGraph(classes []CxxClass) string {
+	var graph strings.Builder
+
+	graph.WriteString("digraph classes {\n\trankdir=BT;\n\tnode [shape=box];\n")
+
+	for i := range classes {
+		cls := &classes[i]
+
+		label := []string{utilDotEscape(cls.Name)}
+		for _, vt := range cls.VTables {
+			label = append(label, fmt.Sprintf("vtable %#x", vt.Address))
+		}
+
+		style := ""
+		if cls.Kind == TypeInfoExternal {
+			style = ", style=dashed"
+		}
+
+		fmt.Fprintf(&graph, "\t\"%s\" [label=\"%s\"%s];\n", utilDotEscape(cls.Name), strings.Join(label, "\\n"), style)
+	}
+
+	for i := range classes {
+		for _, base := range classes[i].Bases {
+			var attrs []string
+			if base.Virtual {
+				attrs = append(attrs, "style=dashed")
+			}
+
+			if label := UtilBaseSpecifier(&base); label != "" {
+				attrs = append(attrs, "label=\""+label+"\"")
+			}
+
+			fmt.Fprintf(&graph, "\t\"%s\" -> \"%s\"", utilDotEscape(classes[i].Name), utilDotEscape(base.Name))
+			if attrs != nil {
+				fmt.Fprintf(&graph, " [%s]", strings.Join(attrs, ", "))
+			}
+
+			graph.WriteString(";\n")
+		}
+	}
+
+	graph.WriteString("}\n")
+
+	return graph.String()
+}
+
+// UtilBaseSpecifier will describe how the base is inherited when it is not
+// the usual public base at the start of the class, such as "virtual" or
+// "private +0x10", empty otherwise
+func UtilBaseSpecifier(base *ClassBase) string {
+	var spec []string
+	if base.Virtual {
+		spec = append(spec, "virtual")
+	}
+
+	if !base.Public {
+		spec = append(spec, "private")
+	}
+
+	if !base.Virtual && base.Offset != 0 {
+		spec = append(spec, fmt.Sprintf("+%#x", base.Offset))
+	}
+
+	return strings.Join(spec, " ")
+}
+
+// utilDotEscape will escape the text to be quoted in the DOT language
+func utilDotEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text)
+}
@@ -0,0 +1,98 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"reflect"
+	"testing"
+)
+
+func TestClasses(t *testing.T) {
+	const (
+		text   = 0x401000
+		rodata = 0x402000
+		data   = 0x403000
+
+		// where the typeinfo of each kind points, into the vtables of the
+		// typeinfo classes in the C++ runtime
+		classVPtr  = 0x404010
+		singleVPtr = 0x404110
+		multiVPtr  = 0x404210
+
+		base, other, derived, multi = data, data + 0x10, data + 0x20, data + 0x38
+	)
+
+	names := []byte("4Base\x005Other\x007Derived\x005Multi\x00")
+	name := func(off uint64) uint64 { return rodata + off }
+
+	// Multi inherits from Base and, eight bytes into it, from Other
+	section := testWords(
+		classVPtr, name(0),
+		classVPtr, name(6),
+		singleVPtr, name(13), base,
+		multiVPtr, name(22), 2<<32, base, 0<<8|2, other, 8<<8|2,
+		0,
+		0, derived, text,
+		0, multi, text, ^uint64(7), multi, text+1,
+		0, base, text,
+	)
+
+	want := []CxxClass{
+		{Name: "Base", Mangled: "4Base", TypeInfo: base, Kind: TypeInfoClass,
+			Derived: []string{"Derived", "Multi"},
+			VTables: []ClassVTable{{Address: data + 0xc0}}},
+		{Name: "Derived", Mangled: "7Derived", TypeInfo: derived, Kind: TypeInfoSingle,
+			Bases:   []ClassBase{{Name: "Base", Public: true}},
+			VTables: []ClassVTable{{Address: data + 0x78}}},
+		{Name: "Multi", Mangled: "5Multi", TypeInfo: multi, Kind: TypeInfoMultiple,
+			Bases:   []ClassBase{{Name: "Base", Public: true}, {Name: "Other", Offset: 8, Public: true}},
+			VTables: []ClassVTable{{Address: data + 0x90}, {Address: data + 0xa8, OffsetToTop: -8}}},
+		{Name: "Other", Mangled: "5Other", TypeInfo: other, Kind: TypeInfoClass,
+			Derived: []string{"Multi"}},
+	}
+
+	runtime := []testSymbol{
+		{name: "_ZTVN10__cxxabiv117__class_type_infoE", typ: elf.STT_OBJECT, value: classVPtr - 16, section: uint16(elf.SHN_ABS)},
+		{name: "_ZTVN10__cxxabiv120__si_class_type_infoE", typ: elf.STT_OBJECT, value: singleVPtr - 16, section: uint16(elf.SHN_ABS)},
+		{name: "_ZTVN10__cxxabiv121__vmi_class_type_infoE", typ: elf.STT_OBJECT, value: multiVPtr - 16, section: uint16(elf.SHN_ABS)},
+	}
+
+	tests := []struct {
+		name    string
+		symbols []testSymbol
+		size    uint64
+		want    []CxxClass
+	}{
+		// the kinds are named by the symbols of the vtables they point to
+		{"symbols", runtime, 0, want},
+		// without them they are told apart by the layout of the typeinfo
+		{"stripped", nil, 0, want},
+		// a section larger than the file is left out rather than walked
+		{"oversized", nil, 1 << 62, nil},
+	}
+
+	for _, tt := range tests {
+		r := testELF(t, []testSection{
+			{name: ".text", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC | elf.SHF_EXECINSTR, addr: text, data: []byte{0xc3, 0xc3}},
+			{name: ".rodata", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC, addr: rodata, data: names},
+			{name: ".data.rel.ro", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC | elf.SHF_WRITE, addr: data, size: tt.size, data: section},
+		}, tt.symbols)
+
+		got, err := r.ReaderClasses(nil)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("%s: recovered %+v, want an error", tt.name, got)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: recovered\n%+v\nwant\n%+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package elfstrings
+
+import (
+	"bytes"
+	"debug/elf"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The limits of the fields of a panic location, which hold back the words
+// of the data sections that only look like one
+const (
+	rustMaxFile   = 0x1000
+	rustMaxLine   = 1 << 20
+	rustMaxColumn = 1 << 12
+)
+
+var (
+	// rustVersionRegex matches the version that rustc leaves in .comment
+	rustVersionRegex = regexp.MustCompile(`rustc version ([^\x00]+)`)
+	// rustCommitRegex matches the commit of rustc in the paths of the
+	// standard library, which are remapped to be under /rustc/
+	rustCommitRegex = regexp.MustCompile(`/rustc/([0-9a-f]{40})/`)
+	// rustRegistryRegex matches the name and version of a crate in the
+	// paths of the sources that cargo downloaded from a registry
+	rustRegistryRegex = regexp.MustCompile(`[/\\]registry[/\\]src[/\\][^/\\\x00]+[/\\]([A-Za-z0-9_\-]+)-([0-9]+\.[0-9]+\.[0-9]+[A-Za-z0-9.+\-]*)[/\\]`)
+	// rustGitRegex matches the name and revision of a crate in the paths
+	// of the sources that cargo checked out from git
+	rustGitRegex = regexp.MustCompile(`[/\\]git[/\\]checkouts[/\\]([A-Za-z0-9_\-]+)-[0-9a-f]{16}[/\\]([0-9a-f]{7,40})[/\\]`)
+	// rustDepsRegex matches the name and version of a crate that the
+	// standard library depends on, in the paths of its vendored sources
+	rustDepsRegex = regexp.MustCompile(`/rust/deps/([A-Za-z0-9_\-]+)-([0-9]+\.[0-9]+\.[0-9]+[A-Za-z0-9.+\-]*)/`)
+	// rustRootRegex matches the crate at the root of a demangled path, the
+	// names of crates are lowercase unlike those of the type parameters
+	rustRootRegex = regexp.MustCompile(`(?:^|[<\s,&*(\[])([a-z][a-z0-9_]*)::`)
+)
+
+// RustCrate is a crate that a Rust binary was built from
+type RustCrate struct {
+	Name string
+	// Version is the version of the crate, or the revision when it was
+	// checked out from git, empty when it is only known from the symbols
+	Version string
+	// Source is where the crate was found, "registry" or "git" for the
+	// paths of its sources, "std" for those the standard library depends
+	// on and "symbols" for the names of its functions
+	Source string
+}
+
+// PanicLocation is where in the source a Rust binary may panic, from the
+// records of the file, line and column that each panic is passed
+type PanicLocation struct {
+	// Address is the virtual address of the record
+	Address uint64
+	File    string
+	Line    uint32
+	Column  uint32
+}
+
+// RustInfo is what a Rust binary records about the compiler and the crates
+// that built it, and about where in their sources it may panic
+type RustInfo struct {
+	// Version is the version of rustc from .comment, such as
+	// "1.75.0 (82e1608df 2023-12-21)", empty when it was stripped
+	Version string
+	// Commit is the commit of rustc from the paths of the standard library
+	Commit    string
+	Crates    []RustCrate
+	Locations []PanicLocation
+}
+
+// ReaderIsRust will check whether the binary was built by Rust, from the
+// version that rustc leaves in .comment, the symbols of its runtime or the
+// paths of its standard library
+func (r *ElfReader) ReaderIsRust() bool {
+	if bytes.Contains(r.ReaderParseSection(".comment"), []byte("rustc version")) {
+		return true
+	}
+
+	for _, load := range []func() ([]elf.Symbol, error){r.ExecReader.Symbols, r.ExecReader.DynamicSymbols} {
+		syms, err := load()
+		if err != nil {
+			continue
+		}
+
+		for _, sym := range syms {
+			if sym.Name == "rust_begin_unwind" || sym.Name == "rust_panic" || strings.HasPrefix(sym.Name, "__rust_") {
+				return true
+			}
+		}
+	}
+
+	return bytes.Contains(r.ReaderParseSection(".rodata"), []byte("/rustc/"))
+}
+
+// ReaderRustInfo will read the version and commit of rustc from a Rust
+// binary, the crates it was built from with their versions and the panic
+// locations within their sources
+func (r *ElfReader) ReaderRustInfo() (*RustInfo, error) {
+	if !r.ReaderIsRust() {
+		return nil, errors.New("not a Rust binary")
+	}
+
+	info := &RustInfo{Locations: r.readerPanicLocations()}
+
+	if m := rustVersionRegex.FindSubmatch(r.ReaderParseSection(".comment")); m != nil {
+		info.Version = string(m[1])
+	}
+
+	crates := make(map[string]RustCrate)
+	// the hyphens of the names of packages are underscores in those of
+	// their crates, which is how the symbols name them
+	add := func(crate RustCrate) {
+		crate.Name = strings.Replace(crate.Name, "-", "_", -1)
+		if prev, ok := crates[crate.Name]; !ok || prev.Version == "" {
+			crates[crate.Name] = crate
+		}
+	}
+
+	// the paths are in the panic locations and the debug information, as
+	// well as in whatever else the sources pass file!() to
+	for _, s := range r.ExecReader.Sections {
+		if s.Type == elf.SHT_NOBITS || s.Flags&elf.SHF_EXECINSTR != 0 {
+			continue
+		}
+
+		buf, err := s.Data()
+		if err != nil {
+			continue
+		}
+
+		if m := rustCommitRegex.FindSubmatch(buf); m != nil && info.Commit == "" {
+			info.Commit = string(m[1])
+		}
+
+		for _, m := range rustRegistryRegex.FindAllSubmatch(buf, -1) {
+			add(RustCrate{Name: string(m[1]), Version: string(m[2]), Source: "registry"})
+		}
+
+		for _, m := range rustGitRegex.FindAllSubmatch(buf, -1) {
+			add(RustCrate{Name: string(m[1]), Version: string(m[2]), Source: "git"})
+		}
+
+		for _, m := range rustDepsRegex.FindAllSubmatch(buf, -1) {
+			add(RustCrate{Name: string(m[1]), Version: string(m[2]), Source: "std"})
+		}
+	}
+
+	for _, load := range []func() ([]elf.Symbol, error){r.ExecReader.Symbols, r.ExecReader.DynamicSymbols} {
+		syms, err := load()
+		if err != nil {
+			continue
+		}
+
+		for _, sym := range syms {
+			demangled, err := UtilDemangleRust(sym.Name)
+			if err != nil {
+				continue
+			}
+
+			for _, m := range rustRootRegex.FindAllStringSubmatch(demangled, -1) {
+				add(RustCrate{Name: m[1], Source: "symbols"})
+			}
+		}
+	}
+
+	for _, crate := range crates {
+		info.Crates = append(info.Crates, crate)
+	}
+
+	sort.Slice(info.Crates, func(i, j int) bool {
+		return info.Crates[i].Name < info.Crates[j].Name
+	})
+
+	return info, nil
+}
+
+// readerPanicLocations will find the panic locations in the data sections,
+// the string header of the path of a Rust source file followed by the line
+// and column as 32 bit words. They are ordered by file, line and column
+func (r *ElfReader) readerPanicLocations() []PanicLocation {
+	var locations []PanicLocation
+
+	if r.ExecReader.Type == elf.ET_REL {
+		return nil
+	}
+
+	word := uint64(4)
+	if r.ExecReader.Class == elf.ELFCLASS64 {
+		word = 8
+	}
+
+	mem := r.readerMemory()
+	seen := make(map[PanicLocation]bool)
+
+	for _, s := range r.ExecReader.Sections {
+		if s.Type != elf.SHT_PROGBITS || s.Flags&elf.SHF_ALLOC == 0 || s.Flags&elf.SHF_EXECINSTR != 0 || s.Addr == 0 {
+			continue
+		}
+
+		buf, err := r.ReaderReadAt(s.Offset, s.Size)
+		if err != nil {
+			continue
+		}
+
+		load := func(off uint64) uint64 {
+			if word == 8 {
+				return mem.order.Uint64(buf[off:])
+			}
+
+			return uint64(mem.order.Uint32(buf[off:]))
+		}
+
+		for off := uint64(0); off+2*word+8 <= uint64(len(buf)); off += word {
+			addr := s.Addr + off
+
+			ptr, ok := mem.relocs[addr]
+			if !ok {
+				ptr = load(off)
+			}
+
+			size := load(off + word)
+			line := mem.order.Uint32(buf[off+2*word:])
+			column := mem.order.Uint32(buf[off+2*word+4:])
+
+			if ptr == 0 || size < 4 || size > rustMaxFile || line == 0 || line > rustMaxLine || column == 0 || column > rustMaxColumn {
+				continue
+			}
+
+			file, ok := r.readerLoadString(mem, ptr, size)
+			if !ok || !strings.HasSuffix(file, ".rs") {
+				continue
+			}
+
+			loc := PanicLocation{Address: addr, File: file, Line: line, Column: column}
+			key := loc
+			key.Address = 0
+
+			if !seen[key] {
+				seen[key] = true
+				locations = append(locations, loc)
+			}
+		}
+	}
+
+	sort.Slice(locations, func(i, j int) bool {
+		a, b := locations[i], locations[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+
+		return a.Column < b.Column
+	})
+
+	return locations
+}
+
+// readerLoadString will read the printable text of the given size at the
+// address, false is returned when it is not loaded or not printable
+func (r *ElfReader) readerLoadString(mem *memory, addr uint64, size uint64) (string, bool) {
+	for _, s := range r.ExecReader.Sections {
+		if s.Type == elf.SHT_NOBITS || s.Flags&elf.SHF_ALLOC == 0 {
+			continue
+		}
+
+		if addr < s.Addr || addr+size > s.Addr+s.Size {
+			continue
+		}
+
+		buf, ok := mem.data[s]
+		if !ok {
+			buf, _ = r.ReaderReadAt(s.Offset, s.Size)
+			mem.data[s] = buf
+		}
+
+		off := addr - s.Addr
+		if off+size > uint64(len(buf)) || !utilIsPrefixedText(buf[off:off+size]) {
+			return "", false
+		}
+
+		return string(buf[off : off+size]), true
+	}
+
+	return "", false
+}
@@ -0,0 +1,1056 @@
+package elfstrings
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// rustMaxDepth is how deep the paths and types of a v0 symbol may nest,
+// as the back references could otherwise refer to themselves forever
+const rustMaxDepth = 256
+
+// rustBasicTypes are the types of a v0 symbol named by a single letter
+var rustBasicTypes = map[byte]string{
+	'a': "i8", 'b': "bool", 'c': "char", 'd': "f64", 'e': "str", 'f': "f32",
+	'h': "u8", 'i': "isize", 'j': "usize", 'l': "i32", 'm': "u32", 'n': "i128",
+	'o': "u128", 's': "i16", 't': "u16", 'u': "()", 'v': "...", 'x': "i64",
+	'y': "u64", 'z': "!", 'p': "_",
+}
+
+// rustLegacyEscapes are the escapes of the characters that legacy symbols
+// cannot hold
+var rustLegacyEscapes = map[string]string{
+	"SP": "@", "BP": "*", "RF": "&", "LT": "<", "GT": ">", "LP": "(", "RP": ")", "C": ",",
+}
+
+// errRustSymbol is returned for the symbols which are not mangled by Rust
+var errRustSymbol = errors.New("not a Rust symbol")
+
+// UtilDemangleRust will demangle a Rust symbol, in either the legacy
+// mangling, which is the Itanium mangling ending with a hash, or in the
+// v0 mangling which starts with _R. The hash and the disambiguators of
+// the crates are left out, as they are in the paths of the source
+func UtilDemangleRust(symbol string) (string, error) {
+	for _, prefix := range []string{"_R", "R", "__R"} {
+		if !strings.HasPrefix(symbol, prefix) || len(symbol) == len(prefix) {
+			continue
+		}
+
+		// anything after a dot was added by LLVM rather than by rustc
+		sym := symbol[len(prefix):]
+		if i := strings.IndexByte(sym, '.'); i >= 0 {
+			sym = sym[:i]
+		}
+
+		if sym != "" && sym[0] >= 'A' && sym[0] <= 'Z' {
+			return utilDemangleRustV0(sym)
+		}
+	}
+
+	for _, prefix := range []string{"_ZN", "ZN", "__ZN"} {
+		if strings.HasPrefix(symbol, prefix) {
+			return utilDemangleRustLegacy(symbol[len(prefix):])
+		}
+	}
+
+	return "", errRustSymbol
+}
+
+// utilDemangleRustLegacy will demangle the names of a legacy symbol after
+// its _ZN, which must end in the hash of the symbol
+func utilDemangleRustLegacy(sym string) (string, error) {
+	var names []string
+
+	for len(sym) != 0 && sym[0] != 'E' {
+		n := 0
+		for n < len(sym) && sym[n] >= '0' && sym[n] <= '9' {
+			n++
+		}
+
+		size, err := strconv.Atoi(sym[:n])
+		if n == 0 || err != nil || size == 0 || n+size > len(sym) {
+			return "", errRustSymbol
+		}
+
+		names = append(names, sym[n:n+size])
+		sym = sym[n+size:]
+	}
+
+	if len(sym) == 0 || len(names) < 2 || !utilIsRustHash(names[len(names)-1]) {
+		return "", errRustSymbol
+	}
+
+	// whatever follows the E was added by LLVM, such as .llvm.1234
+	if rest := sym[1:]; rest != "" && rest[0] != '.' {
+		return "", errRustSymbol
+	}
+
+	names = names[:len(names)-1]
+	for i, name := range names {
+		unescaped, err := utilRustUnescape(name)
+		if err != nil {
+			return "", err
+		}
+
+		names[i] = unescaped
+	}
+
+	return strings.Join(names, "::"), nil
+}
+
+// utilIsRustHash will check whether the name is the hash which ends a
+// legacy symbol, an h followed by 16 hexadecimal digits
+func utilIsRustHash(name string) bool {
+	if len(name) != 17 || name[0] != 'h' {
+		return false
+	}
+
+	for _, c := range name[1:] {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// utilRustUnescape will undo the escapes of a name of a legacy symbol,
+// $LT$ and the others, $u7e$ for any other character and .. for ::
+func utilRustUnescape(name string) (string, error) {
+	var sb strings.Builder
+
+	if strings.HasPrefix(name, "_$") {
+		name = name[1:]
+	}
+
+	for i := 0; i < len(name); {
+		switch {
+		case name[i] == '$':
+			end := strings.IndexByte(name[i+1:], '$')
+			if end < 0 {
+				return "", errRustSymbol
+			}
+
+			esc := name[i+1 : i+1+end]
+			if text, ok := rustLegacyEscapes[esc]; ok {
+				sb.WriteString(text)
+			} else if len(esc) > 1 && esc[0] == 'u' {
+				code, err := strconv.ParseUint(esc[1:], 16, 32)
+				if err != nil || !utf8.ValidRune(rune(code)) {
+					return "", errRustSymbol
+				}
+
+				sb.WriteRune(rune(code))
+			} else {
+				return "", errRustSymbol
+			}
+
+			i += end + 2
+		case strings.HasPrefix(name[i:], ".."):
+			sb.WriteString("::")
+			i += 2
+		default:
+			sb.WriteByte(name[i])
+			i++
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// rustV0 is the state of demangling a v0 symbol, which is printed as it is
+// parsed. Printing is turned off to skip over the parts that are not shown
+type rustV0 struct {
+	sym   string
+	pos   int
+	out   strings.Builder
+	quiet int
+	depth int
+	// bound is how many lifetimes are bound by the binders around the
+	// part being printed
+	bound uint64
+}
+
+// errRustV0 is returned for the v0 symbols which are malformed
+var errRustV0 = errors.New("malformed Rust v0 symbol")
+
+// utilDemangleRustV0 will demangle a v0 symbol after its _R, leaving out
+// the crate that it was instantiated in
+func utilDemangleRustV0(sym string) (string, error) {
+	// the version of the encoding, which is only ever left out
+	if sym[0] >= '0' && sym[0] <= '9' {
+		return "", errRustSymbol
+	}
+
+	p := &rustV0{sym: sym}
+	if err := p.path(true); err != nil {
+		return "", err
+	}
+
+	// the crate it was instantiated in is a path of its own, and nothing
+	// may follow it
+	if p.pos < len(p.sym) {
+		p.quiet++
+		err := p.path(false)
+		p.quiet--
+
+		if err != nil || p.pos != len(p.sym) {
+			return "", errRustV0
+		}
+	}
+
+	return p.out.String(), nil
+}
+
+// print will write the text unless printing is turned off
+func (p *rustV0) print(text string) {
+	if p.quiet == 0 {
+		p.out.WriteString(text)
+	}
+}
+
+// peek will return the next character, zero at the end
+func (p *rustV0) peek() byte {
+	if p.pos < len(p.sym) {
+		return p.sym[p.pos]
+	}
+
+	return 0
+}
+
+// next will return the next character and move past it
+func (p *rustV0) next() (byte, error) {
+	if p.pos >= len(p.sym) {
+		return 0, errRustV0
+	}
+
+	p.pos++
+
+	return p.sym[p.pos-1], nil
+}
+
+// eat will move past the next character when it is c
+func (p *rustV0) eat(c byte) bool {
+	if p.peek() == c {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+// enter will go one level deeper, failing when the symbol nests too deep
+func (p *rustV0) enter() error {
+	p.depth++
+	if p.depth > rustMaxDepth {
+		return errRustV0
+	}
+
+	return nil
+}
+
+// base62 will parse a base 62 number ending in _, which is one more than
+// the digits as an empty number is zero
+func (p *rustV0) base62() (uint64, error) {
+	if p.eat('_') {
+		return 0, nil
+	}
+
+	var x uint64
+	for {
+		c, err := p.next()
+		if err != nil {
+			return 0, err
+		}
+
+		var d uint64
+		switch {
+		case c == '_':
+			return x + 1, nil
+		case c >= '0' && c <= '9':
+			d = uint64(c - '0')
+		case c >= 'a' && c <= 'z':
+			d = uint64(c-'a') + 10
+		case c >= 'A' && c <= 'Z':
+			d = uint64(c-'A') + 36
+		default:
+			return 0, errRustV0
+		}
+
+		if x > (^uint64(0)-d)/62 {
+			return 0, errRustV0
+		}
+
+		x = x*62 + d
+	}
+}
+
+// optBase62 will parse a base 62 number after the tag, which is one more
+// than the number, zero when there is no tag
+func (p *rustV0) optBase62(tag byte) (uint64, error) {
+	if !p.eat(tag) {
+		return 0, nil
+	}
+
+	x, err := p.base62()
+
+	return x + 1, err
+}
+
+// ident will parse an identifier, its length and its bytes, which are
+// Punycode when it starts with a u
+func (p *rustV0) ident() (string, error) {
+	puny := p.eat('u')
+
+	// a length of zero is a single 0, any other has no leading zeros
+	start := p.pos
+	if p.eat('0') {
+		return "", nil
+	}
+
+	for p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+
+	if p.pos == start {
+		return "", errRustV0
+	}
+
+	size, err := strconv.Atoi(p.sym[start:p.pos])
+	if err != nil {
+		return "", errRustV0
+	}
+
+	p.eat('_')
+	if p.pos+size > len(p.sym) {
+		return "", errRustV0
+	}
+
+	name := p.sym[p.pos : p.pos+size]
+	p.pos += size
+
+	if puny {
+		return utilPunycode(name)
+	}
+
+	return name, nil
+}
+
+// backref will run f with the symbol parsed from where the back reference
+// points to, before the back reference itself
+func (p *rustV0) backref(f func() error) error {
+	at := p.pos - 1
+
+	target, err := p.base62()
+	if err != nil {
+		return err
+	}
+
+	if target >= uint64(at) {
+		return errRustV0
+	}
+
+	saved := p.pos
+	p.pos = int(target)
+	err = f()
+	p.pos = saved
+
+	return err
+}
+
+// path will print a path, with the generic arguments of the values after ::
+func (p *rustV0) path(inValue bool) error {
+	if err := p.enter(); err != nil {
+		return err
+	}
+
+	defer func() { p.depth-- }()
+
+	tag, err := p.next()
+	if err != nil {
+		return err
+	}
+
+	switch tag {
+	case 'C':
+		if _, err := p.optBase62('s'); err != nil {
+			return err
+		}
+
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+
+		p.print(name)
+	case 'N':
+		ns, err := p.next()
+		if err != nil || !(ns >= 'A' && ns <= 'Z' || ns >= 'a' && ns <= 'z') {
+			return errRustV0
+		}
+
+		if err := p.path(inValue); err != nil {
+			return err
+		}
+
+		dis, err := p.optBase62('s')
+		if err != nil {
+			return err
+		}
+
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case ns >= 'A' && ns <= 'Z':
+			kind := string(ns)
+			if ns == 'C' {
+				kind = "closure"
+			} else if ns == 'S' {
+				kind = "shim"
+			}
+
+			if name != "" {
+				kind += ":" + name
+			}
+
+			p.print(fmt.Sprintf("::{%s#%d}", kind, dis))
+		case name != "":
+			p.print("::" + name)
+		}
+	case 'M', 'X', 'Y':
+		if tag != 'Y' {
+			// the path of the impl is not shown, only its type
+			if _, err := p.optBase62('s'); err != nil {
+				return err
+			}
+
+			p.quiet++
+			err := p.path(false)
+			p.quiet--
+
+			if err != nil {
+				return err
+			}
+		}
+
+		p.print("<")
+		if err := p.typ(); err != nil {
+			return err
+		}
+
+		if tag != 'M' {
+			p.print(" as ")
+			if err := p.path(false); err != nil {
+				return err
+			}
+		}
+
+		p.print(">")
+	case 'I':
+		if err := p.path(inValue); err != nil {
+			return err
+		}
+
+		if inValue {
+			p.print("::")
+		}
+
+		p.print("<")
+		if err := p.list(", ", p.genericArg); err != nil {
+			return err
+		}
+
+		p.print(">")
+	case 'B':
+		return p.backref(func() error {
+			return p.path(inValue)
+		})
+	default:
+		return errRustV0
+	}
+
+	return nil
+}
+
+// list will run f for each element until an E, printing sep between them,
+// and return how many elements there were
+func (p *rustV0) list(sep string, f func() error) error {
+	_, err := p.count(sep, f)
+	return err
+}
+
+// count will run f for each element until an E, printing sep between
+// them, and return how many elements there were
+func (p *rustV0) count(sep string, f func() error) (int, error) {
+	n := 0
+	for !p.eat('E') {
+		if p.pos >= len(p.sym) {
+			return n, errRustV0
+		}
+
+		if n > 0 {
+			p.print(sep)
+		}
+
+		if err := f(); err != nil {
+			return n, err
+		}
+
+		n++
+	}
+
+	return n, nil
+}
+
+// genericArg will print a lifetime, a type or a constant
+func (p *rustV0) genericArg() error {
+	if p.eat('L') {
+		lt, err := p.base62()
+		if err != nil {
+			return err
+		}
+
+		return p.lifetime(lt)
+	}
+
+	if p.eat('K') {
+		return p.constant()
+	}
+
+	return p.typ()
+}
+
+// lifetime will print the lifetime bound at the de Bruijn index, counted
+// from the innermost binder
+func (p *rustV0) lifetime(lt uint64) error {
+	if lt == 0 {
+		p.print("'_")
+		return nil
+	}
+
+	if lt > p.bound {
+		return errRustV0
+	}
+
+	if depth := p.bound - lt; depth < 26 {
+		p.print("'" + string(rune('a'+depth)))
+	} else {
+		p.print(fmt.Sprintf("'_%d", depth))
+	}
+
+	return nil
+}
+
+// binder will print the lifetimes bound by a binder, if there is one,
+// around what f prints
+func (p *rustV0) binder(f func() error) error {
+	bound, err := p.optBase62('G')
+	if err != nil {
+		return err
+	}
+
+	if bound != 0 {
+		p.print("for<")
+		for i := uint64(0); i < bound; i++ {
+			if i > 0 {
+				p.print(", ")
+			}
+
+			p.bound++
+			if err := p.lifetime(1); err != nil {
+				return err
+			}
+		}
+
+		p.print("> ")
+	}
+
+	err = f()
+	p.bound -= bound
+
+	return err
+}
+
+// typ will print a type
+func (p *rustV0) typ() error {
+	if err := p.enter(); err != nil {
+		return err
+	}
+
+	defer func() { p.depth-- }()
+
+	tag, err := p.next()
+	if err != nil {
+		return err
+	}
+
+	if name, ok := rustBasicTypes[tag]; ok {
+		p.print(name)
+		return nil
+	}
+
+	switch tag {
+	case 'R', 'Q':
+		p.print("&")
+		if p.eat('L') {
+			lt, err := p.base62()
+			if err != nil {
+				return err
+			}
+
+			if lt != 0 {
+				if err := p.lifetime(lt); err != nil {
+					return err
+				}
+
+				p.print(" ")
+			}
+		}
+
+		if tag == 'Q' {
+			p.print("mut ")
+		}
+
+		return p.typ()
+	case 'P':
+		p.print("*const ")
+		return p.typ()
+	case 'O':
+		p.print("*mut ")
+		return p.typ()
+	case 'A', 'S':
+		p.print("[")
+		if err := p.typ(); err != nil {
+			return err
+		}
+
+		if tag == 'A' {
+			p.print("; ")
+			if err := p.constant(); err != nil {
+				return err
+			}
+		}
+
+		p.print("]")
+	case 'T':
+		p.print("(")
+		n, err := p.count(", ", p.typ)
+		if err != nil {
+			return err
+		}
+
+		if n == 1 {
+			p.print(",")
+		}
+
+		p.print(")")
+	case 'F':
+		return p.binder(p.fnSig)
+	case 'D':
+		p.print("dyn ")
+		if err := p.binder(func() error {
+			return p.list(" + ", p.dynTrait)
+		}); err != nil {
+			return err
+		}
+
+		if !p.eat('L') {
+			return errRustV0
+		}
+
+		lt, err := p.base62()
+		if err != nil {
+			return err
+		}
+
+		if lt != 0 {
+			p.print(" + ")
+			return p.lifetime(lt)
+		}
+	case 'B':
+		return p.backref(p.typ)
+	default:
+		p.pos--
+		return p.path(false)
+	}
+
+	return nil
+}
+
+// fnSig will print the signature of a function pointer
+func (p *rustV0) fnSig() error {
+	unsafe := p.eat('U')
+
+	var abi string
+	if p.eat('K') {
+		if p.eat('C') {
+			abi = "C"
+		} else {
+			name, err := p.ident()
+			if err != nil {
+				return err
+			}
+
+			abi = strings.Replace(name, "_", "-", -1)
+		}
+	}
+
+	if unsafe {
+		p.print("unsafe ")
+	}
+
+	if abi != "" {
+		p.print("extern \"" + abi + "\" ")
+	}
+
+	p.print("fn(")
+	if err := p.list(", ", p.typ); err != nil {
+		return err
+	}
+
+	p.print(")")
+
+	// the unit return type is left out
+	if p.eat('u') {
+		return nil
+	}
+
+	p.print(" -> ")
+
+	return p.typ()
+}
+
+// dynTrait will print a trait of a trait object, along with the bindings
+// of its associated types
+func (p *rustV0) dynTrait() error {
+	open, err := p.openPath()
+	if err != nil {
+		return err
+	}
+
+	for p.eat('p') {
+		if open {
+			p.print(", ")
+		} else {
+			p.print("<")
+			open = true
+		}
+
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+
+		p.print(name + " = ")
+		if err := p.typ(); err != nil {
+			return err
+		}
+	}
+
+	if open {
+		p.print(">")
+	}
+
+	return nil
+}
+
+// openPath will print a path whose generic arguments are left open, so
+// that the bindings of associated types may follow them
+func (p *rustV0) openPath() (bool, error) {
+	if p.eat('B') {
+		var open bool
+		err := p.backref(func() error {
+			var err error
+			open, err = p.openPath()
+			return err
+		})
+
+		return open, err
+	}
+
+	if p.eat('I') {
+		if err := p.path(false); err != nil {
+			return false, err
+		}
+
+		p.print("<")
+
+		return true, p.list(", ", p.genericArg)
+	}
+
+	return false, p.path(false)
+}
+
+// constant will print a constant, the value of a const generic
+func (p *rustV0) constant() error {
+	if err := p.enter(); err != nil {
+		return err
+	}
+
+	defer func() { p.depth-- }()
+
+	tag, err := p.next()
+	if err != nil {
+		return err
+	}
+
+	switch tag {
+	case 'a', 's', 'l', 'x', 'n', 'i', 'h', 't', 'm', 'y', 'o', 'j':
+		neg := p.eat('n')
+		digits, err := p.hex()
+		if err != nil {
+			return err
+		}
+
+		v, ok := new(big.Int).SetString("0"+digits, 16)
+		if !ok {
+			return errRustV0
+		}
+
+		if neg {
+			p.print("-")
+		}
+
+		p.print(v.String())
+	case 'b':
+		digits, err := p.hex()
+		if err != nil || digits != "0" && digits != "1" {
+			return errRustV0
+		}
+
+		p.print(map[string]string{"0": "false", "1": "true"}[digits])
+	case 'c':
+		digits, err := p.hex()
+		if err != nil {
+			return err
+		}
+
+		code, err := strconv.ParseUint(digits, 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return errRustV0
+		}
+
+		p.print(strconv.QuoteRune(rune(code)))
+	case 'e':
+		digits, err := p.hex()
+		if err != nil || len(digits)%2 != 0 {
+			return errRustV0
+		}
+
+		var raw []byte
+		for i := 0; i < len(digits); i += 2 {
+			b, _ := strconv.ParseUint(digits[i:i+2], 16, 8)
+			raw = append(raw, byte(b))
+		}
+
+		p.print(strconv.Quote(string(raw)))
+	case 'R', 'Q':
+		// a reference to a string is shown as the literal
+		if tag == 'R' && p.peek() == 'e' {
+			return p.constant()
+		}
+
+		p.print("&")
+		if tag == 'Q' {
+			p.print("mut ")
+		}
+
+		return p.constant()
+	case 'A':
+		p.print("[")
+		if err := p.list(", ", p.constant); err != nil {
+			return err
+		}
+
+		p.print("]")
+	case 'T':
+		p.print("(")
+		n, err := p.count(", ", p.constant)
+		if err != nil {
+			return err
+		}
+
+		if n == 1 {
+			p.print(",")
+		}
+
+		p.print(")")
+	case 'V':
+		if err := p.path(true); err != nil {
+			return err
+		}
+
+		return p.fields()
+	case 'p':
+		p.print("_")
+	case 'B':
+		return p.backref(p.constant)
+	default:
+		return errRustV0
+	}
+
+	return nil
+}
+
+// fields will print the fields of a constant of a struct or an enum
+func (p *rustV0) fields() error {
+	tag, err := p.next()
+	if err != nil {
+		return err
+	}
+
+	switch tag {
+	case 'U':
+	case 'T':
+		p.print("(")
+		if err := p.list(", ", p.constant); err != nil {
+			return err
+		}
+
+		p.print(")")
+	case 'S':
+		p.print(" { ")
+		if err := p.list(", ", func() error {
+			if _, err := p.optBase62('s'); err != nil {
+				return err
+			}
+
+			name, err := p.ident()
+			if err != nil {
+				return err
+			}
+
+			p.print(name + ": ")
+
+			return p.constant()
+		}); err != nil {
+			return err
+		}
+
+		p.print(" }")
+	default:
+		return errRustV0
+	}
+
+	return nil
+}
+
+// hex will parse the hexadecimal digits of a constant up to its _
+func (p *rustV0) hex() (string, error) {
+	end := strings.IndexByte(p.sym[p.pos:], '_')
+	if end < 0 {
+		return "", errRustV0
+	}
+
+	digits := p.sym[p.pos : p.pos+end]
+	for _, c := range digits {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return "", errRustV0
+		}
+	}
+
+	p.pos += end + 1
+	if digits == "" {
+		return "0", nil
+	}
+
+	return digits, nil
+}
+
+// utilPunycode will decode a Punycode identifier of a v0 symbol, where the
+// ASCII characters are separated from the encoded ones by the last _
+func utilPunycode(name string) (string, error) {
+	const (
+		base        = 36
+		tMin        = 1
+		tMax        = 26
+		skew        = 38
+		damp        = 700
+		initialBias = 72
+		initialN    = 128
+	)
+
+	var out []rune
+
+	if i := strings.LastIndexByte(name, '_'); i >= 0 {
+		out = []rune(name[:i])
+		name = name[i+1:]
+	}
+
+	n, bias, i := rune(initialN), initialBias, 0
+
+	for pos := 0; pos < len(name); {
+		oldi, w := i, 1
+		for k := base; ; k += base {
+			if pos >= len(name) {
+				return "", errRustV0
+			}
+
+			c := name[pos]
+			pos++
+
+			var digit int
+			switch {
+			case c >= 'a' && c <= 'z':
+				digit = int(c - 'a')
+			case c >= '0' && c <= '9':
+				digit = int(c-'0') + 26
+			default:
+				return "", errRustV0
+			}
+
+			i += digit * w
+			t := k - bias
+			if t < tMin {
+				t = tMin
+			} else if t > tMax {
+				t = tMax
+			}
+
+			if digit < t {
+				break
+			}
+
+			w *= base - t
+			if i > utf8.MaxRune || w > utf8.MaxRune {
+				return "", errRustV0
+			}
+		}
+
+		// adapt the bias to the amount of characters decoded so far
+		delta := i - oldi
+		if oldi == 0 {
+			delta /= damp
+		} else {
+			delta /= 2
+		}
+
+		delta += delta / (len(out) + 1)
+
+		k := 0
+		for delta > ((base-tMin)*tMax)/2 {
+			delta /= base - tMin
+			k += base
+		}
+
+		bias = k + (base-tMin+1)*delta/(delta+skew)
+
+		n += rune(i / (len(out) + 1))
+		i %= len(out) + 1
+		if !utf8.ValidRune(n) {
+			return "", errRustV0
+		}
+
+		out = append(out[:i], append([]rune{n}, out[i:]...)...)
+		i++
+	}
+
+	return string(out), nil
+}
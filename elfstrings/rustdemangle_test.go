@@ -0,0 +1,87 @@
+package elfstrings
+
+import "testing"
+
+// The expected names are those that c++filt gives, without the hashes of
+// the symbols and the disambiguators of the crates, and without the types
+// of the const generic arguments, which the demangler leaves out
+func TestDemangleRust(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   string
+	}{
+		// legacy
+		{"_ZN3std12backtrace_rs5print17BacktraceFrameFmt14print_fileline17hff1c812c6dc65028E",
+			"std::backtrace_rs::print::BacktraceFrameFmt::print_fileline"},
+		{"_ZN102_$LT$std..panicking..begin_panic_handler..FormatStringPayload$u20$as$u20$core..panic..PanicPayload$GT$3get17ha39363536bd5c768E",
+			"<std::panicking::begin_panic_handler::FormatStringPayload as core::panic::PanicPayload>::get"},
+		{"_ZN132_$LT$alloc..vec..Vec$LT$T$C$A$GT$$u20$as$u20$alloc..vec..spec_extend..SpecExtend$LT$$RF$T$C$core..slice..iter..Iter$LT$T$GT$$GT$$GT$11spec_extend17hff234546cece389aE",
+			"<alloc::vec::Vec<T,A> as alloc::vec::spec_extend::SpecExtend<&T,core::slice::iter::Iter<T>>>::spec_extend"},
+		{"_ZN3foo3bar17h0123456789abcdefE.llvm.1234", "foo::bar"},
+
+		// v0
+		{"_RNvCs8Gv9BFMk9cN_1m4main", "m::main"},
+		{"_RNvCs582dLtAx6OK_1uu7_1lqs71d", "u::東京"},
+		{"_RINvCs582dLtAx6OK_1u3genKj7_yEB2_", "u::gen::<7, u64>"},
+		{"_RNCINvNtCscKkwsb9kWaL_3std2rt10lang_startuE0Cs8Gv9BFMk9cN_1m",
+			"std::rt::lang_start::<()>::{closure#0}"},
+		{"_RNSNvYNCINvNtCscKkwsb9kWaL_3std2rt10lang_startuE0INtNtNtCs5GmCzIpY9Qj_4core3ops8function6FnOnceuE9call_once6vtableCs8Gv9BFMk9cN_1m",
+			"<std::rt::lang_start<()>::{closure#0} as core::ops::function::FnOnce<()>>::call_once::{shim:vtable#0}"},
+		{"_RINvYNtNtNtCscKkwsb9kWaL_3std4hash6random11RandomStateNtNtCs5GmCzIpY9Qj_4core4hash11BuildHasher8hash_oneRNtNtCscmSb185pVu_5alloc6string6StringECs8Gv9BFMk9cN_1m",
+			"<std::hash::random::RandomState as core::hash::BuildHasher>::hash_one::<&alloc::string::String>"},
+		{"_RINvNtCs5GmCzIpY9Qj_4core3ptr13drop_in_placeINtNtB4_6result6ResulthINtNtCscmSb185pVu_5alloc5boxed3BoxDNtNtB4_3any3AnyNtNtB4_6marker4SendEL_EEECs8Gv9BFMk9cN_1m",
+			"core::ptr::drop_in_place::<core::result::Result<u8, alloc::boxed::Box<dyn core::any::Any + core::marker::Send>>>"},
+		{"_RINvNtNtCscKkwsb9kWaL_3std3sys9backtrace28___rust_begin_short_backtraceFEuuECs8Gv9BFMk9cN_1m",
+			"std::sys::backtrace::__rust_begin_short_backtrace::<fn(), ()>"},
+		{"_RINvMs6_NtCsgyaJDGyq3nG_9hashbrown3rawINtB6_8RawTableTNtNtCscmSb185pVu_5alloc6string6StringINtNtBU_3vec3VecmEEE14reserve_rehashNCINvNtB8_3map11make_hasherBQ_B1r_NtNtNtCscKkwsb9kWaL_3std4hash6random11RandomStateE0ECs8Gv9BFMk9cN_1m.llvm.14271408898870712269",
+			"<hashbrown::raw::RawTable<(alloc::string::String, alloc::vec::Vec<u32>)>>::reserve_rehash::<hashbrown::map::make_hasher<alloc::string::String, alloc::vec::Vec<u32>, std::hash::random::RandomState>::{closure#0}>"},
+		{"_RNvNvMCs4fqI2P2rA04_13const_genericINtB4_3FooKpE3foo3FOO", "<const_generic::Foo<_>>::foo::FOO"},
+		{"_RINvCs4fqI2P2rA04_13const_generic3fooKb1_E", "const_generic::foo::<true>"},
+		{"_RINvCs4fqI2P2rA04_13const_generic3fooKc61_E", "const_generic::foo::<'a'>"},
+	}
+
+	for _, tt := range tests {
+		got, err := UtilDemangleRust(tt.symbol)
+		if err != nil {
+			t.Errorf("UtilDemangleRust(%q) failed: %v", tt.symbol, err)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("UtilDemangleRust(%q) = %q, want %q", tt.symbol, got, tt.want)
+		}
+	}
+}
+
+func TestDemangleRustMalformed(t *testing.T) {
+	for _, symbol := range []string{
+		"_ZN3foo3barE",
+		"_ZN3foo3bar17h0123456789abcdefEx",
+		"_RNvNtCs4fqI2P2rA04_4test4path10u30c8u6b32u7d44",
+		"_RNvCs4fqI2P2rA04_1a",
+		"_RB_",
+		"_R",
+	} {
+		if got, err := UtilDemangleRust(symbol); err == nil {
+			t.Errorf("UtilDemangleRust(%q) = %q, want an error", symbol, got)
+		}
+	}
+}
+
+func TestDetectRust(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   Language
+	}{
+		{"_ZN102_$LT$std..panicking..begin_panic_handler..FormatStringPayload$u20$as$u20$core..panic..PanicPayload$GT$3get17ha39363536bd5c768E", LanguageRust},
+		{"_ZN3foo3bar17h0123456789abcdefE.llvm.1234", LanguageRust},
+		{"_RNvCs8Gv9BFMk9cN_1m4main", LanguageRust},
+		{"_ZN3foo3barEv", LanguageCPP},
+	}
+
+	for _, tt := range tests {
+		if got := UtilDetectLanguage(tt.symbol, false); got != tt.want {
+			t.Errorf("UtilDetectLanguage(%q) = %s, want %s", tt.symbol, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ScanMinLength is the minimum length of a printable run when scanning
+// raw regions and no minimum has been given, the same as strings(1)
+const ScanMinLength = 4
+
+// ScanMode to emulate an enum of the ways the binary can be scanned
+type ScanMode int32
+
+// Modes of scanning that may be used
+const (
+	// ScanSections parses the strings of the selected sections
+	ScanSections ScanMode = iota
+	// ScanFile scans the whole file for printable runs
+	ScanFile
+	// ScanSegments scans every PT_LOAD segment for printable runs
+	ScanSegments
+	// ScanRange scans a file offset or address range for printable runs
+	ScanRange
+	scanEnd
+)
+
+// ScanRegion is a region of the file that is scanned for printable runs
+type ScanRegion struct {
+	Name   string
+	Offset uint64
+	Size   uint64
+}
+
+// ReaderScanRegions will build the regions of the file that are scanned
+// in the given mode, start and end are only used by ScanRange and are
+// virtual addresses rather than file offsets when virtual is set
+func (r *ElfReader) ReaderScanRegions(mode ScanMode, start uint64, end uint64, virtual bool) ([]ScanRegion, error) {
+	stat, err := r.File.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	size := uint64(stat.Size())
+
+	switch mode {
+	case ScanFile:
+		return []ScanRegion{{Name: "file", Offset: 0, Size: size}}, nil
+	case ScanSegments:
+		var regions []ScanRegion
+
+		for i, p := range r.ExecReader.Progs {
+			if p.Type != elf.PT_LOAD || p.Filesz == 0 {
+				continue
+			}
+
+			seg := Segment{Index: i, Prog: p}
+			regions = append(regions, ScanRegion{Name: seg.Name(), Offset: p.Off, Size: p.Filesz})
+		}
+
+		return regions, nil
+	case ScanRange:
+		if end <= start {
+			return nil, errors.New("the end of the range must be after the start")
+		}
+
+		if !virtual {
+			if start >= size {
+				return nil, errors.New("the range is outside of the file")
+			}
+
+			if end > size {
+				end = size
+			}
+
+			return []ScanRegion{{Name: "range", Offset: start, Size: end - start}}, nil
+		}
+
+		return r.readerAddressRegions(start, end)
+	}
+
+	return nil, errors.New("this scan mode does not scan regions")
+}
+
+// readerAddressRegions will convert a virtual address range into the
+// regions of the file which are loaded into that range
+func (r *ElfReader) readerAddressRegions(start uint64, end uint64) ([]ScanRegion, error) {
+	var regions []ScanRegion
+
+	for i, p := range r.ExecReader.Progs {
+		if p.Type != elf.PT_LOAD {
+			continue
+		}
+
+		lo, hi := start, end
+		if lo < p.Vaddr {
+			lo = p.Vaddr
+		}
+
+		if hi > p.Vaddr+p.Filesz {
+			hi = p.Vaddr + p.Filesz
+		}
+
+		if lo >= hi {
+			continue
+		}
+
+		seg := Segment{Index: i, Prog: p}
+		regions = append(regions, ScanRegion{
+			Name:   seg.Name(),
+			Offset: p.Off + (lo - p.Vaddr),
+			Size:   hi - lo,
+		})
+	}
+
+	if regions == nil {
+		return nil, fmt.Errorf("no segment maps the range %#x-%#x", start, end)
+	}
+
+	return regions, nil
+}
+
+// ReaderScan will scan the region for runs of printable characters,
+// much like strings(1) -a, each string is still attributed to the
+// section and segment that it resides in where there is one
+func (r *ElfReader) ReaderScan(region ScanRegion, opts *Options) []StringRecord {
+	var records []StringRecord
+
+	buf, err := r.ReaderReadAt(region.Offset, region.Size)
+	if err != nil {
+		return nil
+	}
+
+	for _, enc := range opts.encodings() {
+		if enc == EncodingASCII {
+			records = append(records, r.readerScanASCII(buf, region.Offset, opts)...)
+			continue
+		}
+
+		for _, w := range UtilWideStrings(buf, region.Offset, enc, opts.Unaligned) {
+			f := UtilFilterWide(&w, enc, opts)
+			if f == nil {
+				continue
+			}
+
+			records = append(records, r.readerRecordAt(region.Offset+w.Offset, w.Raw, f))
+		}
+	}
+
+	records = UtilLimitRecords(records, opts)
+	if opts.Xrefs {
+		r.readerAttachXrefs(records)
+	}
+
+	return records
+}
+
+// readerScanASCII will find the runs of printable ASCII and UTF-8
+// within the buffer, which was read from the given file offset
+func (r *ElfReader) readerScanASCII(buf []byte, base uint64, opts *Options) []StringRecord {
+	var records []StringRecord
+
+	minLength := opts.MinLength
+	if minLength == 0 {
+		minLength = ScanMinLength
+	}
+
+	records, covered := r.readerScanLegacy(buf, base, minLength, opts)
+
+	start := -1
+	for i := 0; i <= len(buf); i++ {
+		if i < len(buf) && UtilIsPrintable(buf[i]) {
+			if start == -1 {
+				start = i
+			}
+
+			continue
+		}
+
+		// printable characters outside of ASCII are kept
+		// as long as they are valid UTF-8
+		if i < len(buf) && buf[i] >= utf8.RuneSelf {
+			r, size := utf8.DecodeRune(buf[i:])
+			if r != utf8.RuneError && unicode.IsPrint(r) {
+				if start == -1 {
+					start = i
+				}
+
+				i += size - 1
+				continue
+			}
+		}
+
+		if start == -1 {
+			continue
+		}
+
+		raw := buf[start:i]
+		off := base + uint64(start)
+		start = -1
+
+		if uint64(utf8.RuneCount(raw)) < minLength || covered[off] {
+			continue
+		}
+
+		records = append(records, r.readerWithUnwrapped(raw, UtilFilterString(raw, opts), func(f *FilteredString) StringRecord {
+			return r.readerRecordAt(off, raw, f)
+		}, opts)...)
+	}
+
+	return records
+}
+
+// readerScanLegacy will find the runs of text between control characters
+// which are not UTF-8 but can be decoded with one of the legacy encodings,
+// returning them along with the offsets of the ASCII runs which they cover
+func (r *ElfReader) readerScanLegacy(buf []byte, base uint64, minLength uint64, opts *Options) ([]StringRecord, map[uint64]bool) {
+	var records []StringRecord
+
+	covered := make(map[uint64]bool)
+	if len(opts.Legacy) == 0 {
+		return nil, covered
+	}
+
+	start := 0
+	for i := 0; i <= len(buf); i++ {
+		if i < len(buf) && (buf[i] >= ' ' || buf[i] == '\t') {
+			continue
+		}
+
+		raw := buf[start:i]
+		off := base + uint64(start)
+		start = i + 1
+
+		if uint64(len(raw)) < minLength || utf8.Valid(raw) {
+			continue
+		}
+
+		f := UtilFilterString(raw, opts)
+		if f == nil || f.Encoding == EncodingBinary {
+			continue
+		}
+
+		records = append(records, r.readerRecordAt(off, raw, f))
+
+		// the parts of the run that are printable on their own would
+		// otherwise be found again by the ASCII scan
+		for j := range raw {
+			covered[off+uint64(j)] = true
+		}
+	}
+
+	return records, covered
+}
+
+// readerRecordAt will create the record for a string at the file offset,
+// attributing it to the section or segment that contains it
+func (r *ElfReader) readerRecordAt(fileOff uint64, raw []byte, f *FilteredString) StringRecord {
+	if s := r.ReaderSectionAt(fileOff); s != nil {
+		return r.readerRecord(s, fileOff-s.Offset, raw, f)
+	}
+
+	rec := StringRecord{
+		ID:         r.readerNextID(),
+		Section:    "[file]",
+		Offset:     fileOff,
+		FileOffset: fileOff,
+		Raw:        raw,
+		Text:       f.Text,
+		Encoding:   f.Encoding,
+		Score:      f.Score,
+		IOCs:       f.IOCs,
+		Tags:       f.Tags,
+		Language:   f.Language,
+		Mangled:    f.Mangled,
+	}
+
+	rec.Segment = r.ReaderSegmentAt(fileOff)
+	if rec.Segment != nil {
+		rec.Section = rec.Segment.Name()
+		rec.Offset = fileOff - rec.Segment.Prog.Off
+		rec.Address, _ = r.ReaderAddressOf(fileOff)
+	}
+
+	return rec
+}
+
+// UtilIsPrintable will check if the byte is printable ASCII,
+// tabs are included as they are by strings(1)
+func UtilIsPrintable(b byte) bool {
+	return (b >= ' ' && b <= '~') || b == '\t'
+}
+
+// ScanParseModeStr converts from a string to a scan mode
+// default is scanning the sections
+func ScanParseModeStr(mode string) (ScanMode, error) {
+	switch strings.ToLower(mode) {
+	case "", "sections":
+		return ScanSections, nil
+	case "file":
+		return ScanFile, nil
+	case "segments":
+		return ScanSegments, nil
+	case "range":
+		return ScanRange, nil
+	}
+
+	return scanEnd, errors.New("this scan mode does not exist")
+}
+
+// ScanParseRangeStr converts a range such as "0x1000-0x2000"
+// into its start and end
+func ScanParseRangeStr(rng string) (uint64, uint64, error) {
+	parts := strings.SplitN(rng, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, errors.New("the range must be in the form start-end")
+	}
+
+	start, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 0, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 0, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return start, end, nil
+}
@@ -0,0 +1,233 @@
+package elfstrings
+
+import (
+	"math"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+// constants describing the layout of the bigram model, these must
+// match the constants in makemodel.go
+const (
+	modelOther    = 95
+	modelBoundary = 96
+	modelSize     = 97
+	modelScale    = 16
+)
+
+// formatRegex matches printf and strftime style conversions
+var formatRegex = regexp.MustCompile(`%[-+ #0']*(\d+|\*)?(\.(\d+|\*))?(hh|h|ll|l|L|q|j|z|t)?[diouxXeEfFgGaAcspn]|%[aAbBcCdDeFgGhHIjklmMnprRsStTuUVwWxXyYzZ+]`)
+
+// junkRegexes match the printable runs which x86 instructions commonly
+// encode to, such as "AWAVAUATUSH" for the pushes of a prologue
+var junkRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`^(A[P-_])+[P-_]*([AEHILM]|E1|1|Lc|Hc)?$`),
+	regexp.MustCompile(`^[P-_]*(A[P-_])*[P-_]*[HILM]$`),
+	regexp.MustCompile(`^[A-Za-z\\]\$.{0,3}$`),
+}
+
+// weights of each of the measures in the readability score
+const (
+	scoreNGramWeight   = 0.6
+	scoreClassWeight   = 0.25
+	scoreEntropyWeight = 0.15
+)
+
+// Score is the readability score of a string, broken down
+// into each of the measures it is made up of
+type Score struct {
+	// NGram is how closely the bigrams match those of real text
+	NGram float64
+	// Classes is how plausible the mix of letters, digits,
+	// spaces and symbols is
+	Classes float64
+	// Entropy is how plausible the spread of characters is, neither
+	// the same character repeated nor every character different
+	Entropy float64
+	// Total is the weighted score between 0 and 1
+	Total float64
+}
+
+// modelClass converts a byte to its index in the model
+func modelClass(b byte) int {
+	if b >= ' ' && b <= '~' {
+		return int(b - ' ')
+	}
+
+	return modelOther
+}
+
+// UtilScore will score how readable the text is, between 0 for
+// random bytes and 1 for text which is clearly written by a human
+func UtilScore(text string) Score {
+	var score Score
+
+	score.NGram = utilScoreNGram(text)
+	score.Classes = utilScoreClasses(text)
+	score.Entropy = utilScoreEntropy(text)
+
+	score.Total = score.NGram*scoreNGramWeight +
+		score.Classes*scoreClassWeight +
+		score.Entropy*scoreEntropyWeight
+
+	// the fewer characters there are the less the measures can be
+	// trusted, so short text is held back a little
+	if n := utf8.RuneCountInString(text); n < 8 {
+		score.Total *= 0.6 + 0.05*float64(n)
+	}
+
+	// the register pushes and pops of function prologues and
+	// epilogues are the most common printable runs in code
+	for _, junk := range junkRegexes {
+		if junk.MatchString(text) {
+			score.Total *= 0.3
+			break
+		}
+	}
+
+	// format strings are made up of symbols that would otherwise
+	// look like noise, but the conversions in them are rarely random
+	if n := len(formatRegex.FindAllString(text, -1)); n != 0 {
+		score.Total = math.Max(score.Total, math.Min(0.5+0.1*float64(n), 0.8))
+	}
+
+	return score
+}
+
+// utilScoreNGram will score the average cost of the bigrams in the
+// text against the model, characters outside of ASCII have already
+// been checked to be of a single script so they are skipped over
+func utilScoreNGram(text string) float64 {
+	var bits float64
+	var count int
+
+	prev := modelBoundary
+	for _, r := range text {
+		if r >= utf8.RuneSelf {
+			prev = modelBoundary
+			continue
+		}
+
+		cur := modelClass(byte(r))
+		bits += float64(model[prev][cur]) / modelScale
+		count++
+
+		prev = cur
+	}
+
+	if prev != modelBoundary {
+		bits += float64(model[prev][modelBoundary]) / modelScale
+		count++
+	}
+
+	// text entirely in another script has nothing to measure
+	if count < 3 {
+		return 0.75
+	}
+
+	// real text averages around four bits a character, while random
+	// printable bytes cost close to the full width of the model
+	return utilClamp((8.5 - bits/float64(count)) / 4)
+}
+
+// utilScoreClasses will score the mix of characters in the text, text
+// is mostly letters with the odd digit, space or punctuation
+func utilScoreClasses(text string) float64 {
+	var total, letters, digits, spaces, symbols, switches int
+	var prev int
+
+	for _, r := range text {
+		var class int
+
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+			class = 1
+		case unicode.IsDigit(r):
+			digits++
+			class = 2
+		case unicode.IsSpace(r):
+			spaces++
+			class = 3
+		default:
+			symbols++
+			class = 4
+		}
+
+		if prev != 0 && class != prev {
+			switches++
+		}
+
+		prev = class
+		total++
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	n := float64(total)
+	score := 1.0
+
+	// symbols beyond a quarter of the text are unusual outside of
+	// format strings, and these are still mostly letters
+	if ratio := float64(symbols) / n; ratio > 0.25 {
+		score -= (ratio - 0.25) * 2
+	}
+
+	if letters == 0 && digits != total {
+		score -= 0.5
+	}
+
+	// constantly switching between classes is a sign of random bytes,
+	// words keep the same class for a few characters at a time
+	if ratio := float64(switches) / n; ratio > 0.5 {
+		score -= (ratio - 0.5) * 2
+	}
+
+	return utilClamp(score)
+}
+
+// utilScoreEntropy will score the spread of characters in the text
+func utilScoreEntropy(text string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+
+	for _, r := range text {
+		counts[r]++
+		total++
+	}
+
+	if total < 4 {
+		return 0.5
+	}
+
+	var entropy float64
+	for _, c := range counts {
+		p := float64(c) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+
+	// the entropy is compared to the most it could be for text of this
+	// length, random bytes are nearly always every character different
+	max := math.Log2(math.Min(float64(total), 95))
+	ratio := entropy / max
+
+	// the same character over and over is padding, not text
+	if entropy < 1 {
+		return entropy / 2
+	}
+
+	// short text has too few characters for their spread to mean much
+	if total < 16 {
+		return 1
+	}
+
+	return utilClamp((1 - ratio) * 5)
+}
+
+// utilClamp will clamp the value between 0 and 1
+func utilClamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,238 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// SectionMode to emulate an enum of the ways sections can be selected
+type SectionMode int32
+
+// Modes of section selection that may be used
+const (
+	// SectionsDefault selects the sections in DefaultSections
+	SectionsDefault SectionMode = iota
+	// SectionsAll selects every section that has data in the file
+	SectionsAll
+	// SectionsMatch selects sections by a glob or regular expression
+	SectionsMatch
+	// SectionsFlags selects sections by their SHF_* flags
+	SectionsFlags
+	// SectionsType selects sections by their SHT_* type
+	SectionsType
+	sectionsEnd
+)
+
+// SectionSelector describes which sections of the binary are scanned
+type SectionSelector struct {
+	Mode SectionMode
+	// Names is the list used by SectionsDefault, DefaultSections when nil
+	Names []string
+	// Globs are the name patterns used by SectionsMatch
+	Globs []string
+	// Regex is the name expression used by SectionsMatch
+	Regex *regexp.Regexp
+	// Flags must all be set for a section to be picked by SectionsFlags
+	Flags elf.SectionFlag
+	// NoExec skips sections with SHF_EXECINSTR in SectionsFlags
+	NoExec bool
+	// Types are the section types picked by SectionsType
+	Types []elf.SectionType
+}
+
+// SkippedSection is a section that was not selected, and the reason why
+type SkippedSection struct {
+	Name   string
+	Reason string
+}
+
+// sectionTypes are the types which can be passed by name
+var sectionTypes = []elf.SectionType{
+	elf.SHT_PROGBITS, elf.SHT_SYMTAB, elf.SHT_STRTAB, elf.SHT_RELA,
+	elf.SHT_HASH, elf.SHT_DYNAMIC, elf.SHT_NOTE, elf.SHT_REL,
+	elf.SHT_DYNSYM, elf.SHT_INIT_ARRAY, elf.SHT_FINI_ARRAY,
+	elf.SHT_PREINIT_ARRAY, elf.SHT_GROUP, elf.SHT_GNU_HASH,
+	elf.SHT_GNU_VERDEF, elf.SHT_GNU_VERNEED, elf.SHT_GNU_VERSYM,
+}
+
+// sectionFlags are the flags which can be passed by name
+var sectionFlags = map[string]elf.SectionFlag{
+	"write":   elf.SHF_WRITE,
+	"alloc":   elf.SHF_ALLOC,
+	"exec":    elf.SHF_EXECINSTR,
+	"merge":   elf.SHF_MERGE,
+	"strings": elf.SHF_STRINGS,
+	"tls":     elf.SHF_TLS,
+}
+
+// ReaderSelectSections will pick the sections to scan using the selector,
+// returning those selected and the names of those skipped along with
+// the reason they were skipped. The sections are returned rather than
+// their names as more than one section may have the same name
+func (r *ElfReader) ReaderSelectSections(sel *SectionSelector) ([]*elf.Section, []SkippedSection) {
+	var selected []*elf.Section
+	var skipped []SkippedSection
+
+	names := sel.Names
+	if names == nil {
+		names = DefaultSections
+	}
+
+	for i, s := range r.ExecReader.Sections {
+		name := s.Name
+		if name == "" {
+			name = fmt.Sprintf("[%d]", i)
+		}
+
+		reason := sectionSkipReason(s, sel, names)
+		if reason != "" {
+			skipped = append(skipped, SkippedSection{Name: name, Reason: reason})
+			continue
+		}
+
+		selected = append(selected, s)
+	}
+
+	return selected, skipped
+}
+
+// sectionSkipReason will return why the section should not be
+// scanned, or an empty string if it should be
+func sectionSkipReason(s *elf.Section, sel *SectionSelector, names []string) string {
+	if s.Type == elf.SHT_NULL {
+		return "null section"
+	}
+
+	if s.Type == elf.SHT_NOBITS {
+		return "occupies no space in the file (SHT_NOBITS)"
+	}
+
+	if s.Size == 0 {
+		return "empty section"
+	}
+
+	switch sel.Mode {
+	case SectionsDefault:
+		for _, name := range names {
+			if s.Name == name {
+				return ""
+			}
+		}
+
+		return "not in the default section list"
+	case SectionsMatch:
+		for _, glob := range sel.Globs {
+			if ok, _ := path.Match(glob, s.Name); ok {
+				return ""
+			}
+		}
+
+		if sel.Regex != nil && sel.Regex.MatchString(s.Name) {
+			return ""
+		}
+
+		return "name does not match"
+	case SectionsFlags:
+		if s.Flags&sel.Flags != sel.Flags {
+			return fmt.Sprintf("missing flags (has %s)", s.Flags)
+		}
+
+		if sel.NoExec && s.Flags&elf.SHF_EXECINSTR != 0 {
+			return "executable section"
+		}
+	case SectionsType:
+		for _, typ := range sel.Types {
+			if s.Type == typ {
+				return ""
+			}
+		}
+
+		return fmt.Sprintf("type %s not selected", s.Type)
+	}
+
+	return ""
+}
+
+// SectionParseModeStr converts from a string to a section mode
+// default is the default section list
+func SectionParseModeStr(mode string) (SectionMode, error) {
+	switch strings.ToLower(mode) {
+	case "", "default":
+		return SectionsDefault, nil
+	case "all":
+		return SectionsAll, nil
+	case "match":
+		return SectionsMatch, nil
+	case "flags":
+		return SectionsFlags, nil
+	case "type":
+		return SectionsType, nil
+	}
+
+	return sectionsEnd, errors.New("this section mode does not exist")
+}
+
+// SectionParseFlagsStr converts a comma separated list of flags such as
+// "strings,alloc,noexec" into the flags that must be set, and whether
+// executable sections are skipped
+func SectionParseFlagsStr(list string) (elf.SectionFlag, bool, error) {
+	var flags elf.SectionFlag
+	var noExec bool
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		if name == "noexec" {
+			noExec = true
+			continue
+		}
+
+		flag, ok := sectionFlags[name]
+		if !ok {
+			return 0, false, fmt.Errorf("unknown section flag %q", name)
+		}
+
+		flags |= flag
+	}
+
+	return flags, noExec, nil
+}
+
+// SectionParseTypesStr converts a comma separated list of types such as
+// "progbits,note" into their section types
+func SectionParseTypesStr(list string) ([]elf.SectionType, error) {
+	var types []elf.SectionType
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(name, "SHT_") {
+			name = "SHT_" + name
+		}
+
+		found := false
+		for _, typ := range sectionTypes {
+			if typ.String() == name {
+				types = append(types, typ)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unknown section type %q", name)
+		}
+	}
+
+	return types, nil
+}
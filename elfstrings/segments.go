@@ -0,0 +1,72 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"fmt"
+)
+
+// Segment is a PT_LOAD segment that maps part of the file into memory
+type Segment struct {
+	// Index is the index of the program header
+	Index int
+	// Prog is the program header of the segment
+	Prog *elf.Prog
+}
+
+// Name will return the name of the segment, by its program header index
+func (s *Segment) Name() string {
+	return fmt.Sprintf("LOAD[%d]", s.Index)
+}
+
+// Perms will return the R/W/X permissions of the segment
+// in the same form as readelf
+func (s *Segment) Perms() string {
+	return UtilProgPerms(s.Prog.Flags)
+}
+
+// ReaderSegmentAt will find the PT_LOAD segment which maps
+// the given file offset, or nil if it is not loaded
+func (r *ElfReader) ReaderSegmentAt(fileOff uint64) *Segment {
+	for i, p := range r.ExecReader.Progs {
+		if p.Type != elf.PT_LOAD {
+			continue
+		}
+
+		if fileOff >= p.Off && fileOff < p.Off+p.Filesz {
+			return &Segment{Index: i, Prog: p}
+		}
+	}
+
+	return nil
+}
+
+// ReaderAddressOf will convert a file offset to the virtual address
+// that it is loaded at, the second value is false if it is not loaded
+func (r *ElfReader) ReaderAddressOf(fileOff uint64) (uint64, bool) {
+	seg := r.ReaderSegmentAt(fileOff)
+	if seg == nil {
+		return 0, false
+	}
+
+	return seg.Prog.Vaddr + (fileOff - seg.Prog.Off), true
+}
+
+// UtilProgPerms converts the flags of a program header to a
+// permission string such as "r-x"
+func UtilProgPerms(flags elf.ProgFlag) string {
+	perms := []byte("---")
+
+	if flags&elf.PF_R != 0 {
+		perms[0] = 'r'
+	}
+
+	if flags&elf.PF_W != 0 {
+		perms[1] = 'w'
+	}
+
+	if flags&elf.PF_X != 0 {
+		perms[2] = 'x'
+	}
+
+	return string(perms)
+}
@@ -0,0 +1,34 @@
+package elfstrings
+
+import (
+	"fmt"
+)
+
+// Source to emulate an enum of where a string was recovered from
+type Source int32
+
+// Sources that strings are recovered from
+const (
+	// SourceSection is a string stored in the file as it is
+	SourceSection Source = iota
+	// SourceStack is a string built on the stack by the code
+	SourceStack
+	// SourceDecoded is a string decoded at runtime, found by emulation
+	SourceDecoded
+	sourceEnd
+)
+
+var sourceNames = map[Source]string{
+	SourceSection: "section",
+	SourceStack:   "stack",
+	SourceDecoded: "decoded",
+}
+
+// String will return the name of the source
+func (s Source) String() string {
+	if name, ok := sourceNames[s]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("source(%d)", int32(s))
+}
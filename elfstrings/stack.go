@@ -0,0 +1,175 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"sort"
+)
+
+// stackByte is a byte that an instruction stored to the stack
+type stackByte struct {
+	val byte
+	at  uint64
+}
+
+// stackKey is a slot on the stack, relative to the register it is
+// addressed from, either the stack pointer or the frame pointer
+type stackKey struct {
+	base int
+	off  int64
+}
+
+// stackBlock collects the immediate stores to the stack within a basic
+// block, emit is passed each contiguous run of them once the block ends
+// along with the address of the first instruction that stored to it
+type stackBlock struct {
+	bytes map[stackKey]stackByte
+	emit  func(buf []byte, at uint64)
+}
+
+// newStackBlock will create an empty block
+func newStackBlock(emit func(buf []byte, at uint64)) *stackBlock {
+	return &stackBlock{bytes: make(map[stackKey]stackByte), emit: emit}
+}
+
+// store will record the little endian value of size bytes stored at the
+// offset from the base register by the instruction at pc
+func (b *stackBlock) store(base int, off int64, val uint64, size int, pc uint64) {
+	for i := 0; i < size; i++ {
+		b.bytes[stackKey{base: base, off: off + int64(i)}] = stackByte{val: byte(val >> (8 * uint(i))), at: pc}
+	}
+}
+
+// clobber will forget the bytes overwritten by a store of an unknown value
+func (b *stackBlock) clobber(base int, off int64, size int) {
+	for i := 0; i < size; i++ {
+		delete(b.bytes, stackKey{base: base, off: off + int64(i)})
+	}
+}
+
+// flush will emit the contiguous runs of bytes stored in the block
+// and start a new one
+func (b *stackBlock) flush() {
+	if len(b.bytes) == 0 {
+		return
+	}
+
+	keys := make([]stackKey, 0, len(b.bytes))
+	for key := range b.bytes {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].base != keys[j].base {
+			return keys[i].base < keys[j].base
+		}
+
+		return keys[i].off < keys[j].off
+	})
+
+	var buf []byte
+	var at uint64
+	for i, key := range keys {
+		if i > 0 && (key.base != keys[i-1].base || key.off != keys[i-1].off+1) {
+			b.emit(buf, at)
+			buf = nil
+		}
+
+		stored := b.bytes[key]
+		if buf == nil || stored.at < at {
+			at = stored.at
+		}
+
+		buf = append(buf, stored.val)
+	}
+
+	b.emit(buf, at)
+	b.bytes = make(map[stackKey]stackByte)
+}
+
+// ReaderStackStrings will recover the strings that the code builds on the
+// stack from immediate stores, which never appear in any section. Each is
+// attributed to the first instruction that builds it and the function that
+// contains it. Only x86, x86-64 and AArch64 code is decoded
+func (r *ElfReader) ReaderStackStrings(opts *Options) []StringRecord {
+	var records []StringRecord
+
+	for _, s := range r.ExecReader.Sections {
+		if s.Type != elf.SHT_PROGBITS || s.Flags&elf.SHF_EXECINSTR == 0 {
+			continue
+		}
+
+		code, err := r.ReaderReadAt(s.Offset, s.Size)
+		if err != nil {
+			continue
+		}
+
+		block := newStackBlock(func(buf []byte, at uint64) {
+			fn, _ := r.ReaderFunctionStart(at)
+			records = append(records, r.readerBuiltRecords(s, buf, at, SourceStack, fn, opts)...)
+		})
+
+		switch r.ExecReader.Machine {
+		case elf.EM_X86_64:
+			utilStackX86(code, s.Addr, 64, block)
+		case elf.EM_386:
+			utilStackX86(code, s.Addr, 32, block)
+		case elf.EM_AARCH64:
+			utilStackARM64(code, s.Addr, block)
+		}
+
+		block.flush()
+	}
+
+	return UtilLimitRecords(records, opts)
+}
+
+// readerBuiltRecords will find the strings within the bytes that the code
+// at the address builds, and filter them like any other string. fn is the
+// address of the function that builds them
+func (r *ElfReader) readerBuiltRecords(s *elf.Section, buf []byte, at uint64, source Source, fn uint64, opts *Options) []StringRecord {
+	var records []StringRecord
+
+	minLength := opts.MinLength
+	if minLength == 0 {
+		minLength = ScanMinLength
+	}
+
+	record := func(raw []byte) func(f *FilteredString) StringRecord {
+		return func(f *FilteredString) StringRecord {
+			rec := r.readerRecord(s, at-s.Addr, raw, f)
+			rec.Source = source
+			rec.FunctionAddress = fn
+
+			return rec
+		}
+	}
+
+	for _, enc := range opts.encodings() {
+		if enc != EncodingASCII {
+			for _, w := range UtilWideStrings(buf, 0, enc, opts.Unaligned) {
+				if f := UtilFilterWide(&w, enc, opts); f != nil {
+					records = append(records, record(w.Raw)(f))
+				}
+			}
+
+			continue
+		}
+
+		// the runs are cut at the terminator and at anything which
+		// is not printable, such as a length stored alongside them
+		start := 0
+		for i := 0; i <= len(buf); i++ {
+			if i < len(buf) && (UtilIsPrintable(buf[i]) || buf[i] >= 0x80) {
+				continue
+			}
+
+			if raw := buf[start:i]; uint64(len(raw)) >= minLength {
+				records = append(records, r.readerWithUnwrapped(raw, UtilFilterString(raw, opts), record(raw), opts)...)
+			}
+
+			start = i + 1
+		}
+	}
+
+	return records
+}
@@ -0,0 +1,100 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"testing"
+)
+
+func TestStackStringsX86(t *testing.T) {
+	const text = 0x401000
+
+	str := "powershell -nop -w hidden -enc"
+
+	// mov dword [base+off], imm32 with the shortest displacement, base is
+	// 5 for rbp and 4 for rsp, which needs a SIB byte
+	immediate := func(base byte, off int32, val uint32) []byte {
+		inst := []byte{0xc7}
+		if off == int32(int8(off)) {
+			inst = append(inst, 0x40|base)
+		} else {
+			inst = append(inst, 0x80|base)
+		}
+
+		if base == 4 {
+			inst = append(inst, 0x24)
+		}
+
+		if off == int32(int8(off)) {
+			inst = append(inst, byte(off))
+		} else {
+			inst = append(inst, 0, 0, 0, 0)
+			binary.LittleEndian.PutUint32(inst[len(inst)-4:], uint32(off))
+		}
+
+		imm := make([]byte, 4)
+		binary.LittleEndian.PutUint32(imm, val)
+
+		return append(inst, imm...)
+	}
+
+	tests := []struct {
+		name  string
+		base  byte
+		start int32
+		// through a register is mov eax, imm32; mov [base+off], eax
+		register bool
+	}{
+		{"rbp byte displacements", 5, -0x40, false},
+		{"rbp byte and dword displacements", 5, -0x90, false},
+		{"rbp dword displacements", 5, -0x200, false},
+		{"rsp dword displacements", 4, 0x100, false},
+		{"rbp through a register", 5, -0x90, true},
+	}
+
+	for _, tt := range tests {
+		buf := []byte(str)
+		buf = append(buf, make([]byte, 4-len(buf)%4)...)
+
+		var code []byte
+		for i := 0; i < len(buf); i += 4 {
+			val := binary.LittleEndian.Uint32(buf[i:])
+			inst := immediate(tt.base, tt.start+int32(i), val)
+
+			if tt.register {
+				// the same operand as the immediate store without the
+				// immediate, after the opcode of a store of eax
+				load := []byte{0xb8, 0, 0, 0, 0}
+				binary.LittleEndian.PutUint32(load[1:], val)
+				inst = append(append(load, 0x89), inst[1:len(inst)-4]...)
+			}
+
+			code = append(code, inst...)
+		}
+
+		code = append(code, 0xc3)
+
+		r := testELF(t, []testSection{
+			{name: ".text", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC | elf.SHF_EXECINSTR, addr: text, data: code},
+		}, nil)
+
+		// the string is from the first store, after the load of eax
+		at := uint64(text)
+		if tt.register {
+			at += 5
+		}
+
+		var got []string
+		for _, rec := range r.ReaderStackStrings(&Options{}) {
+			got = append(got, rec.Text)
+
+			if rec.Source != SourceStack || rec.Address != at {
+				t.Errorf("%s: %q is from %s at %#x, want the stack at %#x", tt.name, rec.Text, rec.Source, rec.Address, at)
+			}
+		}
+
+		if len(got) != 1 || got[0] != str {
+			t.Errorf("%s: built %q, want %q", tt.name, got, str)
+		}
+	}
+}
@@ -0,0 +1,551 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The sizes of the records of the reflection metadata of Swift
+const (
+	swiftFieldDescriptorSize = 16
+	swiftFieldRecordSize     = 12
+)
+
+// The kinds of the context descriptors of Swift, in the low bits of their
+// flags
+const (
+	swiftContextModule    = 0
+	swiftContextExtension = 1
+	swiftContextAnonymous = 2
+	swiftContextProtocol  = 3
+	swiftContextClass     = 16
+	swiftContextStruct    = 17
+	swiftContextEnum      = 18
+)
+
+// SwiftTypeKind to emulate an enum of the kinds of the Swift types that
+// the reflection metadata describes
+type SwiftTypeKind int32
+
+// Kinds of the field descriptors of Swift, in the order of their values
+const (
+	SwiftStruct SwiftTypeKind = iota
+	SwiftClass
+	SwiftEnum
+	// SwiftMultiPayloadEnum is an enum with more than one case which
+	// carries a payload
+	SwiftMultiPayloadEnum
+	SwiftProtocol
+	SwiftClassProtocol
+	SwiftObjCProtocol
+	SwiftObjCClass
+	swiftTypeEnd
+)
+
+var swiftTypeNames = map[SwiftTypeKind]string{
+	SwiftStruct:           "struct",
+	SwiftClass:            "class",
+	SwiftEnum:             "enum",
+	SwiftMultiPayloadEnum: "enum",
+	SwiftProtocol:         "protocol",
+	SwiftClassProtocol:    "protocol",
+	SwiftObjCProtocol:     "@objc protocol",
+	SwiftObjCClass:        "@objc class",
+}
+
+// swiftContextKinds are the kinds of the types for the kinds of their
+// context descriptors, and the nodes that they are demangled into
+var swiftContextKinds = map[uint32]struct {
+	kind SwiftTypeKind
+	node swiftKind
+}{
+	swiftContextProtocol: {SwiftProtocol, swiftProtocol},
+	swiftContextClass:    {SwiftClass, swiftClass},
+	swiftContextStruct:   {SwiftStruct, swiftStructure},
+	swiftContextEnum:     {SwiftEnum, swiftEnum},
+}
+
+// String will return the keyword that declares the kind of type
+func (k SwiftTypeKind) String() string {
+	if name, ok := swiftTypeNames[k]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("kind(%d)", int32(k))
+}
+
+// IsEnum will check whether the kind is that of an enum, whose fields are
+// its cases
+func (k SwiftTypeKind) IsEnum() bool {
+	return k == SwiftEnum || k == SwiftMultiPayloadEnum
+}
+
+// SwiftField is a stored property of a Swift type, or a case of an enum
+type SwiftField struct {
+	Name string
+	// Type is the type of the property or the payload of the case, empty
+	// for a case without one
+	Type string
+	// Var is whether the property is declared with var rather than let
+	Var bool
+	// Indirect is whether the case is declared indirect
+	Indirect bool
+}
+
+// SwiftType is a type of a Swift binary, read from its reflection metadata
+type SwiftType struct {
+	Name string
+	Kind SwiftTypeKind
+	// Superclass is the class that a class inherits from, empty when it
+	// has none
+	Superclass string
+	Fields     []SwiftField
+	// Conformances are the protocols that the type conforms to
+	Conformances []string
+}
+
+// swiftReflection is the state of reading the reflection metadata, which
+// is laid out with 32 bit offsets relative to where each of them is
+type swiftReflection struct {
+	r     *ElfReader
+	mem   *memory
+	named map[uint64]string
+	word  int
+	opts  *DemangleOptions
+	// contexts are the nodes of the context descriptors which were read,
+	// by their addresses
+	contexts map[uint64]*swiftNode
+	depth    int
+}
+
+// ReaderSwiftTypes will read the types of a Swift binary from its
+// reflection metadata, the stored properties and cases from swift5_fieldmd
+// along with their names from swift5_reflstr and their types from
+// swift5_typeref, the protocols from swift5_protocols and the conformances
+// to them from swift5_proto. The types without any fields are found from
+// swift5_types. They are ordered by name
+func (r *ElfReader) ReaderSwiftTypes(opts *DemangleOptions) ([]SwiftType, error) {
+	if opts == nil {
+		opts = &DemangleOptions{}
+	}
+
+	sections := make(map[string]*elf.Section)
+	for _, s := range r.ExecReader.Sections {
+		if strings.HasPrefix(s.Name, "swift5_") && s.Type != elf.SHT_NOBITS {
+			sections[s.Name] = s
+		}
+	}
+
+	if sections["swift5_fieldmd"] == nil && sections["swift5_types"] == nil && sections["swift5_protocols"] == nil {
+		return nil, errors.New("not a Swift binary")
+	}
+
+	sw := &swiftReflection{
+		r:        r,
+		mem:      r.readerMemory(),
+		named:    r.readerSymbolRelocs(),
+		word:     4,
+		opts:     opts,
+		contexts: make(map[uint64]*swiftNode),
+	}
+
+	if r.ExecReader.Class == elf.ELFCLASS64 {
+		sw.word = 8
+	}
+
+	types := make(map[string]*SwiftType)
+	add := func(name string, kind SwiftTypeKind) *SwiftType {
+		ty, ok := types[name]
+		if !ok {
+			ty = &SwiftType{Name: name, Kind: kind}
+			types[name] = ty
+		}
+
+		return ty
+	}
+
+	if s := sections["swift5_fieldmd"]; s != nil {
+		sw.fieldDescriptors(s, add)
+	}
+
+	// the protocols and the types without fields are only listed
+	for _, name := range []string{"swift5_protocols", "swift5_types"} {
+		s := sections[name]
+		if s == nil {
+			continue
+		}
+
+		for addr := s.Addr; addr+4 <= s.Addr+s.Size; addr += 4 {
+			desc, ok := sw.relative(addr)
+			if !ok {
+				continue
+			}
+
+			// the low bits of the offsets in swift5_types are the kind
+			// of the reference, only the direct ones are followed
+			if name == "swift5_types" {
+				if desc&3 != 0 {
+					continue
+				}
+			} else if desc&1 != 0 {
+				desc, ok = sw.load(desc &^ 1)
+				if !ok {
+					continue
+				}
+			}
+
+			flags, ok := sw.mem.load(desc, 4)
+			kind, known := swiftContextKinds[uint32(flags)&0x1f]
+			if !ok || !known {
+				continue
+			}
+
+			if n := sw.context(desc); n != nil {
+				add(sw.print(n), kind.kind)
+			}
+		}
+	}
+
+	if s := sections["swift5_proto"]; s != nil {
+		for addr := s.Addr; addr+4 <= s.Addr+s.Size; addr += 4 {
+			desc, ok := sw.relative(addr)
+			if !ok {
+				continue
+			}
+
+			ty, proto := sw.conformance(desc)
+			if ty == "" || proto == "" {
+				continue
+			}
+
+			conforming, ok := types[ty]
+			if !ok {
+				continue
+			}
+
+			seen := false
+			for _, c := range conforming.Conformances {
+				seen = seen || c == proto
+			}
+
+			if !seen {
+				conforming.Conformances = append(conforming.Conformances, proto)
+			}
+		}
+	}
+
+	var all []SwiftType
+	for _, ty := range types {
+		sort.Strings(ty.Conformances)
+		all = append(all, *ty)
+	}
+
+	if len(all) == 0 {
+		return nil, errors.New("no Swift reflection metadata")
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+
+	return all, nil
+}
+
+// fieldDescriptors will read the field descriptors of swift5_fieldmd, each
+// the type that it describes, its superclass and then its fields
+func (sw *swiftReflection) fieldDescriptors(s *elf.Section, add func(name string, kind SwiftTypeKind) *SwiftType) {
+	order := sw.mem.order
+
+	for addr := s.Addr; addr+swiftFieldDescriptorSize <= s.Addr+s.Size; {
+		buf := sw.mem.at(addr)
+		if len(buf) < swiftFieldDescriptorSize {
+			return
+		}
+
+		kind := SwiftTypeKind(order.Uint16(buf[8:]))
+		size := uint64(order.Uint16(buf[10:]))
+		count := uint64(order.Uint32(buf[12:]))
+		// a descriptor whose records run past the end of the section is
+		// truncated, and none of what follows it lines up either
+		end := addr + swiftFieldDescriptorSize + count*swiftFieldRecordSize
+		if kind >= swiftTypeEnd || (count != 0 && size != swiftFieldRecordSize) || end > s.Addr+s.Size {
+			return
+		}
+
+		name := sw.typeName(addr)
+		if name != "" {
+			ty := add(name, kind)
+			ty.Superclass = sw.typeName(addr + 4)
+
+			for i := uint64(0); i < count; i++ {
+				rec := addr + swiftFieldDescriptorSize + i*swiftFieldRecordSize
+
+				flags, _ := sw.mem.load(rec, 4)
+				field := SwiftField{
+					Type:     sw.typeName(rec + 4),
+					Indirect: flags&1 != 0,
+					Var:      flags&2 != 0,
+				}
+
+				if at, ok := sw.relative(rec + 8); ok {
+					field.Name, _ = sw.mem.loadString(at, swiftMaxLength)
+				}
+
+				ty.Fields = append(ty.Fields, field)
+			}
+		}
+
+		addr = end
+	}
+}
+
+// conformance will read the protocol conformance descriptor at the
+// address, and return the type which conforms and the protocol
+func (sw *swiftReflection) conformance(desc uint64) (string, string) {
+	proto := sw.indirectable(desc)
+	if proto == nil {
+		return "", ""
+	}
+
+	target, ok := sw.relative(desc + 4)
+	flags, _ := sw.mem.load(desc+12, 4)
+	if !ok {
+		return "", ""
+	}
+
+	// the kind of the reference to the type is in the flags, a context
+	// descriptor, a pointer to one or the name of an Objective-C class
+	var ty *swiftNode
+	switch (flags >> 3) & 7 {
+	case 0:
+		ty = sw.context(target)
+	case 1:
+		ty = sw.indirect(target)
+	case 2:
+		name, _ := sw.mem.loadString(target, swiftMaxLength)
+		return name, sw.print(proto)
+	}
+
+	if ty == nil {
+		return "", ""
+	}
+
+	return sw.print(ty), sw.print(proto)
+}
+
+// typeName will demangle the type whose mangled name the 32 bit offset at
+// the address points to, empty when there is none
+func (sw *swiftReflection) typeName(addr uint64) string {
+	at, ok := sw.relative(addr)
+	if !ok {
+		return ""
+	}
+
+	mangled := sw.mangledName(at)
+	if mangled == "" {
+		return ""
+	}
+
+	text, err := utilDemangleSwiftType(mangled, sw.resolver(at), sw.opts)
+	if err == nil {
+		return text
+	}
+
+	// the names which could not be demangled are shown as they are when
+	// they are text, those of the types imported from C are
+	if utilIsPrefixedText([]byte(mangled)) {
+		return mangled
+	}
+
+	return "?"
+}
+
+// mangledName will read the mangled name at the address, which ends in a
+// NUL that is not within the offset of a symbolic reference
+func (sw *swiftReflection) mangledName(addr uint64) string {
+	buf := sw.mem.at(addr)
+
+	i := 0
+	for i < len(buf) && buf[i] != 0 && i < swiftMaxLength {
+		switch c := buf[i]; {
+		case c >= 0x01 && c <= 0x17:
+			i += 5
+		case c >= 0x18 && c <= 0x1f:
+			i += 1 + sw.word
+		default:
+			i++
+		}
+	}
+
+	if i > len(buf) || i >= swiftMaxLength {
+		return ""
+	}
+
+	return string(buf[:i])
+}
+
+// resolver will resolve the symbolic references of the mangled name at the
+// address, each a context descriptor or a pointer to one
+func (sw *swiftReflection) resolver(base uint64) func(kind byte, pos int) *swiftNode {
+	return func(kind byte, pos int) *swiftNode {
+		target, ok := sw.relative(base + uint64(pos))
+		if !ok {
+			return nil
+		}
+
+		switch kind {
+		case 0x01:
+			return sw.context(target)
+		case 0x02:
+			return sw.indirect(target)
+		}
+
+		return nil
+	}
+}
+
+// context will build the node of the context descriptor at the address,
+// the type that it describes or the module. The anonymous contexts of the
+// private types are left out of their names
+func (sw *swiftReflection) context(desc uint64) *swiftNode {
+	if n, ok := sw.contexts[desc]; ok {
+		return n
+	}
+
+	if sw.depth > swiftMaxDepth {
+		return nil
+	}
+
+	sw.depth++
+	defer func() { sw.depth-- }()
+
+	// a descriptor which is malformed into a cycle is not followed again
+	sw.contexts[desc] = nil
+
+	flags, ok := sw.mem.load(desc, 4)
+	if !ok {
+		return nil
+	}
+
+	var n *swiftNode
+	switch kind := uint32(flags) & 0x1f; kind {
+	case swiftContextModule:
+		if name := sw.name(desc + 8); name != "" {
+			n = &swiftNode{kind: swiftModule, text: name}
+		}
+	case swiftContextExtension:
+		if at, ok := sw.relative(desc + 8); ok {
+			d := &swiftDemangler{sym: sw.mangledName(at), resolve: sw.resolver(at)}
+			n = d.demangleType()
+		}
+	case swiftContextAnonymous:
+		n = sw.indirectable(desc + 4)
+	default:
+		node, ok := swiftContextKinds[kind]
+		parent := sw.indirectable(desc + 4)
+		name := sw.name(desc + 8)
+		if !ok || parent == nil || name == "" {
+			break
+		}
+
+		if parent.kind == swiftType {
+			parent = parent.children[0]
+		}
+
+		ident := &swiftNode{kind: swiftIdentifier, text: name}
+		n = &swiftNode{kind: swiftType, children: []*swiftNode{{kind: node.node, children: []*swiftNode{parent, ident}}}}
+	}
+
+	sw.contexts[desc] = n
+
+	return n
+}
+
+// indirectable will build the node of the context descriptor that the 32
+// bit offset at the address points to, which is a pointer to it when its
+// low bit is set
+func (sw *swiftReflection) indirectable(addr uint64) *swiftNode {
+	target, ok := sw.relative(addr)
+	if !ok {
+		return nil
+	}
+
+	if target&1 != 0 {
+		return sw.indirect(target &^ 1)
+	}
+
+	return sw.context(target)
+}
+
+// indirect will build the node of the context descriptor that the pointer
+// at the address points to. Those in other modules are named by the symbol
+// of the relocation against the pointer, such as $s10Foundation4DateVMn
+func (sw *swiftReflection) indirect(slot uint64) *swiftNode {
+	if sym, ok := sw.named[slot]; ok {
+		for _, prefix := range swiftPrefixes {
+			if !strings.HasPrefix(sym, prefix) {
+				continue
+			}
+
+			mangled := sym[len(prefix):]
+			if !strings.HasSuffix(mangled, "Mn") && !strings.HasSuffix(mangled, "Mp") {
+				return nil
+			}
+
+			d := &swiftDemangler{sym: mangled[:len(mangled)-2]}
+			return d.demangleType()
+		}
+
+		return nil
+	}
+
+	desc, ok := sw.load(slot)
+	if !ok {
+		return nil
+	}
+
+	return sw.context(desc)
+}
+
+// name will read the name that the 32 bit offset at the address points to
+func (sw *swiftReflection) name(addr uint64) string {
+	at, ok := sw.relative(addr)
+	if !ok {
+		return ""
+	}
+
+	name, _ := sw.mem.loadString(at, swiftMaxLength)
+
+	return name
+}
+
+// relative will follow the 32 bit offset at the address, which is relative
+// to the address, false when it is zero for nothing
+func (sw *swiftReflection) relative(addr uint64) (uint64, bool) {
+	off, ok := sw.mem.load(addr, 4)
+	if !ok || off == 0 {
+		return 0, false
+	}
+
+	return addr + uint64(int64(int32(off))), true
+}
+
+// load will read the pointer at the address
+func (sw *swiftReflection) load(addr uint64) (uint64, bool) {
+	return sw.mem.load(addr, sw.word)
+}
+
+// print will print the node of a type or a context
+func (sw *swiftReflection) print(n *swiftNode) string {
+	p := &swiftPrinter{opts: sw.opts}
+
+	text := p.print(n)
+	if p.failed {
+		return ""
+	}
+
+	return text
+}
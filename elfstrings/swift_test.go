@@ -0,0 +1,177 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestSwiftTypes(t *testing.T) {
+	const (
+		consts  = 0x402000
+		typeref = 0x403000
+		reflstr = 0x404000
+		fieldmd = 0x405000
+		types   = 0x406000
+	)
+
+	// image is a section of the metadata, which is laid out with 32 bit
+	// offsets relative to where each of them is
+	type image struct {
+		addr uint64
+		buf  []byte
+	}
+
+	put := func(img *image, addr uint64, v uint32) {
+		binary.LittleEndian.PutUint32(img.buf[addr-img.addr:], v)
+	}
+
+	rel := func(img *image, addr uint64, to uint64) {
+		put(img, addr, uint32(to-addr))
+	}
+
+	text := func(img *image, addr uint64, s string) {
+		copy(img.buf[addr-img.addr:], s)
+	}
+
+	// the context descriptors of the module and of its types, the flags,
+	// the parent and the name. Loop and Knot are each other's parent
+	const (
+		module, item, cart, state, loop, knot, partial = consts, consts + 0x10, consts + 0x20, consts + 0x30, consts + 0x40, consts + 0x50, consts + 0x60
+	)
+
+	ctx := &image{addr: consts, buf: make([]byte, 0x200)}
+	names := consts + 0x100
+	for _, desc := range []struct {
+		addr   uint64
+		flags  uint32
+		parent uint64
+		name   string
+	}{
+		{module, swiftContextModule, 0, "Shop"},
+		{item, 0x40 | swiftContextStruct, module, "Item"},
+		{cart, 0x80 | swiftContextClass, module, "Cart"},
+		{state, 0x40 | swiftContextEnum, module, "State"},
+		{loop, swiftContextStruct, knot, "Loop"},
+		{knot, swiftContextStruct, loop, "Knot"},
+		{partial, swiftContextStruct, module, "Partial"},
+	} {
+		put(ctx, desc.addr, desc.flags)
+		if desc.parent != 0 {
+			rel(ctx, desc.addr+4, desc.parent)
+		}
+
+		rel(ctx, desc.addr+8, uint64(names))
+		text(ctx, uint64(names), desc.name)
+		names += len(desc.name) + 1
+	}
+
+	// the mangled names of the types, those of this module refer to their
+	// context descriptors with a 0x01 and the offset to it
+	ref := &image{addr: typeref, buf: make([]byte, 0x100)}
+	symbolic := func(addr uint64, prefix string, desc uint64, suffix string) {
+		text(ref, addr, prefix+"\x01")
+		rel(ref, addr+uint64(len(prefix))+1, desc)
+		text(ref, addr+uint64(len(prefix))+5, suffix)
+	}
+
+	const (
+		tItem, tCart, tState, tBase, tInt, tString, tItems, tPartial = typeref, typeref + 0x10, typeref + 0x20, typeref + 0x30, typeref + 0x40, typeref + 0x48, typeref + 0x50, typeref + 0x60
+	)
+
+	symbolic(tItem, "", item, "")
+	text(ref, tCart, "4Shop4CartC")
+	text(ref, tState, "4Shop5StateO")
+	text(ref, tBase, "4Shop4BaseC")
+	text(ref, tInt, "Si")
+	text(ref, tString, "SS")
+	symbolic(tItems, "Say", item, "G")
+	symbolic(tPartial, "", partial, "")
+
+	str := &image{addr: reflstr, buf: make([]byte, 0x100)}
+	fieldNames := make(map[string]uint64)
+	at := uint64(reflstr)
+	for _, name := range []string{"count", "name", "items", "empty", "full", "size"} {
+		text(str, at, name)
+		fieldNames[name] = at
+		at += uint64(len(name)) + 1
+	}
+
+	// each descriptor is the type, the superclass, the kind, the size of
+	// a record and how many there are, then the records of the fields as
+	// the flags, the type and the name. Partial is cut off after its
+	// first record by the end of the section
+	type record struct {
+		flags uint32
+		typ   uint64
+		name  string
+	}
+
+	var fields []byte
+	descriptor := func(typ, super uint64, kind SwiftTypeKind, count int, records ...record) {
+		img := &image{addr: fieldmd + uint64(len(fields)), buf: make([]byte, 16+12*len(records))}
+		rel(img, img.addr, typ)
+		if super != 0 {
+			rel(img, img.addr+4, super)
+		}
+
+		binary.LittleEndian.PutUint16(img.buf[8:], uint16(kind))
+		binary.LittleEndian.PutUint16(img.buf[10:], swiftFieldRecordSize)
+		put(img, img.addr+12, uint32(count))
+
+		for i, rec := range records {
+			addr := img.addr + 16 + uint64(i)*12
+			put(img, addr, rec.flags)
+			if rec.typ != 0 {
+				rel(img, addr+4, rec.typ)
+			}
+
+			rel(img, addr+8, fieldNames[rec.name])
+		}
+
+		fields = append(fields, img.buf...)
+	}
+
+	descriptor(tItem, 0, SwiftStruct, 2, record{2, tInt, "count"}, record{0, tString, "name"})
+	descriptor(tCart, tBase, SwiftClass, 1, record{2, tItems, "items"})
+	descriptor(tState, 0, SwiftEnum, 2, record{0, 0, "empty"}, record{1, tInt, "full"})
+	descriptor(tPartial, 0, SwiftStruct, 2, record{0, tInt, "size"})
+
+	list := &image{addr: types, buf: make([]byte, 4*6)}
+	for i, desc := range []uint64{item, cart, state, loop, knot, partial} {
+		rel(list, types+uint64(i)*4, desc)
+	}
+
+	r := testELF(t, []testSection{
+		{name: ".rodata", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC, addr: consts, data: ctx.buf},
+		{name: "swift5_typeref", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC, addr: typeref, data: ref.buf},
+		{name: "swift5_reflstr", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC, addr: reflstr, data: str.buf},
+		{name: "swift5_fieldmd", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC, addr: fieldmd, data: fields},
+		{name: "swift5_types", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC, addr: types, data: list.buf},
+	}, nil)
+
+	got, err := r.ReaderSwiftTypes(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []SwiftType{
+		{Name: "Shop.Cart", Kind: SwiftClass, Superclass: "Shop.Base", Fields: []SwiftField{
+			{Name: "items", Type: "[Shop.Item]", Var: true},
+		}},
+		{Name: "Shop.Item", Kind: SwiftStruct, Fields: []SwiftField{
+			{Name: "count", Type: "Swift.Int", Var: true},
+			{Name: "name", Type: "Swift.String"},
+		}},
+		{Name: "Shop.Partial", Kind: SwiftStruct},
+		{Name: "Shop.State", Kind: SwiftEnum, Fields: []SwiftField{
+			{Name: "empty"},
+			{Name: "full", Type: "Swift.Int", Indirect: true},
+		}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("read\n%+v\nwant\n%+v", got, want)
+	}
+}
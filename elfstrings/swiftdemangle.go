@@ -0,0 +1,2166 @@
+package elfstrings
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The limits of a Swift symbol, which hold back those whose substitutions
+// would otherwise repeat or nest without end
+const (
+	swiftMaxDepth  = 256
+	swiftMaxLength = 8192
+	swiftMaxRepeat = 2048
+	swiftMaxWords  = 26
+)
+
+// swiftPrefixes are the prefixes of the symbols of Swift 4.2 and later,
+// with the underscore that Mach-O adds to them
+var swiftPrefixes = []string{"$s", "_$s", "$S", "_$S", "$e", "_$e"}
+
+// swiftKind is the kind of a node of a demangled Swift symbol
+type swiftKind int32
+
+const (
+	swiftGlobal swiftKind = iota
+	swiftType
+	swiftIdentifier
+	swiftModule
+	swiftClass
+	swiftStructure
+	swiftEnum
+	swiftProtocol
+	swiftTypeAlias
+	swiftExtension
+	swiftFunction
+	swiftVariable
+	swiftSubscript
+	swiftAccessor
+	swiftStatic
+	swiftAllocator
+	swiftConstructor
+	swiftDestructor
+	swiftDeallocator
+	swiftExplicitClosure
+	swiftImplicitClosure
+	swiftDefaultArgument
+	swiftInitializer
+	swiftLocalName
+	swiftPrivateName
+	swiftOperatorName
+	swiftEmptyList
+	swiftFirstElement
+	swiftVariadic
+	swiftTuple
+	swiftTupleElement
+	swiftTupleLabel
+	swiftLabelList
+	swiftFunctionType
+	swiftArgumentTuple
+	swiftReturnType
+	swiftThrows
+	swiftAsync
+	swiftSendable
+	swiftBoundGeneric
+	swiftTypeList
+	swiftBuiltin
+	swiftMetatype
+	swiftProtocolList
+	swiftGenericSignature
+	swiftParamCount
+	swiftGenericParam
+	swiftGenericType
+	swiftMemberType
+	swiftAssocTypeRef
+	swiftRequirement
+	swiftConformance
+	swiftIndex
+	// swiftDescribed is written as its text and then its child, such as
+	// "type metadata for " or "inout "
+	swiftDescribed
+	// swiftPair is written as its text with its two children in place of
+	// the verbs, such as "protocol witness for %s in conformance %s"
+	swiftPair
+	// swiftAttribute is an attribute of the function that the symbol is,
+	// written before it such as "@objc "
+	swiftAttribute
+)
+
+// swiftNode is a node of a demangled Swift symbol, the symbol is parsed
+// into a tree of them which is then printed
+type swiftNode struct {
+	kind     swiftKind
+	text     string
+	index    int
+	depth    int
+	children []*swiftNode
+}
+
+// swiftStandardTypes are the types of the Swift standard library that are
+// substituted by a letter after S, along with their kinds
+var swiftStandardTypes = map[byte]swiftNode{
+	'A': {kind: swiftStructure, text: "AutoreleasingUnsafeMutablePointer"}, 'a': {kind: swiftStructure, text: "Array"},
+	'b': {kind: swiftStructure, text: "Bool"}, 'D': {kind: swiftStructure, text: "Dictionary"},
+	'd': {kind: swiftStructure, text: "Double"}, 'f': {kind: swiftStructure, text: "Float"},
+	'h': {kind: swiftStructure, text: "Set"}, 'I': {kind: swiftStructure, text: "DefaultIndices"},
+	'i': {kind: swiftStructure, text: "Int"}, 'J': {kind: swiftStructure, text: "Character"},
+	'N': {kind: swiftStructure, text: "ClosedRange"}, 'n': {kind: swiftStructure, text: "Range"},
+	'O': {kind: swiftStructure, text: "ObjectIdentifier"}, 'P': {kind: swiftStructure, text: "UnsafePointer"},
+	'p': {kind: swiftStructure, text: "UnsafeMutablePointer"}, 'R': {kind: swiftStructure, text: "UnsafeBufferPointer"},
+	'r': {kind: swiftStructure, text: "UnsafeMutableBufferPointer"}, 'S': {kind: swiftStructure, text: "String"},
+	's': {kind: swiftStructure, text: "Substring"}, 'u': {kind: swiftStructure, text: "UInt"},
+	'V': {kind: swiftStructure, text: "UnsafeRawPointer"}, 'v': {kind: swiftStructure, text: "UnsafeMutableRawPointer"},
+	'W': {kind: swiftStructure, text: "UnsafeRawBufferPointer"}, 'w': {kind: swiftStructure, text: "UnsafeMutableRawBufferPointer"},
+	'q': {kind: swiftEnum, text: "Optional"},
+	'B': {kind: swiftProtocol, text: "BinaryFloatingPoint"}, 'E': {kind: swiftProtocol, text: "Encodable"},
+	'e': {kind: swiftProtocol, text: "Decodable"}, 'F': {kind: swiftProtocol, text: "FloatingPoint"},
+	'G': {kind: swiftProtocol, text: "RandomNumberGenerator"}, 'H': {kind: swiftProtocol, text: "Hashable"},
+	'j': {kind: swiftProtocol, text: "Numeric"}, 'K': {kind: swiftProtocol, text: "BidirectionalCollection"},
+	'k': {kind: swiftProtocol, text: "RandomAccessCollection"}, 'L': {kind: swiftProtocol, text: "Comparable"},
+	'l': {kind: swiftProtocol, text: "Collection"}, 'M': {kind: swiftProtocol, text: "MutableCollection"},
+	'm': {kind: swiftProtocol, text: "RangeReplaceableCollection"}, 'Q': {kind: swiftProtocol, text: "Equatable"},
+	'T': {kind: swiftProtocol, text: "Sequence"}, 't': {kind: swiftProtocol, text: "IteratorProtocol"},
+	'U': {kind: swiftProtocol, text: "UnsignedInteger"}, 'X': {kind: swiftProtocol, text: "RangeExpression"},
+	'x': {kind: swiftProtocol, text: "Strideable"}, 'Y': {kind: swiftProtocol, text: "RawRepresentable"},
+	'y': {kind: swiftProtocol, text: "StringProtocol"}, 'Z': {kind: swiftProtocol, text: "SignedInteger"},
+	'z': {kind: swiftProtocol, text: "BinaryInteger"},
+}
+
+// swiftConcurrencyTypes are the types of the concurrency library that are
+// substituted by a letter after Sc
+var swiftConcurrencyTypes = map[byte]swiftNode{
+	'A': {kind: swiftProtocol, text: "Actor"}, 'C': {kind: swiftStructure, text: "CheckedContinuation"},
+	'c': {kind: swiftStructure, text: "UnsafeContinuation"}, 'E': {kind: swiftStructure, text: "CancellationError"},
+	'e': {kind: swiftStructure, text: "UnownedSerialExecutor"}, 'F': {kind: swiftProtocol, text: "Executor"},
+	'f': {kind: swiftProtocol, text: "SerialExecutor"}, 'G': {kind: swiftStructure, text: "TaskGroup"},
+	'g': {kind: swiftStructure, text: "ThrowingTaskGroup"}, 'I': {kind: swiftProtocol, text: "AsyncIteratorProtocol"},
+	'i': {kind: swiftProtocol, text: "AsyncSequence"}, 'J': {kind: swiftStructure, text: "UnownedJob"},
+	'M': {kind: swiftClass, text: "MainActor"}, 'P': {kind: swiftStructure, text: "TaskPriority"},
+	'S': {kind: swiftStructure, text: "AsyncStream"}, 's': {kind: swiftStructure, text: "AsyncThrowingStream"},
+	'T': {kind: swiftStructure, text: "Task"}, 't': {kind: swiftStructure, text: "UnsafeCurrentTask"},
+}
+
+// swiftAccessors are the accessors of the variables and subscripts, after v
+// or i
+var swiftAccessors = map[byte]string{
+	'g': "getter", 's': "setter", 'G': "global getter", 'w': "willset", 'W': "didset",
+	'r': "read", 'M': "modify", 'm': "materializeForSet", 'i': "init",
+}
+
+// swiftMetadata are the descriptions of the metadata of a type, after M
+var swiftMetadata = map[byte]string{
+	'a': "type metadata accessor for ", 'B': "reflection metadata builtin descriptor ",
+	'D': "demangling cache variable for type metadata for ", 'f': "full type metadata for ",
+	'F': "reflection metadata field descriptor ", 'I': "type metadata instantiation cache for ",
+	'i': "type metadata instantiation function for ", 'l': "lazy cache variable for type metadata for ",
+	'L': "type metadata singleton initialization cache for ", 'm': "metaclass for ",
+	'n': "nominal type descriptor for ", 'o': "class metadata base offset for ",
+	'P': "generic type metadata pattern for ", 'r': "type metadata completion function for ",
+	's': "ObjC resilient class stub for ", 't': "full ObjC resilient class stub for ",
+	'u': "method lookup function for ", 'U': "ObjC metadata update function for ",
+}
+
+// swiftConformances are the descriptions of the records of a conformance
+// to a protocol, after M or W
+var swiftConformances = map[string]string{
+	"Mc": "protocol conformance descriptor for ", "MA": "associated type descriptor for ",
+	"WP": "protocol witness table for ", "Wp": "protocol witness table pattern for ",
+	"WG": "generic protocol witness table for ", "Wa": "protocol witness table accessor for ",
+	"WI": "instantiation function for generic protocol witness table for ",
+	"Wr": "resilient protocol witness table for ",
+}
+
+// swiftThunks are the descriptions of the thunks of a function, after T
+var swiftThunks = map[byte]string{
+	'c': "curry thunk of ", 'j': "dispatch thunk of ", 'q': "method descriptor for ",
+	'S': "protocol self-conformance witness for ",
+}
+
+// swiftAttributes are the attributes of a function, after T, which are
+// written before it
+var swiftAttributes = map[byte]string{
+	'o': "@objc ", 'O': "@nonobjc ", 'D': "dynamic ", 'd': "super ", 'm': "merged ",
+	'A': "partial apply forwarder for ", 'a': "partial apply ObjC forwarder for ",
+	'X': "dynamically replaceable variable for ", 'x': "dynamically replaceable key for ",
+	'I': "dynamically replaceable thunk for ", 'E': "distributed thunk for ", 'F': "distributed accessor for ",
+}
+
+// swiftLayouts are the layouts that a generic parameter may be held to
+var swiftLayouts = map[byte]string{
+	'U': "_UnknownLayout", 'R': "_RefCountedObject", 'N': "_NativeRefCountedObject",
+	'C': "AnyObject", 'D': "_NativeClass", 'T': "_Trivial",
+}
+
+// swiftOperatorChars are the characters of the operators, which are
+// mangled as the lowercase letters
+const swiftOperatorChars = "& @/= >    <*!|+?%-~   ^ ."
+
+// errSwiftSymbol is returned for the symbols which are not mangled by Swift,
+// or which use the parts of the mangling that are not demangled
+var errSwiftSymbol = errors.New("not a Swift symbol")
+
+// utilIsSwiftSymbol will check whether the symbol is in the mangling of
+// Swift 4.2 and later
+func utilIsSwiftSymbol(symbol string) bool {
+	for _, prefix := range swiftPrefixes {
+		if strings.HasPrefix(symbol, prefix) && len(symbol) > len(prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UtilDemangleSwift will demangle a Swift symbol the way swift-demangle
+// does, for the functions, variables, metadata, witness tables and thunks.
+// The contexts of a name are written by their names alone, and the parts
+// of the mangling such as specializations are not demangled
+func UtilDemangleSwift(symbol string, opts *DemangleOptions) (string, error) {
+	if opts == nil {
+		opts = &DemangleOptions{}
+	}
+
+	for _, prefix := range swiftPrefixes {
+		if !strings.HasPrefix(symbol, prefix) {
+			continue
+		}
+
+		// anything after a dot was added by LLVM rather than by swiftc
+		sym := symbol[len(prefix):]
+		if i := strings.IndexByte(sym, '.'); i >= 0 {
+			sym = sym[:i]
+		}
+
+		d := &swiftDemangler{sym: sym}
+
+		global := d.demangle()
+		if global == nil {
+			return "", errSwiftSymbol
+		}
+
+		p := &swiftPrinter{opts: opts}
+		text := p.print(global)
+		if p.failed {
+			return "", errSwiftSymbol
+		}
+
+		return text, nil
+	}
+
+	return "", errSwiftSymbol
+}
+
+// utilDemangleSwiftType will demangle the mangled name of a type, as the
+// reflection metadata holds it, resolving its symbolic references with
+// resolve
+func utilDemangleSwiftType(mangled string, resolve func(kind byte, pos int) *swiftNode, opts *DemangleOptions) (string, error) {
+	d := &swiftDemangler{sym: mangled, resolve: resolve}
+
+	ty := d.demangleType()
+	if ty == nil {
+		return "", errSwiftSymbol
+	}
+
+	p := &swiftPrinter{opts: opts}
+	text := p.print(ty)
+	if p.failed {
+		return "", errSwiftSymbol
+	}
+
+	return text, nil
+}
+
+// swiftDemangler is the state of demangling a Swift symbol. The mangling
+// is postfix, each operator pops the nodes that it is made of from the
+// stack and pushes itself
+type swiftDemangler struct {
+	sym    string
+	pos    int
+	stack  []*swiftNode
+	substs []*swiftNode
+	words  []string
+	// resolve will resolve the symbolic reference of the kind whose offset
+	// is at the position, nil when they are not resolved
+	resolve func(kind byte, pos int) *swiftNode
+}
+
+// demangle will run the operators of the symbol up to its end, and gather
+// what is left on the stack into the global node
+func (d *swiftDemangler) demangle() *swiftNode {
+	for d.pos < len(d.sym) {
+		n := d.operator()
+		if n == nil {
+			return nil
+		}
+
+		d.push(n)
+	}
+
+	global := &swiftNode{kind: swiftGlobal}
+	for {
+		attr := d.popKind(swiftAttribute)
+		if attr == nil {
+			break
+		}
+
+		global.children = append(global.children, attr)
+	}
+
+	// anything more than the entity itself was not demangled right
+	if len(d.stack) != 1 {
+		return nil
+	}
+
+	n := d.stack[0]
+	if n.kind == swiftType {
+		n = n.children[0]
+	}
+
+	global.children = append(global.children, n)
+
+	return global
+}
+
+// demangleType will run the operators of a mangled type name, which is a
+// type without the prefix of a symbol, and return the type
+func (d *swiftDemangler) demangleType() *swiftNode {
+	for d.pos < len(d.sym) {
+		n := d.operator()
+		if n == nil {
+			return nil
+		}
+
+		d.push(n)
+	}
+
+	if len(d.stack) != 1 || d.stack[0].kind != swiftType {
+		return nil
+	}
+
+	return d.stack[0]
+}
+
+// peek will return the next character, zero at the end
+func (d *swiftDemangler) peek() byte {
+	if d.pos < len(d.sym) {
+		return d.sym[d.pos]
+	}
+
+	return 0
+}
+
+// next will return the next character and move past it, zero at the end
+func (d *swiftDemangler) next() byte {
+	if d.pos < len(d.sym) {
+		d.pos++
+		return d.sym[d.pos-1]
+	}
+
+	return 0
+}
+
+// nextIf will move past the next character when it is c
+func (d *swiftDemangler) nextIf(c byte) bool {
+	if d.peek() == c {
+		d.pos++
+		return true
+	}
+
+	return false
+}
+
+// push will push the node onto the stack
+func (d *swiftDemangler) push(n *swiftNode) {
+	d.stack = append(d.stack, n)
+}
+
+// popIf will pop the node on the top of the stack when its kind matches,
+// nil is returned when it does not
+func (d *swiftDemangler) popIf(match func(swiftKind) bool) *swiftNode {
+	if len(d.stack) == 0 {
+		return nil
+	}
+
+	n := d.stack[len(d.stack)-1]
+	if !match(n.kind) {
+		return nil
+	}
+
+	d.stack = d.stack[:len(d.stack)-1]
+
+	return n
+}
+
+// popKind will pop the node on the top of the stack when it is the kind
+func (d *swiftDemangler) popKind(kind swiftKind) *swiftNode {
+	return d.popIf(func(k swiftKind) bool {
+		return k == kind
+	})
+}
+
+// pop will pop the node on the top of the stack whatever its kind
+func (d *swiftDemangler) pop() *swiftNode {
+	return d.popIf(func(swiftKind) bool {
+		return true
+	})
+}
+
+// create will create a node with the children, nil is returned when any of
+// them are missing as the operator did not find what it is made of
+func (d *swiftDemangler) create(kind swiftKind, text string, children ...*swiftNode) *swiftNode {
+	for _, c := range children {
+		if c == nil {
+			return nil
+		}
+	}
+
+	return &swiftNode{kind: kind, text: text, children: children}
+}
+
+// typ will wrap the node in a type, nil when it is missing
+func (d *swiftDemangler) typ(n *swiftNode) *swiftNode {
+	return d.create(swiftType, "", n)
+}
+
+// popType will pop a type and return what it wraps
+func (d *swiftDemangler) popType() *swiftNode {
+	if ty := d.popKind(swiftType); ty != nil {
+		return ty.children[0]
+	}
+
+	return nil
+}
+
+// natural will parse a decimal number, false when there is none
+func (d *swiftDemangler) natural() (int, bool) {
+	start := d.pos
+	for d.peek() >= '0' && d.peek() <= '9' {
+		d.pos++
+	}
+
+	n, err := strconv.Atoi(d.sym[start:d.pos])
+	if err != nil || n > len(d.sym)*swiftMaxRepeat {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// index will parse an index, an _ for zero or one less than a number
+// ending in _
+func (d *swiftDemangler) index() (int, bool) {
+	if d.nextIf('_') {
+		return 0, true
+	}
+
+	n, ok := d.natural()
+	if !ok || !d.nextIf('_') {
+		return 0, false
+	}
+
+	return n + 1, true
+}
+
+// indexNode will parse an index into a node
+func (d *swiftDemangler) indexNode() *swiftNode {
+	n, ok := d.index()
+	if !ok {
+		return nil
+	}
+
+	return &swiftNode{kind: swiftIndex, index: n}
+}
+
+// operator will demangle the next operator, nil is returned when it is
+// malformed or is not one that is demangled
+func (d *swiftDemangler) operator() *swiftNode {
+	c := d.next()
+
+	switch {
+	case c >= '0' && c <= '9':
+		d.pos--
+		return d.identifier()
+	case c >= 0x01 && c <= 0x17:
+		return d.symbolicReference(c)
+	}
+
+	switch c {
+	case 'A':
+		return d.multiSubstitution()
+	case 'B':
+		return d.builtin()
+	case 'C':
+		return d.nominal(swiftClass)
+	case 'D':
+		return d.create(swiftType, "", d.popType())
+	case 'E':
+		return d.extension()
+	case 'F':
+		return d.plainFunction()
+	case 'G':
+		return d.boundGeneric()
+	case 'K':
+		return &swiftNode{kind: swiftThrows}
+	case 'L':
+		return d.localIdentifier()
+	case 'M':
+		return d.metadata()
+	case 'N':
+		return d.create(swiftDescribed, "type metadata for ", d.popKind(swiftType))
+	case 'O':
+		return d.nominal(swiftEnum)
+	case 'P':
+		return d.nominal(swiftProtocol)
+	case 'Q':
+		return d.archetype()
+	case 'R':
+		return d.requirement()
+	case 'S':
+		return d.standardSubstitution()
+	case 'T':
+		return d.thunk()
+	case 'V':
+		return d.nominal(swiftStructure)
+	case 'W':
+		return d.witness()
+	case 'X':
+		return d.specialType()
+	case 'Y':
+		return d.typeAnnotation()
+	case 'Z':
+		return d.create(swiftStatic, "", d.popIf(swiftIsEntity))
+	case 'a':
+		return d.nominal(swiftTypeAlias)
+	case 'c':
+		return d.functionType()
+	case 'd':
+		return &swiftNode{kind: swiftVariadic}
+	case 'f':
+		return d.functionEntity()
+	case 'h':
+		return d.typ(d.create(swiftDescribed, "__shared ", d.popType()))
+	case 'i':
+		return d.subscript()
+	case 'l':
+		return d.genericSignature(false)
+	case 'm':
+		return d.typ(d.create(swiftMetatype, "", d.popKind(swiftType)))
+	case 'n':
+		return d.typ(d.create(swiftDescribed, "__owned ", d.popType()))
+	case 'o':
+		return d.operatorIdentifier()
+	case 'p':
+		return d.protocolList()
+	case 'q':
+		return d.typ(d.genericParamIndex())
+	case 'r':
+		return d.genericSignature(true)
+	case 's':
+		return &swiftNode{kind: swiftModule, text: "Swift"}
+	case 't':
+		return d.tuple()
+	case 'u':
+		sig := d.popKind(swiftGenericSignature)
+		return d.typ(d.create(swiftGenericType, "", sig, d.popKind(swiftType)))
+	case 'v':
+		return d.accessor(d.entity(swiftVariable))
+	case 'x':
+		return d.typ(&swiftNode{kind: swiftGenericParam})
+	case 'y':
+		return &swiftNode{kind: swiftEmptyList}
+	case 'z':
+		return d.typ(d.create(swiftDescribed, "inout ", d.popType()))
+	case '_':
+		return &swiftNode{kind: swiftFirstElement}
+	}
+
+	return nil
+}
+
+// symbolicReference will resolve the reference to a context descriptor,
+// which the mangled names of the reflection metadata hold as its kind and
+// then its 32 bit relative offset. A type referenced by it is substituted
+// just as one which is spelled out is
+func (d *swiftDemangler) symbolicReference(kind byte) *swiftNode {
+	if d.resolve == nil || d.pos+4 > len(d.sym) {
+		return nil
+	}
+
+	pos := d.pos
+	d.pos += 4
+
+	n := d.resolve(kind, pos)
+	if n != nil && n.kind == swiftType {
+		d.substs = append(d.substs, n)
+	}
+
+	return n
+}
+
+// identifier will parse an identifier, its length and then its characters.
+// After a 0 the words of the earlier identifiers are substituted by their
+// letters, and after 00 it is Punycode
+func (d *swiftDemangler) identifier() *swiftNode {
+	words, puny := false, false
+	if d.nextIf('0') {
+		if d.nextIf('0') {
+			puny = true
+		} else {
+			words = true
+		}
+	}
+
+	var ident strings.Builder
+	for {
+		for words && (d.peek() >= 'a' && d.peek() <= 'z' || d.peek() >= 'A' && d.peek() <= 'Z') {
+			c := d.next()
+
+			var idx int
+			if c >= 'a' && c <= 'z' {
+				idx = int(c - 'a')
+			} else {
+				idx = int(c - 'A')
+				words = false
+			}
+
+			if idx >= len(d.words) {
+				return nil
+			}
+
+			ident.WriteString(d.words[idx])
+		}
+
+		if d.nextIf('0') {
+			break
+		}
+
+		n, ok := d.natural()
+		if !ok || n == 0 {
+			return nil
+		}
+
+		if puny {
+			d.nextIf('_')
+		}
+
+		if d.pos+n > len(d.sym) {
+			return nil
+		}
+
+		slice := d.sym[d.pos : d.pos+n]
+		d.pos += n
+
+		if puny {
+			decoded, err := utilSwiftPunycode(slice)
+			if err != nil {
+				return nil
+			}
+
+			ident.WriteString(decoded)
+		} else {
+			ident.WriteString(slice)
+			d.addWords(slice)
+		}
+
+		if !words {
+			break
+		}
+	}
+
+	if ident.Len() == 0 {
+		return nil
+	}
+
+	n := &swiftNode{kind: swiftIdentifier, text: ident.String()}
+	d.substs = append(d.substs, n)
+
+	return n
+}
+
+// addWords will add the words of the identifier to those which can be
+// substituted, a word starts at a letter and ends at an underscore or a
+// change to uppercase. Only the words of two or more characters are added
+func (d *swiftDemangler) addWords(slice string) {
+	start := -1
+	for i := 0; i <= len(slice); i++ {
+		c := byte(0)
+		if i < len(slice) {
+			c = slice[i]
+		}
+
+		if start >= 0 && (c == '_' || c == 0 || !(slice[i-1] >= 'A' && slice[i-1] <= 'Z') && c >= 'A' && c <= 'Z') {
+			if i-start >= 2 && len(d.words) < swiftMaxWords {
+				d.words = append(d.words, slice[start:i])
+			}
+
+			start = -1
+		}
+
+		if start < 0 && c != 0 && c != '_' && !(c >= '0' && c <= '9') {
+			start = i
+		}
+	}
+}
+
+// utilSwiftPunycode will decode the Punycode of Swift, which uses A to J
+// for the digits that are 0 to 9 in that of Rust
+func utilSwiftPunycode(name string) (string, error) {
+	basic, encoded := "", name
+	if i := strings.LastIndexByte(name, '_'); i >= 0 {
+		basic, encoded = name[:i+1], name[i+1:]
+	}
+
+	digits := []byte(encoded)
+	for i, c := range digits {
+		if c >= 'A' && c <= 'J' {
+			digits[i] = c - 'A' + '0'
+		} else if c >= '0' && c <= '9' {
+			return "", errSwiftSymbol
+		}
+	}
+
+	return utilPunycode(basic + string(digits))
+}
+
+// multiSubstitution will push the earlier nodes that are substituted after
+// an A, a lowercase letter for each but the last which is uppercase, each
+// of them repeated by a count before it
+func (d *swiftDemangler) multiSubstitution() *swiftNode {
+	repeat := -1
+
+	for {
+		c := d.next()
+
+		switch {
+		case c == 0:
+			return nil
+		case c >= 'a' && c <= 'z':
+			n := d.pushSubstitution(repeat, int(c-'a'))
+			if n == nil {
+				return nil
+			}
+
+			d.push(n)
+			repeat = -1
+		case c >= 'A' && c <= 'Z':
+			return d.pushSubstitution(repeat, int(c-'A'))
+		case c == '_':
+			idx := repeat + 27
+			if idx < 0 || idx >= len(d.substs) {
+				return nil
+			}
+
+			return d.substs[idx]
+		default:
+			d.pos--
+
+			n, ok := d.natural()
+			if !ok || n > swiftMaxRepeat {
+				return nil
+			}
+
+			repeat = n
+		}
+	}
+}
+
+// pushSubstitution will push the substitution one less than repeat times,
+// and return it for the last
+func (d *swiftDemangler) pushSubstitution(repeat int, idx int) *swiftNode {
+	if idx >= len(d.substs) {
+		return nil
+	}
+
+	n := d.substs[idx]
+	for ; repeat > 1; repeat-- {
+		d.push(n)
+	}
+
+	return n
+}
+
+// standardSubstitution will demangle the types of the standard library
+// after an S, which may be repeated by a count
+func (d *swiftDemangler) standardSubstitution() *swiftNode {
+	switch d.peek() {
+	case 'o':
+		d.pos++
+		return &swiftNode{kind: swiftModule, text: "__C"}
+	case 'C':
+		d.pos++
+		return &swiftNode{kind: swiftModule, text: "__C_Synthesized"}
+	case 'g':
+		d.pos++
+
+		optional := d.swiftType(swiftNode{kind: swiftEnum, text: "Optional"})
+		n := d.typ(d.create(swiftBoundGeneric, "", optional, d.create(swiftTypeList, "", d.popKind(swiftType))))
+		if n != nil {
+			d.substs = append(d.substs, n)
+		}
+
+		return n
+	}
+
+	repeat := 1
+	if d.peek() >= '0' && d.peek() <= '9' {
+		n, ok := d.natural()
+		if !ok || n > swiftMaxRepeat {
+			return nil
+		}
+
+		repeat = n
+	}
+
+	table := swiftStandardTypes
+	if d.nextIf('c') {
+		table = swiftConcurrencyTypes
+	}
+
+	std, ok := table[d.next()]
+	if !ok {
+		return nil
+	}
+
+	n := d.swiftType(std)
+	for ; repeat > 1; repeat-- {
+		d.push(n)
+	}
+
+	return n
+}
+
+// swiftType will create the type of the standard library that std names
+func (d *swiftDemangler) swiftType(std swiftNode) *swiftNode {
+	module := &swiftNode{kind: swiftModule, text: "Swift"}
+	name := &swiftNode{kind: swiftIdentifier, text: std.text}
+
+	return d.typ(d.create(std.kind, "", module, name))
+}
+
+// builtin will demangle the builtin types after a B
+func (d *swiftDemangler) builtin() *swiftNode {
+	var name string
+
+	switch c := d.next(); c {
+	case 'b':
+		name = "Builtin.BridgeObject"
+	case 'B':
+		name = "Builtin.UnsafeValueBuffer"
+	case 'e':
+		name = "Builtin.Executor"
+	case 'c':
+		name = "Builtin.RawUnsafeContinuation"
+	case 'D':
+		name = "Builtin.DefaultActorStorage"
+	case 'j':
+		name = "Builtin.Job"
+	case 'I':
+		name = "Builtin.IntLiteral"
+	case 'O':
+		name = "Builtin.UnknownObject"
+	case 'o':
+		name = "Builtin.NativeObject"
+	case 'p':
+		name = "Builtin.RawPointer"
+	case 't':
+		name = "Builtin.SILToken"
+	case 'w':
+		name = "Builtin.Word"
+	case 'f', 'i':
+		size, ok := d.index()
+		if !ok || size < 1 {
+			return nil
+		}
+
+		name = fmt.Sprintf("Builtin.Int%d", size-1)
+		if c == 'f' {
+			name = fmt.Sprintf("Builtin.FPIEEE%d", size-1)
+		}
+	case 'v':
+		elts, ok := d.index()
+		elt := d.popType()
+		if !ok || elts < 1 || elt == nil || elt.kind != swiftBuiltin {
+			return nil
+		}
+
+		name = fmt.Sprintf("Builtin.Vec%dx%s", elts-1, strings.TrimPrefix(elt.text, "Builtin."))
+	default:
+		return nil
+	}
+
+	return d.typ(&swiftNode{kind: swiftBuiltin, text: name})
+}
+
+// popModule will pop the module of a context, an identifier is taken to
+// name one
+func (d *swiftDemangler) popModule() *swiftNode {
+	if ident := d.popKind(swiftIdentifier); ident != nil {
+		return &swiftNode{kind: swiftModule, text: ident.text}
+	}
+
+	return d.popKind(swiftModule)
+}
+
+// popContext will pop the context that a name is declared in
+func (d *swiftDemangler) popContext() *swiftNode {
+	if module := d.popModule(); module != nil {
+		return module
+	}
+
+	if ty := d.popKind(swiftType); ty != nil {
+		if !swiftIsContext(ty.children[0].kind) {
+			return nil
+		}
+
+		return ty.children[0]
+	}
+
+	return d.popIf(swiftIsContext)
+}
+
+// popNominal will pop a type which is a nominal type, and return it
+func (d *swiftDemangler) popNominal() *swiftNode {
+	ty := d.popType()
+	if ty == nil || !swiftIsNominal(ty.kind) {
+		return nil
+	}
+
+	return ty
+}
+
+// nominal will demangle a class, struct, enum, protocol or type alias from
+// its context and name
+func (d *swiftDemangler) nominal(kind swiftKind) *swiftNode {
+	name := d.popIf(swiftIsDeclName)
+	ctx := d.popContext()
+
+	n := d.typ(d.create(kind, "", ctx, name))
+	if n != nil {
+		d.substs = append(d.substs, n)
+	}
+
+	return n
+}
+
+// extension will demangle an extension, the module it is in and the type
+// that it extends
+func (d *swiftDemangler) extension() *swiftNode {
+	sig := d.popKind(swiftGenericSignature)
+	module := d.popModule()
+
+	n := d.create(swiftExtension, "", module, d.popNominal())
+	if n != nil && sig != nil {
+		n.children = append(n.children, sig)
+	}
+
+	return n
+}
+
+// boundGeneric will demangle a generic type with the arguments it is bound
+// to, which are in lists for the type and for each of the generic types
+// it is nested in, the outermost first
+func (d *swiftDemangler) boundGeneric() *swiftNode {
+	var lists []*swiftNode
+
+	for {
+		list := &swiftNode{kind: swiftTypeList}
+		for {
+			ty := d.popKind(swiftType)
+			if ty == nil {
+				break
+			}
+
+			list.children = append([]*swiftNode{ty}, list.children...)
+		}
+
+		lists = append(lists, list)
+
+		if d.popKind(swiftEmptyList) != nil {
+			break
+		}
+
+		if d.popKind(swiftFirstElement) == nil {
+			return nil
+		}
+	}
+
+	nominal := d.popNominal()
+	if nominal == nil {
+		return nil
+	}
+
+	n := d.typ(d.boundArgs(nominal, lists, 0))
+	if n != nil {
+		d.substs = append(d.substs, n)
+	}
+
+	return n
+}
+
+// boundArgs will bind the nominal type to the list of arguments at idx,
+// and its context to those after it
+func (d *swiftDemangler) boundArgs(nominal *swiftNode, lists []*swiftNode, idx int) *swiftNode {
+	if idx >= len(lists) || len(nominal.children) == 0 {
+		return nil
+	}
+
+	args := lists[idx]
+	idx++
+
+	if idx < len(lists) {
+		ctx := nominal.children[0]
+
+		var parent *swiftNode
+		if ctx.kind == swiftExtension {
+			parent = d.create(swiftExtension, "", ctx.children[0], d.boundArgs(ctx.children[1], lists, idx))
+		} else {
+			parent = d.boundArgs(ctx, lists, idx)
+		}
+
+		if parent == nil {
+			return nil
+		}
+
+		rebuilt := &swiftNode{kind: nominal.kind, children: []*swiftNode{parent}}
+		rebuilt.children = append(rebuilt.children, nominal.children[1:]...)
+		nominal = rebuilt
+	}
+
+	if len(args.children) == 0 {
+		return nominal
+	}
+
+	return d.create(swiftBoundGeneric, "", d.typ(nominal), args)
+}
+
+// localIdentifier will demangle the names that are private to their file
+// after LL, and those that are local to their function
+func (d *swiftDemangler) localIdentifier() *swiftNode {
+	if d.nextIf('L') {
+		discriminator := d.popKind(swiftIdentifier)
+		return d.create(swiftPrivateName, "", discriminator, d.popIf(swiftIsDeclName))
+	}
+
+	if d.nextIf('l') {
+		return d.create(swiftPrivateName, "", d.popKind(swiftIdentifier))
+	}
+
+	discriminator := d.indexNode()
+
+	return d.create(swiftLocalName, "", discriminator, d.popIf(swiftIsDeclName))
+}
+
+// operatorIdentifier will demangle the name of an operator, whose
+// characters were mangled as the letters of an identifier
+func (d *swiftDemangler) operatorIdentifier() *swiftNode {
+	ident := d.popKind(swiftIdentifier)
+	if ident == nil {
+		return nil
+	}
+
+	var op strings.Builder
+	for i := 0; i < len(ident.text); i++ {
+		c := ident.text[i]
+		if c >= 0x80 {
+			op.WriteByte(c)
+			continue
+		}
+
+		if c < 'a' || c > 'z' || swiftOperatorChars[c-'a'] == ' ' {
+			return nil
+		}
+
+		op.WriteByte(swiftOperatorChars[c-'a'])
+	}
+
+	fixity := map[byte]string{'i': " infix", 'p': " prefix", 'P': " postfix"}[d.next()]
+	if fixity == "" {
+		return nil
+	}
+
+	return &swiftNode{kind: swiftOperatorName, text: op.String() + fixity}
+}
+
+// tuple will demangle a tuple, its elements each with an optional label
+// up to the marker after the first of them
+func (d *swiftDemangler) tuple() *swiftNode {
+	tuple := &swiftNode{kind: swiftTuple}
+
+	if d.popKind(swiftEmptyList) == nil {
+		for {
+			first := d.popKind(swiftFirstElement) != nil
+			elem := &swiftNode{kind: swiftTupleElement}
+
+			if variadic := d.popKind(swiftVariadic); variadic != nil {
+				elem.children = append(elem.children, variadic)
+			}
+
+			if ident := d.popKind(swiftIdentifier); ident != nil {
+				elem.children = append(elem.children, &swiftNode{kind: swiftTupleLabel, text: ident.text})
+			}
+
+			ty := d.popKind(swiftType)
+			if ty == nil {
+				return nil
+			}
+
+			elem.children = append(elem.children, ty)
+			tuple.children = append([]*swiftNode{elem}, tuple.children...)
+
+			if first {
+				break
+			}
+		}
+	}
+
+	return d.typ(tuple)
+}
+
+// protocolList will demangle the protocols that an existential conforms
+// to, which is Any when there are none
+func (d *swiftDemangler) protocolList() *swiftNode {
+	list := &swiftNode{kind: swiftTypeList}
+
+	if d.popKind(swiftEmptyList) == nil {
+		for {
+			first := d.popKind(swiftFirstElement) != nil
+
+			proto := d.popProtocol()
+			if proto == nil {
+				return nil
+			}
+
+			list.children = append([]*swiftNode{proto}, list.children...)
+
+			if first {
+				break
+			}
+		}
+	}
+
+	return d.typ(d.create(swiftProtocolList, "", list))
+}
+
+// popProtocol will pop a protocol, either as a type or as its context and
+// name
+func (d *swiftDemangler) popProtocol() *swiftNode {
+	if ty := d.popKind(swiftType); ty != nil {
+		if ty.children[0].kind != swiftProtocol {
+			return nil
+		}
+
+		return ty
+	}
+
+	name := d.popIf(swiftIsDeclName)
+	ctx := d.popContext()
+
+	return d.typ(d.create(swiftProtocol, "", ctx, name))
+}
+
+// popConformance will pop the conformance of a type to a protocol, in the
+// module which declares it
+func (d *swiftDemangler) popConformance() *swiftNode {
+	sig := d.popKind(swiftGenericSignature)
+	module := d.popModule()
+	proto := d.popProtocol()
+
+	ty := d.popKind(swiftType)
+	if ty == nil {
+		d.popKind(swiftIdentifier)
+		ty = d.popKind(swiftType)
+	}
+
+	if sig != nil {
+		ty = d.typ(d.create(swiftGenericType, "", sig, ty))
+	}
+
+	return d.create(swiftConformance, "", ty, proto, module)
+}
+
+// functionType will demangle the type of a closure or a function value
+func (d *swiftDemangler) functionType() *swiftNode {
+	fn := &swiftNode{kind: swiftFunctionType}
+
+	for {
+		n := d.popIf(func(k swiftKind) bool {
+			return k == swiftSendable || k == swiftThrows || k == swiftAsync
+		})
+
+		if n == nil {
+			break
+		}
+
+		fn.children = append(fn.children, n)
+	}
+
+	args := d.functionParams(swiftArgumentTuple)
+	ret := d.functionParams(swiftReturnType)
+	if args == nil || ret == nil {
+		return nil
+	}
+
+	fn.children = append(fn.children, args, ret)
+
+	return d.typ(fn)
+}
+
+// functionParams will pop the parameters or the results of a function,
+// which are a tuple unless there is one of them
+func (d *swiftDemangler) functionParams(kind swiftKind) *swiftNode {
+	if d.popKind(swiftEmptyList) != nil {
+		return &swiftNode{kind: kind, children: []*swiftNode{{kind: swiftType, children: []*swiftNode{{kind: swiftTuple}}}}}
+	}
+
+	return d.create(kind, "", d.popKind(swiftType))
+}
+
+// popLabels will pop the labels of the parameters of the function type,
+// a y when none of them have labels or else one for each. nil is returned
+// when there are none
+func (d *swiftDemangler) popLabels(ty *swiftNode) *swiftNode {
+	if d.popKind(swiftEmptyList) != nil {
+		return &swiftNode{kind: swiftLabelList}
+	}
+
+	fn := swiftFunctionOf(ty)
+	if fn == nil {
+		return nil
+	}
+
+	params := 1
+	if args := swiftArgsOf(fn); args.kind == swiftTuple {
+		params = len(args.children)
+	}
+
+	if params == 0 {
+		return nil
+	}
+
+	labels := &swiftNode{kind: swiftLabelList}
+	named := false
+
+	for i := 0; i < params; i++ {
+		label := d.popIf(func(k swiftKind) bool {
+			return k == swiftIdentifier || k == swiftFirstElement
+		})
+
+		if label == nil {
+			return nil
+		}
+
+		labels.children = append([]*swiftNode{label}, labels.children...)
+		named = named || label.kind == swiftIdentifier
+	}
+
+	if !named {
+		return &swiftNode{kind: swiftLabelList}
+	}
+
+	return labels
+}
+
+// plainFunction will demangle a function, its context, name, the labels
+// of its parameters and its type
+func (d *swiftDemangler) plainFunction() *swiftNode {
+	sig := d.popKind(swiftGenericSignature)
+	ty := d.functionType()
+	labels := d.popLabels(ty)
+
+	if sig != nil {
+		ty = d.typ(d.create(swiftGenericType, "", sig, ty))
+	}
+
+	name := d.popIf(swiftIsDeclName)
+	ctx := d.popContext()
+
+	return d.withLabels(d.create(swiftFunction, "", ctx, name), labels, ty)
+}
+
+// withLabels will add the labels when there are any and then the type to
+// the node, nil is returned when the node or the type are missing
+func (d *swiftDemangler) withLabels(n *swiftNode, labels *swiftNode, ty *swiftNode) *swiftNode {
+	if n == nil || ty == nil {
+		return nil
+	}
+
+	if labels != nil {
+		n.children = append(n.children, labels)
+	}
+
+	n.children = append(n.children, ty)
+
+	return n
+}
+
+// entity will demangle a variable or the like, its context, name and type
+func (d *swiftDemangler) entity(kind swiftKind) *swiftNode {
+	ty := d.popKind(swiftType)
+	labels := d.popLabels(ty)
+	name := d.popIf(swiftIsDeclName)
+	ctx := d.popContext()
+
+	return d.withLabels(d.create(kind, "", ctx, name), labels, ty)
+}
+
+// subscript will demangle a subscript and its accessor
+func (d *swiftDemangler) subscript() *swiftNode {
+	d.popKind(swiftPrivateName)
+
+	ty := d.popKind(swiftType)
+	labels := d.popLabels(ty)
+
+	return d.accessor(d.withLabels(d.create(swiftSubscript, "", d.popContext()), labels, ty))
+}
+
+// accessor will demangle the accessor of the variable or subscript, p is
+// the storage itself
+func (d *swiftDemangler) accessor(storage *swiftNode) *swiftNode {
+	if storage == nil {
+		return nil
+	}
+
+	c := d.next()
+	if c == 'p' {
+		return storage
+	}
+
+	if c == 'a' || c == 'l' {
+		kind := map[byte]string{'O': "owning", 'o': "nativeOwning", 'P': "nativePinning", 'u': "unsafe"}[d.next()]
+		if kind == "" {
+			return nil
+		}
+
+		name := kind + "Addressor"
+		if c == 'a' {
+			name = kind + "MutableAddressor"
+		}
+
+		return &swiftNode{kind: swiftAccessor, text: name, children: []*swiftNode{storage}}
+	}
+
+	name, ok := swiftAccessors[c]
+	if !ok {
+		return nil
+	}
+
+	return &swiftNode{kind: swiftAccessor, text: name, children: []*swiftNode{storage}}
+}
+
+// functionEntity will demangle the initializers, deinitializers, closures
+// and the other entities after an f
+func (d *swiftDemangler) functionEntity() *swiftNode {
+	switch c := d.next(); c {
+	case 'D', 'd', 'i':
+		kind := map[byte]swiftKind{'D': swiftDeallocator, 'd': swiftDestructor, 'i': swiftInitializer}[c]
+		return d.create(kind, "", d.popContext())
+	case 'C', 'c':
+		d.popKind(swiftPrivateName)
+
+		ty := d.popKind(swiftType)
+		labels := d.popLabels(ty)
+
+		kind := swiftConstructor
+		if c == 'C' {
+			kind = swiftAllocator
+		}
+
+		return d.withLabels(d.create(kind, "", d.popContext()), labels, ty)
+	case 'U', 'u':
+		idx := d.indexNode()
+		ty := d.popKind(swiftType)
+
+		kind := swiftExplicitClosure
+		if c == 'u' {
+			kind = swiftImplicitClosure
+		}
+
+		return d.create(kind, "", d.popContext(), idx, ty)
+	case 'A':
+		idx := d.indexNode()
+		return d.create(swiftDefaultArgument, "", d.popContext(), idx)
+	}
+
+	return nil
+}
+
+// metadata will demangle the metadata of a type after an M
+func (d *swiftDemangler) metadata() *swiftNode {
+	c := d.next()
+
+	if text, ok := swiftMetadata[c]; ok {
+		return d.create(swiftDescribed, text, d.popKind(swiftType))
+	}
+
+	if text, ok := swiftConformances["M"+string(c)]; ok {
+		return d.create(swiftDescribed, text, d.popConformance())
+	}
+
+	switch c {
+	case 'p':
+		return d.create(swiftDescribed, "protocol descriptor for ", d.popProtocol())
+	case 'S':
+		return d.create(swiftDescribed, "protocol self-conformance descriptor for ", d.popProtocol())
+	case 'V':
+		return d.create(swiftDescribed, "property descriptor for ", d.popIf(swiftIsEntity))
+	case 'Q':
+		return d.create(swiftDescribed, "opaque type descriptor for ", d.pop())
+	case 'C':
+		return d.create(swiftDescribed, "reflection metadata superclass descriptor ", d.popNominal())
+	}
+
+	return nil
+}
+
+// witness will demangle the witness tables and the others after a W
+func (d *swiftDemangler) witness() *swiftNode {
+	c := d.next()
+
+	if text, ok := swiftConformances["W"+string(c)]; ok {
+		return d.create(swiftDescribed, text, d.popConformance())
+	}
+
+	switch c {
+	case 'V':
+		return d.create(swiftDescribed, "value witness table for ", d.popKind(swiftType))
+	case 'S':
+		return d.create(swiftDescribed, "protocol self-conformance witness table for ", d.popProtocol())
+	case 'v':
+		text := map[byte]string{'d': "direct field offset for ", 'i': "indirect field offset for "}[d.next()]
+		if text == "" {
+			return nil
+		}
+
+		return d.create(swiftDescribed, text, d.popIf(swiftIsEntity))
+	case 'l', 'L':
+		conf := d.popConformance()
+		ty := d.popKind(swiftType)
+
+		text := "lazy protocol witness table accessor for type %s and conformance %s"
+		if c == 'L' {
+			text = "lazy protocol witness table cache variable for type %s and conformance %s"
+		}
+
+		return d.create(swiftPair, text, ty, conf)
+	case 't':
+		name := d.popIf(swiftIsDeclName)
+		return d.create(swiftPair, "associated type metadata accessor for %s in %s", name, d.popConformance())
+	}
+
+	return nil
+}
+
+// thunk will demangle the thunks and the attributes of functions after a T
+func (d *swiftDemangler) thunk() *swiftNode {
+	c := d.next()
+
+	if text, ok := swiftThunks[c]; ok {
+		return d.create(swiftDescribed, text, d.popIf(swiftIsEntity))
+	}
+
+	if text, ok := swiftAttributes[c]; ok {
+		return &swiftNode{kind: swiftAttribute, text: text}
+	}
+
+	switch c {
+	case 'W':
+		entity := d.popIf(swiftIsEntity)
+		return d.create(swiftPair, "protocol witness for %s in conformance %s", entity, d.popConformance())
+	case 'V':
+		base := d.popIf(swiftIsEntity)
+		return d.create(swiftPair, "vtable thunk for %s dispatching to %s", base, d.popIf(swiftIsEntity))
+	}
+
+	return nil
+}
+
+// specialType will demangle the types after an X which are not written as
+// plainly as the others
+func (d *swiftDemangler) specialType() *swiftNode {
+	switch d.next() {
+	case 'E':
+		return d.functionType()
+	case 'p', 'M':
+		return d.typ(d.create(swiftMetatype, "", d.popKind(swiftType)))
+	case 'w':
+		return d.typ(d.create(swiftDescribed, "weak ", d.popKind(swiftType)))
+	case 'o':
+		return d.typ(d.create(swiftDescribed, "unowned ", d.popKind(swiftType)))
+	case 'u':
+		return d.typ(d.create(swiftDescribed, "unowned(unsafe) ", d.popKind(swiftType)))
+	}
+
+	return nil
+}
+
+// typeAnnotation will demangle the annotations of function types after a Y
+func (d *swiftDemangler) typeAnnotation() *swiftNode {
+	switch d.next() {
+	case 'a':
+		return &swiftNode{kind: swiftAsync}
+	case 'b':
+		return &swiftNode{kind: swiftSendable}
+	}
+
+	return nil
+}
+
+// genericParamIndex will demangle the depth and index of a generic
+// parameter
+func (d *swiftDemangler) genericParamIndex() *swiftNode {
+	if d.nextIf('d') {
+		depth, ok := d.index()
+		idx, ok2 := d.index()
+		if !ok || !ok2 {
+			return nil
+		}
+
+		return &swiftNode{kind: swiftGenericParam, depth: depth + 1, index: idx}
+	}
+
+	if d.nextIf('z') {
+		return &swiftNode{kind: swiftGenericParam}
+	}
+
+	idx, ok := d.index()
+	if !ok {
+		return nil
+	}
+
+	return &swiftNode{kind: swiftGenericParam, index: idx + 1}
+}
+
+// genericSignature will demangle the generic parameters, a count of them
+// at each depth, and the requirements that they are held to
+func (d *swiftDemangler) genericSignature(counts bool) *swiftNode {
+	sig := &swiftNode{kind: swiftGenericSignature}
+
+	if counts {
+		for !d.nextIf('l') {
+			count := 0
+			if !d.nextIf('z') {
+				idx, ok := d.index()
+				if !ok {
+					return nil
+				}
+
+				count = idx + 1
+			}
+
+			sig.children = append(sig.children, &swiftNode{kind: swiftParamCount, index: count})
+		}
+	} else {
+		sig.children = append(sig.children, &swiftNode{kind: swiftParamCount, index: 1})
+	}
+
+	var reqs []*swiftNode
+	for {
+		req := d.popKind(swiftRequirement)
+		if req == nil {
+			break
+		}
+
+		reqs = append([]*swiftNode{req}, reqs...)
+	}
+
+	sig.children = append(sig.children, reqs...)
+
+	return sig
+}
+
+// requirement will demangle a requirement of a generic signature, which
+// holds a generic parameter or an associated type of it to a protocol,
+// a class, a type or a layout
+func (d *swiftDemangler) requirement() *swiftNode {
+	const (
+		generic = iota
+		assoc
+		compound
+		substitution
+	)
+
+	var kind byte
+	var param int
+
+	switch c := d.next(); c {
+	case 'c', 't', 'm', 'p':
+		kind, param = c, assoc
+	case 'C', 'T', 'M', 'P':
+		kind, param = c+'a'-'A', compound
+	case 'b', 's', 'l':
+		kind, param = c, generic
+	case 'B', 'S', 'L':
+		kind, param = c+'a'-'A', substitution
+	case 'Q':
+		kind, param = 'p', substitution
+	default:
+		d.pos--
+		kind, param = 'p', generic
+	}
+
+	// the kinds of constraint, which are spelled as their generic letters
+	switch kind {
+	case 't':
+		kind = 's'
+	case 'c':
+		kind = 'b'
+	case 'm':
+		kind = 'l'
+	}
+
+	var ty *swiftNode
+	switch param {
+	case generic:
+		ty = d.typ(d.genericParamIndex())
+	case assoc:
+		ty = d.assocType(d.genericParamIndex(), false)
+	case compound:
+		ty = d.assocType(d.genericParamIndex(), true)
+	case substitution:
+		ty = d.popKind(swiftType)
+	}
+
+	if param == assoc || param == compound {
+		if ty == nil {
+			return nil
+		}
+
+		d.substs = append(d.substs, ty)
+	}
+
+	switch kind {
+	case 'p':
+		return d.create(swiftRequirement, ": ", ty, d.popProtocol())
+	case 'b':
+		return d.create(swiftRequirement, ": ", ty, d.popKind(swiftType))
+	case 's':
+		return d.create(swiftRequirement, " == ", ty, d.popKind(swiftType))
+	}
+
+	layout := d.next()
+	name, ok := swiftLayouts[layout]
+
+	switch layout {
+	case 'E', 'e', 'M', 'm':
+		size, ok1 := d.index()
+
+		name = "_Trivial"
+		if layout == 'M' || layout == 'm' {
+			name = "_TrivialAtMost"
+		}
+
+		name += "(" + strconv.Itoa(size)
+		if layout == 'E' || layout == 'M' {
+			align, ok2 := d.index()
+			ok1 = ok1 && ok2
+			name += ", " + strconv.Itoa(align)
+		}
+
+		name, ok = name+")", ok1
+	case 'S':
+		size, ok1 := d.index()
+		name, ok = "_TrivialStride("+strconv.Itoa(size)+")", ok1
+	}
+
+	if !ok {
+		return nil
+	}
+
+	return d.create(swiftRequirement, ": ", ty, &swiftNode{kind: swiftIdentifier, text: name})
+}
+
+// popAssocName will pop the name of an associated type, along with the
+// protocol that declares it when it is there
+func (d *swiftDemangler) popAssocName() *swiftNode {
+	proto := d.popKind(swiftType)
+	if proto != nil && proto.children[0].kind != swiftProtocol {
+		return nil
+	}
+
+	ident := d.popKind(swiftIdentifier)
+	if ident == nil {
+		return nil
+	}
+
+	return &swiftNode{kind: swiftAssocTypeRef, text: ident.text}
+}
+
+// assocType will demangle an associated type of the base, or a chain of
+// them for a compound one. The base is popped when it is nil
+func (d *swiftDemangler) assocType(base *swiftNode, compound bool) *swiftNode {
+	var names []*swiftNode
+
+	for {
+		first := !compound || d.popKind(swiftFirstElement) != nil
+
+		name := d.popAssocName()
+		if name == nil {
+			return nil
+		}
+
+		names = append(names, name)
+
+		if first {
+			break
+		}
+	}
+
+	ty := d.typ(base)
+	if base == nil {
+		ty = d.popKind(swiftType)
+	}
+
+	for i := len(names) - 1; i >= 0; i-- {
+		ty = d.typ(d.create(swiftMemberType, "", ty, names[i]))
+	}
+
+	return ty
+}
+
+// archetype will demangle the associated types of the generic parameters
+// after a Q
+func (d *swiftDemangler) archetype() *swiftNode {
+	var ty *swiftNode
+
+	switch d.next() {
+	case 'y':
+		ty = d.assocType(d.genericParamIndex(), false)
+	case 'z':
+		ty = d.assocType(&swiftNode{kind: swiftGenericParam}, false)
+	case 'Y':
+		ty = d.assocType(d.genericParamIndex(), true)
+	case 'Z':
+		ty = d.assocType(&swiftNode{kind: swiftGenericParam}, true)
+	}
+
+	if ty != nil {
+		d.substs = append(d.substs, ty)
+	}
+
+	return ty
+}
+
+// swiftIsDeclName will check whether the kind is the name of a declaration
+func swiftIsDeclName(kind swiftKind) bool {
+	switch kind {
+	case swiftIdentifier, swiftLocalName, swiftPrivateName, swiftOperatorName:
+		return true
+	}
+
+	return false
+}
+
+// swiftIsNominal will check whether the kind is a nominal type
+func swiftIsNominal(kind swiftKind) bool {
+	switch kind {
+	case swiftClass, swiftStructure, swiftEnum, swiftProtocol, swiftTypeAlias:
+		return true
+	}
+
+	return false
+}
+
+// swiftIsContext will check whether the kind can be the context that
+// other declarations are nested in
+func swiftIsContext(kind swiftKind) bool {
+	switch kind {
+	case swiftModule, swiftExtension, swiftFunction, swiftVariable, swiftSubscript, swiftAccessor, swiftStatic,
+		swiftAllocator, swiftConstructor, swiftDestructor, swiftDeallocator, swiftExplicitClosure,
+		swiftImplicitClosure, swiftDefaultArgument, swiftInitializer:
+		return true
+	}
+
+	return swiftIsNominal(kind)
+}
+
+// swiftIsEntity will check whether the kind is an entity, a context or
+// a type
+func swiftIsEntity(kind swiftKind) bool {
+	return kind == swiftType || swiftIsContext(kind)
+}
+
+// swiftFunctionOf will return the function type that the type is, which
+// may be generic, nil when it is not one
+func swiftFunctionOf(ty *swiftNode) *swiftNode {
+	if ty == nil || ty.kind != swiftType {
+		return nil
+	}
+
+	fn := ty.children[0]
+	if fn.kind == swiftGenericType {
+		fn = fn.children[1].children[0]
+	}
+
+	if fn.kind != swiftFunctionType {
+		return nil
+	}
+
+	return fn
+}
+
+// swiftChildOf will return the first child of the node of the kind
+func swiftChildOf(n *swiftNode, kind swiftKind) *swiftNode {
+	for _, c := range n.children {
+		if c.kind == kind {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// swiftArgsOf will return the type of the parameters of the function type,
+// unwrapped
+func swiftArgsOf(fn *swiftNode) *swiftNode {
+	return swiftChildOf(fn, swiftArgumentTuple).children[0].children[0]
+}
+
+// swiftParamName will return the name of a generic parameter, the letters
+// for its index followed by its depth when it is nested
+func swiftParamName(depth, index int) string {
+	var name []byte
+
+	for {
+		name = append(name, byte('A'+index%26))
+		index /= 26
+
+		if index == 0 {
+			break
+		}
+	}
+
+	if depth != 0 {
+		name = append(name, strconv.Itoa(depth)...)
+	}
+
+	return string(name)
+}
+
+// swiftPrinter will print the nodes of a demangled Swift symbol
+type swiftPrinter struct {
+	opts   *DemangleOptions
+	depth  int
+	failed bool
+}
+
+// print will print the node, failing when the text nests too deep or grows
+// too long
+func (p *swiftPrinter) print(n *swiftNode) string {
+	p.depth++
+	defer func() { p.depth-- }()
+
+	if p.failed || p.depth > swiftMaxDepth {
+		p.failed = true
+		return ""
+	}
+
+	text := p.node(n)
+	if len(text) > swiftMaxLength {
+		p.failed = true
+		return ""
+	}
+
+	return text
+}
+
+// join will print each of the nodes and join them with sep
+func (p *swiftPrinter) join(nodes []*swiftNode, sep string) string {
+	var parts []string
+	for _, n := range nodes {
+		parts = append(parts, p.print(n))
+	}
+
+	return strings.Join(parts, sep)
+}
+
+// node will print the node by its kind
+func (p *swiftPrinter) node(n *swiftNode) string {
+	c := n.children
+
+	switch n.kind {
+	case swiftGlobal:
+		var text string
+		for _, attr := range c[:len(c)-1] {
+			text += attr.text
+		}
+
+		return text + p.print(c[len(c)-1])
+	case swiftType, swiftReturnType:
+		return p.print(c[0])
+	case swiftIdentifier, swiftModule, swiftOperatorName, swiftBuiltin, swiftAssocTypeRef:
+		return n.text
+	case swiftLocalName:
+		return p.print(c[1]) + " #" + strconv.Itoa(c[0].index+1)
+	case swiftPrivateName:
+		if len(c) < 2 {
+			return ""
+		}
+
+		return "(" + p.print(c[1]) + " in " + c[0].text + ")"
+	case swiftClass, swiftStructure, swiftEnum, swiftProtocol, swiftTypeAlias:
+		return p.qualify(c[0], p.print(c[1]))
+	case swiftExtension:
+		return "(extension in " + c[0].text + "):" + p.print(c[1])
+	case swiftBoundGeneric:
+		return p.boundGeneric(n)
+	case swiftTuple:
+		return "(" + p.join(c, ", ") + ")"
+	case swiftTupleElement:
+		var text string
+		if label := swiftChildOf(n, swiftTupleLabel); label != nil {
+			text = label.text + ": "
+		}
+
+		text += p.print(swiftChildOf(n, swiftType))
+		if swiftChildOf(n, swiftVariadic) != nil {
+			text += "..."
+		}
+
+		return text
+	case swiftFunctionType:
+		var text string
+		if swiftChildOf(n, swiftSendable) != nil {
+			text = "@Sendable "
+		}
+
+		return text + p.params(n, nil) + p.effects(n) + " -> " + p.print(swiftChildOf(n, swiftReturnType))
+	case swiftMetatype:
+		return p.print(c[0]) + ".Type"
+	case swiftProtocolList:
+		if len(c[0].children) == 0 {
+			return "Any"
+		}
+
+		return p.join(c[0].children, " & ")
+	case swiftGenericSignature:
+		return p.genericSignature(n)
+	case swiftGenericParam:
+		return swiftParamName(n.depth, n.index)
+	case swiftGenericType:
+		return p.print(c[0]) + " " + p.print(c[1])
+	case swiftMemberType:
+		return p.print(c[0]) + "." + p.print(c[1])
+	case swiftRequirement:
+		return p.print(c[0]) + n.text + p.print(c[1])
+	case swiftConformance:
+		return p.print(c[0]) + " : " + p.print(c[1]) + " in " + c[2].text
+	case swiftDescribed:
+		return n.text + p.print(c[0])
+	case swiftPair:
+		return fmt.Sprintf(n.text, p.print(c[0]), p.print(c[1]))
+	case swiftFunction:
+		return p.function(p.qualify(c[0], p.print(c[1])), c[2:])
+	case swiftVariable:
+		return p.qualify(c[0], p.print(c[1])) + " : " + p.print(c[len(c)-1])
+	case swiftSubscript:
+		return p.function(p.qualify(c[0], "subscript"), c[1:])
+	case swiftAccessor:
+		return p.name(c[0]) + "." + n.text + " : " + p.print(c[0].children[len(c[0].children)-1])
+	case swiftStatic:
+		return "static " + p.print(c[0])
+	case swiftAllocator, swiftConstructor:
+		return p.function(p.name(n), c[1:])
+	case swiftDestructor, swiftDeallocator, swiftInitializer:
+		return p.name(n)
+	case swiftExplicitClosure, swiftImplicitClosure:
+		return p.name(n)
+	case swiftDefaultArgument:
+		return "default argument " + strconv.Itoa(c[1].index) + " of " + p.print(c[0])
+	}
+
+	p.failed = true
+
+	return ""
+}
+
+// qualify will qualify the name with its context, the standard library is
+// left out when it is simplified
+func (p *swiftPrinter) qualify(ctx *swiftNode, name string) string {
+	var text string
+
+	switch {
+	case ctx.kind == swiftModule:
+		if ctx.text == "Swift" && p.opts.SimplifyStd {
+			return name
+		}
+
+		text = ctx.text
+	case swiftIsNominal(ctx.kind) || ctx.kind == swiftExtension:
+		text = p.print(ctx)
+	default:
+		text = p.name(ctx)
+	}
+
+	return text + "." + name
+}
+
+// name will print the name of an entity without its type, which is how
+// it is written as the context of another
+func (p *swiftPrinter) name(n *swiftNode) string {
+	c := n.children
+
+	switch n.kind {
+	case swiftFunction, swiftVariable:
+		return p.qualify(c[0], p.print(c[1]))
+	case swiftSubscript:
+		return p.qualify(c[0], "subscript")
+	case swiftAccessor, swiftStatic:
+		return p.name(c[0])
+	case swiftAllocator:
+		return p.qualify(c[0], "__allocating_init")
+	case swiftConstructor:
+		return p.qualify(c[0], "init")
+	case swiftDestructor:
+		return p.qualify(c[0], "deinit")
+	case swiftDeallocator:
+		return p.qualify(c[0], "__deallocating_deinit")
+	case swiftInitializer:
+		return "variable initialization expression of " + p.name(c[0])
+	case swiftExplicitClosure:
+		return "closure #" + strconv.Itoa(c[1].index+1) + " in " + p.name(c[0])
+	case swiftImplicitClosure:
+		return "implicit closure #" + strconv.Itoa(c[1].index+1) + " in " + p.name(c[0])
+	case swiftDefaultArgument:
+		return "default argument " + strconv.Itoa(c[1].index) + " of " + p.name(c[0])
+	}
+
+	return p.print(n)
+}
+
+// function will print the function with its name, the labels of its
+// parameters when it has them and its type, which are the rest of it
+func (p *swiftPrinter) function(name string, rest []*swiftNode) string {
+	if len(rest) == 0 {
+		p.failed = true
+		return ""
+	}
+
+	var labels *swiftNode
+	if rest[0].kind == swiftLabelList {
+		labels = rest[0]
+	}
+
+	ty := rest[len(rest)-1]
+
+	fn := swiftFunctionOf(ty)
+	if fn == nil {
+		return name + " : " + p.print(ty)
+	}
+
+	if p.opts.NoParams {
+		return name
+	}
+
+	var sig string
+	if generic := ty.children[0]; generic.kind == swiftGenericType && !p.opts.NoTemplateArgs {
+		sig = p.print(generic.children[0])
+	}
+
+	return name + sig + p.params(fn, labels) + p.effects(fn) + " -> " + p.print(swiftChildOf(fn, swiftReturnType))
+}
+
+// params will print the parameters of the function type in parentheses,
+// each with its label when there are labels
+func (p *swiftPrinter) params(fn *swiftNode, labels *swiftNode) string {
+	args := swiftArgsOf(fn)
+
+	elems := []*swiftNode{args}
+	if args.kind == swiftTuple {
+		elems = args.children
+	}
+
+	if labels == nil || len(labels.children) != len(elems) {
+		if args.kind == swiftTuple {
+			return p.print(args)
+		}
+
+		return "(" + p.print(args) + ")"
+	}
+
+	var parts []string
+	for i, elem := range elems {
+		label := "_"
+		if labels.children[i].kind == swiftIdentifier {
+			label = labels.children[i].text
+		}
+
+		parts = append(parts, label+": "+p.print(elem))
+	}
+
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// effects will print whether the function type is async and throws
+func (p *swiftPrinter) effects(fn *swiftNode) string {
+	var text string
+
+	if swiftChildOf(fn, swiftAsync) != nil {
+		text += " async"
+	}
+
+	if swiftChildOf(fn, swiftThrows) != nil {
+		text += " throws"
+	}
+
+	return text
+}
+
+// boundGeneric will print a generic type with its arguments, the optionals,
+// arrays and dictionaries of the standard library with their sugar
+func (p *swiftPrinter) boundGeneric(n *swiftNode) string {
+	nominal, args := n.children[0], n.children[1].children
+	if p.opts.NoTemplateArgs {
+		return p.print(nominal)
+	}
+
+	switch p.print(nominal) {
+	case "Swift.Optional", "Optional":
+		if len(args) == 1 {
+			if swiftFunctionOf(args[0]) != nil {
+				return "(" + p.print(args[0]) + ")?"
+			}
+
+			return p.print(args[0]) + "?"
+		}
+	case "Swift.Array", "Array":
+		if len(args) == 1 {
+			return "[" + p.print(args[0]) + "]"
+		}
+	case "Swift.Dictionary", "Dictionary":
+		if len(args) == 2 {
+			return "[" + p.print(args[0]) + " : " + p.print(args[1]) + "]"
+		}
+	}
+
+	return p.print(nominal) + "<" + p.join(args, ", ") + ">"
+}
+
+// genericSignature will print the generic parameters of a signature, with
+// the requirements that they are held to
+func (p *swiftPrinter) genericSignature(n *swiftNode) string {
+	var params []string
+	var reqs []*swiftNode
+
+	depth := 0
+	for _, c := range n.children {
+		if c.kind != swiftParamCount {
+			reqs = append(reqs, c)
+			continue
+		}
+
+		for i := 0; i < c.index; i++ {
+			params = append(params, swiftParamName(depth, i))
+		}
+
+		depth++
+	}
+
+	text := "<" + strings.Join(params, ", ")
+	if len(reqs) != 0 {
+		text += " where " + p.join(reqs, ", ")
+	}
+
+	return text + ">"
+}
@@ -0,0 +1,66 @@
+package elfstrings
+
+import "testing"
+
+// c++filt does not demangle Swift, so the expected names are those that
+// swift-demangle gives
+func TestDemangleSwift(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   string
+	}{
+		{"$sSi", "Swift.Int"},
+		{"$sSS", "Swift.String"},
+		{"$s4main3FooV", "main.Foo"},
+		{"$s4main3fooyyF", "main.foo() -> ()"},
+		{"$sSaySiG", "[Swift.Int]"},
+		{"$sSDySSSiG", "[Swift.String : Swift.Int]"},
+		{"$sSiSg", "Swift.Int?"},
+		{"$s4main3FooC3barSiyF", "main.Foo.bar() -> Swift.Int"},
+		{"$s4main3FooVMn", "nominal type descriptor for main.Foo"},
+		{"$s4main3FooVN", "type metadata for main.Foo"},
+		{"$s4main3FooCMa", "type metadata accessor for main.Foo"},
+		{"$s4main3FooVAA1PAAMc", "protocol conformance descriptor for main.Foo : main.P in main"},
+		{"$s4main3FooC1xSivg", "main.Foo.x.getter : Swift.Int"},
+		{"$s4main3FooC1xSivs", "main.Foo.x.setter : Swift.Int"},
+		{"$s4main3FooCACycfC", "main.Foo.__allocating_init() -> main.Foo"},
+		{"$s4main3FooCfD", "main.Foo.__deallocating_deinit"},
+		{"$s4main3fooyySi_SStF", "main.foo(Swift.Int, Swift.String) -> ()"},
+		{"$s4main3fooyS2iF", "main.foo(Swift.Int) -> Swift.Int"},
+		{"$s4main3FooV1aSivpMV", "property descriptor for main.Foo.a : Swift.Int"},
+		{"$sSo8NSObjectC", "__C.NSObject"},
+		{"$s4main1PP", "main.P"},
+		{"$s4main3fooyxxlF", "main.foo<A>(A) -> A"},
+		{"$s4main3BarO1ayA2CmF", "main.Bar.a(main.Bar.Type) -> main.Bar"},
+		{"$s4main3fooySiSgSaySSGF", "main.foo([Swift.String]) -> Swift.Int?"},
+		{"$s4main3FooC5ValueV", "main.Foo.Value"},
+		{"$s4main3fooyyYaKF", "main.foo() async throws -> ()"},
+		{"_$s4main3FooVMn", "nominal type descriptor for main.Foo"},
+		{"$s4main3fooSiyFTq", "method descriptor for main.foo() -> Swift.Int"},
+	}
+
+	for _, tt := range tests {
+		got, err := UtilDemangleSwift(tt.symbol, nil)
+		if err != nil {
+			t.Errorf("UtilDemangleSwift(%q) failed: %v", tt.symbol, err)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("UtilDemangleSwift(%q) = %q, want %q", tt.symbol, got, tt.want)
+		}
+	}
+}
+
+func TestDemangleSwiftMalformed(t *testing.T) {
+	for _, symbol := range []string{
+		"$s4main3fooyySiztF",
+		"$s4main3FooVyxGlF",
+		"$s9main",
+		"$s",
+	} {
+		if got, err := UtilDemangleSwift(symbol, nil); err == nil {
+			t.Errorf("UtilDemangleSwift(%q) = %q, want an error", symbol, got)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package elfstrings
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Tag to emulate an enum of the categories a string may fall into
+type Tag int32
+
+// Categories of strings which are tagged
+const (
+	// TagFormat is a printf or strftime style format string
+	TagFormat Tag = iota
+	// TagPath is a file system path
+	TagPath
+	// TagCommand is a shell command line
+	TagCommand
+	// TagSQL is an SQL statement
+	TagSQL
+	// TagMessage is an error or log message
+	TagMessage
+	// TagEnv is the name of an environment variable
+	TagEnv
+	// TagRegistry is a registry key
+	TagRegistry
+	// TagCrypto is the name of a cryptographic algorithm
+	TagCrypto
+	// TagUserAgent is an HTTP user agent
+	TagUserAgent
+	// TagMangled is a mangled symbol name
+	TagMangled
+	// TagIOC is a string with an indicator of compromise in it
+	TagIOC
+	tagEnd
+)
+
+var tagNames = map[Tag]string{
+	TagFormat:    "format",
+	TagPath:      "path",
+	TagCommand:   "cmd",
+	TagSQL:       "sql",
+	TagMessage:   "message",
+	TagEnv:       "env",
+	TagRegistry:  "registry",
+	TagCrypto:    "crypto",
+	TagUserAgent: "useragent",
+	TagMangled:   "mangled",
+	TagIOC:       "ioc",
+}
+
+// String will return the name of the tag
+func (t Tag) String() string {
+	if name, ok := tagNames[t]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("tag(%d)", int32(t))
+}
+
+// tagRegexes are the patterns of each category, a string is
+// given the tag when any of the patterns match it
+var tagRegexes = map[Tag][]*regexp.Regexp{
+	TagPath: {
+		regexp.MustCompile(`^(~|\.{1,2})?(/[\w.@+%-]+)+/?$`),
+		regexp.MustCompile(`(^|[\s"'=(])(/[\w.@+-]+){2,}/?($|[\s"':)])`),
+		regexp.MustCompile(`(^|[\s"'=])([A-Za-z]:|%\w+%|\\\\[\w.-]+)\\[\w .$-]`),
+	},
+	TagCommand: {
+		regexp.MustCompile(`^\s*(sudo\s+)?((/usr)?/bin/)?(sh|bash|zsh|cmd(\.exe)?|powershell(\.exe)?|wget|curl|chmod|chown|chattr|rm|mv|cp|cat|echo|kill|pkill|killall|nohup|crontab|iptables|systemctl|service|nc|ncat|netcat|python[23]?|perl|busybox|tftp|ftpget|mkdir|uname|ifconfig|ps|grep|awk|sed|tar|gzip|base64|useradd|usermod|passwd|ssh|scp|dd|mount|insmod|modprobe|rmmod|sysctl|setsid|exec|eval|ln|touch|find|xargs|history|unset|export)\s+(-{1,2}\w|[/$%"'~.{\d]|\w+://)`),
+		regexp.MustCompile(`^(/usr)?/bin/(ba|z|da)?sh$`),
+		regexp.MustCompile(`2>&1|>\s*/dev/null|\|\s*(/bin/)?(ba)?sh\b|\b(sh|bash)\s+-c\s|;\s*(rm|chmod|wget|curl|cd)\s`),
+	},
+	TagSQL: {
+		regexp.MustCompile(`(?i)^\s*(SELECT\s.+\sFROM\s|INSERT\s+(OR\s+\w+\s+)?INTO\s|UPDATE\s+\S+\s+SET\s|DELETE\s+FROM\s|CREATE\s+(TEMP\w*\s+|UNIQUE\s+)?(TABLE|INDEX|VIEW|TRIGGER)\s|DROP\s+(TABLE|INDEX|VIEW|TRIGGER)\s|ALTER\s+TABLE\s|(?-i:PRAGMA)\s+\w+|BEGIN\s+(TRANSACTION|IMMEDIATE|EXCLUSIVE)|REPLACE\s+INTO\s)`),
+	},
+	TagMessage: {
+		regexp.MustCompile(`(?i)\b(error|failed|failure|cannot|can't|couldn't|unable to|invalid|warning|fatal|panic|denied|not found|out of memory|unknown|unexpected|missing|timed? ?out|not supported|too many|too long|refused)\b.*\s|\s.*\b(error|failed|failure|cannot|can't|couldn't|unable to|invalid|warning|fatal|panic|denied|not found|out of memory|unknown|unexpected|missing|timed? ?out|not supported|too many|too long|refused)\b`),
+		regexp.MustCompile(`^\[?(DEBUG|INFO|NOTICE|WARN|WARNING|ERROR|ERR|FATAL|TRACE|CRIT|CRITICAL)\]?[:\s]`),
+	},
+	TagEnv: {
+		regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)+=?$`),
+		regexp.MustCompile(`^(PATH|HOME|USER|SHELL|TERM|LANG|PWD|TMPDIR|DISPLAY|EDITOR|PAGER|VISUAL|TZ|HOSTNAME|LOGNAME|COLUMNS|LINES|MAIL|BROWSER)=?$`),
+		regexp.MustCompile(`\$\{[A-Za-z_]\w*\}|%[A-Za-z_]\w{2,}%|\$[A-Z_][A-Z0-9_]{2,}\b`),
+	},
+	TagRegistry: {
+		regexp.MustCompile(`(?i)^(HKEY_(LOCAL_MACHINE|CURRENT_USER|CLASSES_ROOT|USERS|CURRENT_CONFIG)|HKLM|HKCU|HKCR|HKU)(\\|$)`),
+		regexp.MustCompile(`(?i)(^|\\)(Software|System)\\(CurrentControlSet|Microsoft|Classes|Policies|Wow6432Node)\\`),
+	},
+	TagCrypto: {
+		regexp.MustCompile(`\b(AES|DES|3DES|RC2|RC4|RC5|RC6|IDEA|RSA|DSA|ECDSA|ECDH|ECDHE|DHE|MD4|MD5|SHA1|SHA-1|HMAC|GCM|CBC|ECB|XTEA|CAST5|PKCS#?\d+)\b`),
+		regexp.MustCompile(`(?i)\b(aes|sha|sha3|md|rc|blake2[bs]?|ripemd)[-_]?\d+\b|\b(blowfish|twofish|chacha20|xchacha20|salsa20|poly1305|camellia|serpent|ed25519|ed448|x25519|x448|curve25519|secp\d+[kr]1|prime\d+v\d|brainpoolP\d+\w*|diffie-hellman|pbkdf2|bcrypt|scrypt|argon2(id|i|d)?|triple-?des|siphash|whirlpool|streebog|gost\w*|sm[34])\b`),
+	},
+	TagUserAgent: {
+		regexp.MustCompile(`^Mozilla/\d|(?i)^User-Agent:|\b(curl|Wget|python-requests|python-urllib|Go-http-client|okhttp|Java|libwww-perl|Lynx|Opera|AppleWebKit|Chrome|Safari|Gecko|MSIE \d)/[\d.]+`),
+	},
+	TagMangled: {
+		regexp.MustCompile(`^_{1,2}Z(N|L|S[a-zA-Z_]|T[ISVT]|Th|Tv|Tc|GV|St|\d)\w*`),
+		regexp.MustCompile(`^_R[CMNINSvUu][0-9A-Za-z_]{2,}`),
+		regexp.MustCompile(`^_?\$s\d+\w+|^_?\$S\w+`),
+		regexp.MustCompile(`^_D\d+[A-Za-z_]\w*`),
+		regexp.MustCompile(`^caml[A-Z][\w']*(__|\$)[A-Za-z_]\w*`),
+		regexp.MustCompile(`^\?{1,2}[A-Za-z_$?@][\w$?@]*@@[A-Z0-9_$?@]+$`),
+	},
+}
+
+// tagExcludes are the patterns of strings which look like a category
+// but never are, such as the symbol versions of the C library
+var tagExcludes = map[Tag]*regexp.Regexp{
+	TagSQL:    regexp.MustCompile(`^\s*[A-Z][a-z]`),
+	TagEnv:    regexp.MustCompile(`^(GLIBC|GLIBCXX|CXXABI|GCC|LIBC|GNU)_`),
+	TagCrypto: regexp.MustCompile(`^(GLIBC|GLIBCXX|CXXABI|GCC)_|^\.`),
+}
+
+// UtilTags will classify the text into each of the categories that it
+// falls into, iocs are the indicators which were found in it
+func UtilTags(text string, iocs []IOC) []Tag {
+	var tags []Tag
+
+	for tag := Tag(0); tag < tagEnd; tag++ {
+		if utilHasTag(tag, text, iocs) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// utilHasTag will check if the text falls into the category
+func utilHasTag(tag Tag, text string, iocs []IOC) bool {
+	switch tag {
+	case TagFormat:
+		return formatRegex.MatchString(text)
+	case TagIOC:
+		return len(iocs) != 0
+	case TagPath:
+		// the path of a URL is already an indicator
+		for _, ioc := range iocs {
+			if ioc.Type == IOCURL {
+				return false
+			}
+		}
+	}
+
+	if exclude, ok := tagExcludes[tag]; ok && exclude.MatchString(text) {
+		return false
+	}
+
+	for _, regex := range tagRegexes[tag] {
+		if regex.MatchString(text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UtilHasAnyTag will check if any of the tags are amongst those wanted
+func UtilHasAnyTag(tags []Tag, wanted []Tag) bool {
+	for _, tag := range tags {
+		for _, want := range wanted {
+			if tag == want {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// TagParseStr converts a comma separated list of tags
+// such as "path,cmd" into the tags
+func TagParseStr(list string) ([]Tag, error) {
+	var tags []Tag
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		found := false
+		for tag, tagName := range tagNames {
+			if tagName == name {
+				tags = append(tags, tag)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unknown tag %q", name)
+		}
+	}
+
+	return tags, nil
+}
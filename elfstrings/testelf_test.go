@@ -0,0 +1,207 @@
+package elfstrings
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// testBase is where the sections of the test binaries are loaded, each is
+// at the same offset in the file as it is from here
+const testBase = 0x400000
+
+// testSection is a section of a test binary, those with an address are
+// loaded by a single segment which is readable, writable and executable.
+// The size in its header is that of the data unless it is given
+type testSection struct {
+	name  string
+	typ   elf.SectionType
+	flags elf.SectionFlag
+	addr  uint64
+	size  uint64
+	data  []byte
+}
+
+// testSymbol is a symbol of a test binary, the section is the index of
+// the section it is in, counting from one
+type testSymbol struct {
+	name    string
+	typ     elf.SymType
+	value   uint64
+	size    uint64
+	section uint16
+}
+
+// testELF will write a little endian x86-64 executable with the sections
+// and symbols, returning a reader of it which is closed with the test
+func testELF(t *testing.T, sections []testSection, symbols []testSymbol) *ElfReader {
+	t.Helper()
+
+	le := binary.LittleEndian
+
+	var file bytes.Buffer
+	file.Write(make([]byte, 0x1000))
+
+	// the loaded sections are placed at their addresses, and the rest
+	// are put after them
+	offsets := make([]uint64, len(sections))
+	var end uint64
+	for i, s := range sections {
+		if s.addr == 0 {
+			continue
+		}
+
+		offsets[i] = s.addr - testBase
+		if offsets[i]+uint64(len(s.data)) > end {
+			end = offsets[i] + uint64(len(s.data))
+		}
+	}
+
+	buf := make([]byte, end)
+	copy(buf, file.Bytes())
+	for i, s := range sections {
+		if s.addr != 0 {
+			copy(buf[offsets[i]:], s.data)
+		}
+	}
+
+	file.Reset()
+	file.Write(buf)
+
+	type header struct {
+		name    string
+		typ     elf.SectionType
+		flags   elf.SectionFlag
+		addr    uint64
+		off     uint64
+		size    uint64
+		link    uint32
+		info    uint32
+		entsize uint64
+	}
+
+	var headers []header
+	for i, s := range sections {
+		off := offsets[i]
+		if s.addr == 0 {
+			off = uint64(file.Len())
+			file.Write(s.data)
+		}
+
+		size := s.size
+		if size == 0 {
+			size = uint64(len(s.data))
+		}
+
+		headers = append(headers, header{name: s.name, typ: s.typ, flags: s.flags, addr: s.addr, off: off, size: size})
+	}
+
+	if symbols != nil {
+		strtab := []byte{0}
+		symtab := make([]byte, 24)
+		for _, sym := range symbols {
+			entry := make([]byte, 24)
+			le.PutUint32(entry, uint32(len(strtab)))
+			entry[4] = byte(elf.STB_GLOBAL)<<4 | byte(sym.typ)
+			le.PutUint16(entry[6:], sym.section)
+			le.PutUint64(entry[8:], sym.value)
+			le.PutUint64(entry[16:], sym.size)
+			symtab = append(symtab, entry...)
+			strtab = append(append(strtab, sym.name...), 0)
+		}
+
+		link := uint32(len(headers) + 2)
+		headers = append(headers, header{name: ".symtab", typ: elf.SHT_SYMTAB, off: uint64(file.Len()), size: uint64(len(symtab)), link: link, info: 1, entsize: 24})
+		file.Write(symtab)
+		headers = append(headers, header{name: ".strtab", typ: elf.SHT_STRTAB, off: uint64(file.Len()), size: uint64(len(strtab))})
+		file.Write(strtab)
+	}
+
+	shstrtab := []byte{0}
+	names := make([]uint32, len(headers)+1)
+	for i, h := range headers {
+		names[i] = uint32(len(shstrtab))
+		shstrtab = append(append(shstrtab, h.name...), 0)
+	}
+
+	names[len(headers)] = uint32(len(shstrtab))
+	shstrtab = append(shstrtab, ".shstrtab\x00"...)
+	headers = append(headers, header{name: ".shstrtab", typ: elf.SHT_STRTAB, off: uint64(file.Len()), size: uint64(len(shstrtab))})
+	file.Write(shstrtab)
+
+	for file.Len()%8 != 0 {
+		file.WriteByte(0)
+	}
+
+	shoff := uint64(file.Len())
+	file.Write(make([]byte, 64))
+	for i, h := range headers {
+		sh := make([]byte, 64)
+		le.PutUint32(sh, names[i])
+		le.PutUint32(sh[4:], uint32(h.typ))
+		le.PutUint64(sh[8:], uint64(h.flags))
+		le.PutUint64(sh[16:], h.addr)
+		le.PutUint64(sh[24:], h.off)
+		le.PutUint64(sh[32:], h.size)
+		le.PutUint32(sh[40:], h.link)
+		le.PutUint32(sh[44:], h.info)
+		le.PutUint64(sh[48:], 1)
+		le.PutUint64(sh[56:], h.entsize)
+		file.Write(sh)
+	}
+
+	out := file.Bytes()
+
+	copy(out, elf.ELFMAG)
+	out[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	out[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	out[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	le.PutUint16(out[16:], uint16(elf.ET_EXEC))
+	le.PutUint16(out[18:], uint16(elf.EM_X86_64))
+	le.PutUint32(out[20:], uint32(elf.EV_CURRENT))
+	le.PutUint64(out[24:], testBase+0x1000)
+	le.PutUint64(out[32:], 64)
+	le.PutUint64(out[40:], shoff)
+	le.PutUint16(out[52:], 64)
+	le.PutUint16(out[54:], 56)
+	le.PutUint16(out[56:], 1)
+	le.PutUint16(out[58:], 64)
+	le.PutUint16(out[60:], uint16(len(headers)+1))
+	le.PutUint16(out[62:], uint16(len(headers)))
+
+	ph := out[64:]
+	le.PutUint32(ph, uint32(elf.PT_LOAD))
+	le.PutUint32(ph[4:], uint32(elf.PF_R|elf.PF_W|elf.PF_X))
+	le.PutUint64(ph[16:], testBase)
+	le.PutUint64(ph[24:], testBase)
+	le.PutUint64(ph[32:], end)
+	le.PutUint64(ph[40:], end)
+	le.PutUint64(ph[48:], 0x1000)
+
+	path := filepath.Join(t.TempDir(), "test.elf")
+	if err := ioutil.WriteFile(path, out, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewELFReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+// testWords will lay out the words as the data of a 64 bit section
+func testWords(words ...uint64) []byte {
+	buf := make([]byte, 8*len(words))
+	for i, w := range words {
+		binary.LittleEndian.PutUint64(buf[i*8:], w)
+	}
+
+	return buf
+}
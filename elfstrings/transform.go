@@ -0,0 +1,82 @@
+package elfstrings
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// Transform to emulate an enum of the ways a string can be obfuscated
+// or encoded
+type Transform int32
+
+// Transforms that are undone to recover obfuscated and encoded strings
+const (
+	// TransformXOR is each byte XORed with a single or repeating key
+	TransformXOR Transform = iota
+	// TransformADD is a single byte key added to each byte
+	TransformADD
+	// TransformROL is each byte rotated left by the key
+	TransformROL
+	// TransformBase64 is a string encoded with either Base64 alphabet
+	TransformBase64
+	// TransformHex is a string encoded as hexadecimal digits
+	TransformHex
+	// TransformURL is a string with percent-encoded characters
+	TransformURL
+	// TransformROT13 is a string with its letters rotated by 13
+	TransformROT13
+	// TransformGzip is a gzip stream
+	TransformGzip
+	// TransformZlib is a zlib stream
+	TransformZlib
+	// TransformBzip2 is a bzip2 stream
+	TransformBzip2
+	// TransformELF is an ELF binary, which the strings are extracted from
+	TransformELF
+	transformEnd
+)
+
+var transformNames = map[Transform]string{
+	TransformXOR:    "xor",
+	TransformADD:    "add",
+	TransformROL:    "rol",
+	TransformBase64: "base64",
+	TransformHex:    "hex",
+	TransformURL:    "url",
+	TransformROT13:  "rot13",
+	TransformGzip:   "gzip",
+	TransformZlib:   "zlib",
+	TransformBzip2:  "bzip2",
+	TransformELF:    "elf",
+}
+
+// String will return the name of the transform
+func (t Transform) String() string {
+	if name, ok := transformNames[t]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("transform(%d)", int32(t))
+}
+
+// Decoding is a transform which was undone to recover a string,
+// along with the key that it was undone with
+type Decoding struct {
+	Transform Transform
+	// Key is the key of the transform, the repeating key of XOR
+	// starts at the first byte of the string
+	Key []byte
+}
+
+// String will return the transform along with its key, such as
+// xor(0x5a) or rol(3)
+func (d Decoding) String() string {
+	switch {
+	case len(d.Key) == 0:
+		return d.Transform.String()
+	case d.Transform == TransformROL:
+		return fmt.Sprintf("%s(%d)", d.Transform, d.Key[0])
+	default:
+		return fmt.Sprintf("%s(0x%s)", d.Transform, hex.EncodeToString(d.Key))
+	}
+}
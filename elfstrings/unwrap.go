@@ -0,0 +1,411 @@
+package elfstrings
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"compress/zlib"
+	"debug/elf"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// The limits of the unwrapping, so that a stream which decompresses
+// to far more than it is cannot exhaust the memory
+const (
+	// unwrapMinLength is the shortest string that is checked for being
+	// encoded, shorter ones decode to too little to be of interest
+	unwrapMinLength = 8
+	// unwrapMaxSize is the most that a compressed stream is inflated to
+	unwrapMaxSize = 16 << 20
+	// unwrapMinBase64 is the shortest string that is decoded as Base64,
+	// as many short words are made of its alphabet
+	unwrapMinBase64 = 12
+	// unwrapMinNGram is the lowest bigram score of a string decoded as
+	// ROT13, as any text can be rotated into something
+	unwrapMinNGram = 0.7
+	// unwrapMinGain is how much closer the bigrams of a string must be
+	// to those of real text after ROT13 than before
+	unwrapMinGain = 0.3
+)
+
+var (
+	// base64Regex matches the strings which are entirely either Base64
+	// alphabet, with or without the padding
+	base64Regex = regexp.MustCompile(`^([A-Za-z0-9+/]+|[A-Za-z0-9_-]+)={0,2}$`)
+	// hexRegex matches the strings which are entirely hexadecimal digits
+	hexRegex = regexp.MustCompile(`^([0-9a-fA-F]{2})+$`)
+	// percentRegex matches a percent-encoded character
+	percentRegex = regexp.MustCompile(`%[0-9a-fA-F]{2}`)
+)
+
+// unwrapLayer is what a string decodes to with one of the encodings
+type unwrapLayer struct {
+	transform Transform
+	buf       []byte
+}
+
+// readerWithUnwrapped will return the record of the string when it passed
+// the filters, followed by the strings decoded from it up to the depth in
+// opts. The string is kept whatever the filters when anything is decoded
+// from it, so that what is decoded can be linked to it
+func (r *ElfReader) readerWithUnwrapped(raw []byte, f *FilteredString, record func(f *FilteredString) StringRecord, opts *Options) []StringRecord {
+	if opts.UnwrapDepth == 0 {
+		if f == nil {
+			return nil
+		}
+
+		return []StringRecord{record(f)}
+	}
+
+	kept := f
+	if kept == nil {
+		str := string(raw)
+		iocs := UtilFindIOCs(str)
+		kept = &FilteredString{Text: str, Score: UtilScore(str), IOCs: iocs, Tags: UtilTags(str, iocs)}
+	}
+
+	parent := record(kept)
+	children := r.readerUnwrap(&parent, raw, nil, opts.UnwrapDepth, opts)
+
+	if f == nil && len(children) == 0 {
+		return nil
+	}
+
+	return append([]StringRecord{parent}, children...)
+}
+
+// readerUnwrap will decode the string with each of the encodings that it
+// could be in, returning the strings that it decodes to along with those
+// decoded from them in turn
+func (r *ElfReader) readerUnwrap(parent *StringRecord, raw []byte, chain []Decoding, depth uint64, opts *Options) []StringRecord {
+	var records []StringRecord
+
+	if depth == 0 {
+		return nil
+	}
+
+	decodings := append(append([]Decoding{}, parent.Decodings...), chain...)
+	for _, layer := range utilUnwrapLayers(raw, decodings) {
+		next := append(append([]Decoding{}, chain...), Decoding{Transform: layer.transform})
+		records = append(records, r.readerUnwrapBytes(parent, layer.buf, next, depth-1, opts)...)
+	}
+
+	return records
+}
+
+// readerUnwrapBytes will find the strings within bytes that were decoded,
+// which are either text, a compressed stream or an ELF binary
+func (r *ElfReader) readerUnwrapBytes(parent *StringRecord, buf []byte, chain []Decoding, depth uint64, opts *Options) []StringRecord {
+	if bytes.HasPrefix(buf, []byte(elf.ELFMAG)) {
+		return r.readerUnwrapELF(parent, buf, chain, depth, opts)
+	}
+
+	if transform, inflated := utilInflate(buf); inflated != nil {
+		if depth == 0 {
+			return nil
+		}
+
+		next := append(append([]Decoding{}, chain...), Decoding{Transform: transform})
+		if utilIsText(inflated) {
+			return r.readerUnwrapText(parent, inflated, next, depth-1, opts)
+		}
+
+		// the text within a stream of anything else is found as
+		// it would be within the file
+		var records []StringRecord
+		start := 0
+		for i := 0; i <= len(inflated); i++ {
+			if i < len(inflated) && (UtilIsPrintable(inflated[i]) || inflated[i] >= utf8.RuneSelf) {
+				continue
+			}
+
+			if i-start >= ScanMinLength && uint64(i-start) >= opts.MinLength {
+				records = append(records, r.readerUnwrapText(parent, inflated[start:i], next, depth-1, opts)...)
+			}
+
+			start = i + 1
+		}
+
+		return records
+	}
+
+	if utilIsText(buf) {
+		return r.readerUnwrapText(parent, buf, chain, depth, opts)
+	}
+
+	return nil
+}
+
+// readerUnwrapText will create the record of the text which was decoded
+// from the parent, if it passes the filters, followed by what is decoded
+// from the text in turn
+func (r *ElfReader) readerUnwrapText(parent *StringRecord, buf []byte, chain []Decoding, depth uint64, opts *Options) []StringRecord {
+	var records []StringRecord
+
+	f := UtilFilterString(buf, opts)
+
+	// any text can be rotated, so it must read far better for it to
+	// be kept, though what it decodes to in turn still may be
+	last := chain[len(chain)-1].Transform
+	if last == TransformROT13 && f != nil && (f.Score.NGram < unwrapMinNGram || f.Score.NGram-parent.Score.NGram < unwrapMinGain) {
+		f = nil
+	}
+
+	child := r.readerChildRecord(parent, chain)
+	child.Raw = buf
+	if f != nil {
+		child.Text, child.Encoding, child.Score, child.IOCs, child.Tags = f.Text, f.Encoding, f.Score, f.IOCs, f.Tags
+		child.Language, child.Mangled = f.Language, f.Mangled
+		records = append(records, child)
+	} else {
+		child.Text = string(buf)
+		child.Score = UtilScore(child.Text)
+	}
+
+	decoded := r.readerUnwrap(&child, buf, nil, depth, opts)
+
+	// what is decoded from text that is not kept is linked to the string
+	// it was decoded from in turn, so that every parent is one of the
+	// records, the decodings still hold each step
+	if f == nil {
+		for i := range decoded {
+			if decoded[i].Parent == &child {
+				decoded[i].Parent = parent
+			}
+		}
+	}
+
+	return append(records, decoded...)
+}
+
+// readerUnwrapELF will extract the strings from the default sections of
+// the ELF binary which was decoded from the parent
+func (r *ElfReader) readerUnwrapELF(parent *StringRecord, buf []byte, chain []Decoding, depth uint64, opts *Options) []StringRecord {
+	var records []StringRecord
+
+	// the reader works on a file, which the binary is written out to
+	tmp, err := ioutil.TempFile("", "elf-strings-")
+	if err != nil {
+		return nil
+	}
+
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(buf)
+	tmp.Close()
+	if err != nil {
+		return nil
+	}
+
+	inner, err := NewELFReader(tmp.Name())
+	if err != nil {
+		return nil
+	}
+
+	defer inner.Close()
+
+	innerOpts := *opts
+	innerOpts.UnwrapDepth = depth
+	innerOpts.MaxCount = 0
+	innerOpts.Xrefs = false
+
+	// the records of the binary are numbered by the inner reader, so each
+	// is given its own ID and the parents are found by the inner ones
+	outer := make(map[uint64]*StringRecord)
+
+	chain = append(append([]Decoding{}, chain...), Decoding{Transform: TransformELF})
+	sections, _ := inner.ReaderSelectSections(&SectionSelector{Mode: SectionsDefault})
+	for _, section := range sections {
+		for _, rec := range inner.ReaderExtract(section, &innerOpts) {
+			child := r.readerChildRecord(parent, append(chain, rec.Decodings...))
+			child.Raw, child.Text, child.Encoding = rec.Raw, rec.Text, rec.Encoding
+			child.Score, child.IOCs, child.Tags = rec.Score, rec.IOCs, rec.Tags
+
+			// what is decoded within the binary is still linked
+			// to what it was decoded from there
+			if rec.Parent != nil {
+				if p, ok := outer[rec.Parent.ID]; ok {
+					child.Parent = p
+				}
+			}
+
+			kept := child
+			outer[rec.ID] = &kept
+
+			records = append(records, child)
+		}
+	}
+
+	return records
+}
+
+// readerChildRecord will create the record of a string decoded from the
+// parent, which is found where the parent is
+func (r *ElfReader) readerChildRecord(parent *StringRecord, chain []Decoding) StringRecord {
+	child := *parent
+
+	child.ID = r.readerNextID()
+	child.Parent = parent
+	child.Rank = nil
+	child.Decodings = append(append([]Decoding{}, parent.Decodings...), chain...)
+
+	return child
+}
+
+// utilUnwrapLayers will decode the string with each of the encodings that
+// it looks to be in, decodings are how it was decoded so far
+func utilUnwrapLayers(raw []byte, decodings []Decoding) []unwrapLayer {
+	var layers []unwrapLayer
+
+	// a character repeated for padding decodes to the same again
+	str := strings.TrimSpace(string(raw))
+	if len(str) < unwrapMinLength || utilIsRepeating([]byte(str)) {
+		return nil
+	}
+
+	if hexRegex.MatchString(str) {
+		if buf, err := hex.DecodeString(str); err == nil {
+			layers = append(layers, unwrapLayer{TransformHex, buf})
+		}
+	} else if len(str) >= unwrapMinBase64 && base64Regex.MatchString(str) && utilHasBothCases(str) {
+		if buf := utilDecodeBase64(str); buf != nil {
+			layers = append(layers, unwrapLayer{TransformBase64, buf})
+		}
+	}
+
+	// what was rotated is only decoded further when it was rotated to
+	// hide the alphabet of another encoding, rotating twice only gives
+	// back what was rotated
+	if len(decodings) != 0 && decodings[len(decodings)-1].Transform == TransformROT13 {
+		return layers
+	}
+
+	// a stray percent sign is a format rather than an escape
+	if escapes := percentRegex.FindAllStringIndex(str, -1); escapes != nil && len(escapes) == strings.Count(str, "%") {
+		if dec, err := url.QueryUnescape(str); err == nil && dec != str {
+			layers = append(layers, unwrapLayer{TransformURL, []byte(dec)})
+		}
+	}
+
+	if utilIsProse(str) {
+		layers = append(layers, unwrapLayer{TransformROT13, []byte(utilROT13(str))})
+	}
+
+	return layers
+}
+
+// utilHasBothCases will check whether the string has both upper and lower
+// case letters, as Base64 almost always does and words in its alphabet
+// often do not
+func utilHasBothCases(str string) bool {
+	return strings.ToLower(str) != str && strings.ToUpper(str) != str
+}
+
+// utilDecodeBase64 will decode the string with whichever Base64 alphabet
+// it is in, nil if it is not valid in either
+func utilDecodeBase64(str string) []byte {
+	trimmed := strings.TrimRight(str, "=")
+
+	encoding := base64.RawStdEncoding
+	if strings.ContainsAny(trimmed, "-_") {
+		encoding = base64.RawURLEncoding
+	}
+
+	buf, err := encoding.DecodeString(trimmed)
+	if err != nil {
+		return nil
+	}
+
+	return buf
+}
+
+// utilIsProse will check whether the string is ASCII and mostly letters
+// without being an identifier, the only strings which are rotated as the
+// rest turn into something readable by chance too often
+func utilIsProse(str string) bool {
+	letters := 0
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		switch {
+		case c >= utf8.RuneSelf || c == '_':
+			return false
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+			letters++
+		}
+	}
+
+	return letters*2 >= len(str)
+}
+
+// utilROT13 will rotate the letters of the string by 13
+func utilROT13(str string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return 'a' + (r-'a'+13)%26
+		case r >= 'A' && r <= 'Z':
+			return 'A' + (r-'A'+13)%26
+		}
+
+		return r
+	}, str)
+}
+
+// utilInflate will decompress the buffer when it is a gzip, zlib or bzip2
+// stream, returning the transform and what it decompresses to, or nil
+// when it is not a stream that can be decompressed
+func utilInflate(buf []byte) (Transform, []byte) {
+	var transform Transform
+	var reader io.Reader
+	var err error
+
+	switch {
+	case len(buf) >= 3 && buf[0] == 0x1f && buf[1] == 0x8b && buf[2] == 8:
+		transform = TransformGzip
+		reader, err = gzip.NewReader(bytes.NewReader(buf))
+	case len(buf) >= 2 && buf[0]&0x0f == 8 && (uint16(buf[0])<<8|uint16(buf[1]))%31 == 0:
+		transform = TransformZlib
+		reader, err = zlib.NewReader(bytes.NewReader(buf))
+	case len(buf) >= 4 && bytes.HasPrefix(buf, []byte("BZh")) && buf[3] >= '1' && buf[3] <= '9':
+		transform = TransformBzip2
+		reader = bzip2.NewReader(bytes.NewReader(buf))
+	default:
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, nil
+	}
+
+	// a stream which is cut short still gives what was decompressed
+	inflated, _ := ioutil.ReadAll(io.LimitReader(reader, unwrapMaxSize))
+	if len(inflated) == 0 {
+		return 0, nil
+	}
+
+	return transform, inflated
+}
+
+// utilIsText will check whether the bytes are entirely printable text,
+// with the line breaks that a config would have
+func utilIsText(buf []byte) bool {
+	if !utf8.Valid(buf) {
+		return false
+	}
+
+	for _, b := range buf {
+		if !UtilIsPrintable(b) && b < utf8.RuneSelf && b != '\n' && b != '\r' {
+			return false
+		}
+	}
+
+	return len(buf) != 0
+}
@@ -0,0 +1,144 @@
+package elfstrings
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"debug/elf"
+	"encoding/base64"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnwrapLayers(t *testing.T) {
+	// the bytes which the URL alphabet of Base64 writes with - and _
+	urlSafe := []byte{0xfb, 0xef, 0xbe, 'A', 'b', 'c', 0xff, 0xfe, 'x', 'Y', 'z'}
+
+	tests := []struct {
+		name      string
+		text      string
+		decodings []Decoding
+		want      []unwrapLayer
+	}{
+		{"hex", "68656c6c6f20776f726c64", nil, []unwrapLayer{{TransformHex, []byte("hello world")}}},
+		{"base64", "aHR0cDovL2V4YW1wbGUuY29tL2dhdGU=", nil, []unwrapLayer{
+			{TransformBase64, []byte("http://example.com/gate")},
+			{TransformROT13, []byte("nUE0pQbiY2I4LJ1joTHhL29gY2quqTH=")},
+		}},
+		{"base64 url", base64.URLEncoding.EncodeToString(urlSafe), nil, []unwrapLayer{{TransformBase64, urlSafe}}},
+		{"percent", "http%3A%2F%2Fexample.com%2Fx", nil, []unwrapLayer{
+			{TransformURL, []byte("http://example.com/x")},
+			{TransformROT13, []byte("uggc%3N%2S%2Srknzcyr.pbz%2Sk")},
+		}},
+		{"stray percent", "100% of %d", nil, nil},
+		{"rot13", "uryyb jbeyq sebz gur pbasvt", nil, []unwrapLayer{{TransformROT13, []byte("hello world from the config")}}},
+		{"rot13 twice", "uryyb jbeyq sebz gur pbasvt", []Decoding{{Transform: TransformROT13}}, nil},
+		{"one case", "abcdefghijklmnop", nil, []unwrapLayer{{TransformROT13, []byte("nopqrstuvwxyzabc")}}},
+		{"short", "aGk=", nil, nil},
+		{"repeating", "AAAAAAAAAAAAAAAA", nil, nil},
+	}
+
+	for _, tt := range tests {
+		got := utilUnwrapLayers([]byte(tt.text), tt.decodings)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: utilUnwrapLayers(%q) = %v, want %v", tt.name, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestInflate(t *testing.T) {
+	text := []byte("user=admin;pass=hunter2")
+
+	var gz, zl, bomb bytes.Buffer
+
+	w := gzip.NewWriter(&gz)
+	w.Write(text)
+	w.Close()
+
+	z := zlib.NewWriter(&zl)
+	z.Write(text)
+	z.Close()
+
+	// a stream which inflates to more than the limit is cut off at it
+	w = gzip.NewWriter(&bomb)
+	w.Write(make([]byte, unwrapMaxSize+1<<20))
+	w.Close()
+
+	bz, _ := hex.DecodeString("425a683931415926535917c25d5000000b0980100a26635e00200022832613c50a60003d7a0e20b31b281c472825f17724538509017c25d500")
+
+	tests := []struct {
+		name      string
+		buf       []byte
+		transform Transform
+		want      []byte
+	}{
+		{"gzip", gz.Bytes(), TransformGzip, text},
+		{"zlib", zl.Bytes(), TransformZlib, text},
+		{"bzip2", bz, TransformBzip2, text},
+		{"bomb", bomb.Bytes(), TransformGzip, make([]byte, unwrapMaxSize)},
+		{"text", text, 0, nil},
+		{"gzip header only", gz.Bytes()[:10], 0, nil},
+	}
+
+	for _, tt := range tests {
+		transform, got := utilInflate(tt.buf)
+		if transform != tt.transform || !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: utilInflate gave %s and %d bytes, want %s and %d bytes", tt.name, transform, len(got), tt.transform, len(tt.want))
+		}
+	}
+}
+
+func TestUnwrapDepth(t *testing.T) {
+	const rodata = 0x402000
+
+	// a URL compressed with gzip, then written as hex and Base64. The
+	// hex is kept as a string, the compressed bytes in it are not
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte("http://c2.example.net/beacon"))
+	w.Close()
+
+	layered := base64.StdEncoding.EncodeToString([]byte(hex.EncodeToString(gz.Bytes())))
+
+	r := testELF(t, []testSection{
+		{name: ".rodata", typ: elf.SHT_PROGBITS, flags: elf.SHF_ALLOC, addr: rodata, data: append([]byte(layered), 0)},
+	}, nil)
+
+	tests := []struct {
+		depth uint64
+		want  []string
+	}{
+		{0, nil},
+		{1, []string{"base64"}},
+		{2, []string{"base64"}},
+		{3, []string{"base64", "base64,hex,gzip http://c2.example.net/beacon"}},
+		{8, []string{"base64", "base64,hex,gzip http://c2.example.net/beacon"}},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, rec := range r.ReaderExtract(r.ExecReader.Section(".rodata"), &Options{UnwrapDepth: tt.depth}) {
+			if rec.Decodings == nil {
+				continue
+			}
+
+			var chain []string
+			for _, d := range rec.Decodings {
+				chain = append(chain, d.String())
+			}
+
+			desc := strings.Join(chain, ",")
+			if strings.HasPrefix(rec.Text, "http") {
+				desc += " " + rec.Text
+			}
+
+			got = append(got, desc)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("depth %d: decoded %q, want %q", tt.depth, got, tt.want)
+		}
+	}
+}
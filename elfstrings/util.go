@@ -1,10 +1,10 @@
-package main
+package elfstrings
 
 import (
 	"debug/elf"
 	"fmt"
-
-	"github.com/ianlancetaylor/demangle"
+	"unicode"
+	"unicode/utf8"
 )
 
 // UtilConvHex converts a string to a C-like hexadecimal string literal
@@ -19,9 +19,10 @@ func UtilConvHex(buf string) string {
 }
 
 // UtilDemangle will demangle a symbol by string, this is
-// simply just a friendly wrapped around the demangle package
+// simply just a friendly wrapper around UtilDemangleSymbol
+// with its default options, which detects the language
 func UtilDemangle(symbol *string) (string, error) {
-	x, err := demangle.ToString(*symbol)
+	x, _, err := UtilDemangleSymbol(*symbol, nil)
 	if err != nil {
 		return "", err
 	}
@@ -50,25 +51,45 @@ func UtilUniqueSlice(s []uint64) []uint64 {
 }
 
 // UtilIsNice will validate to make sure that the string in question
-// is of 'human readable' format
+// is of 'human readable' format, it must be valid UTF-8 and any letters
+// outside of ASCII must all be from the same script
 func UtilIsNice(str string) bool {
-	length := len(str)
+	if !utf8.ValidString(str) {
+		return false
+	}
+
+	var text []rune
+	wide := false
 
 	spaces := 0
-	for i := 0; i < length; i++ {
-		if str[i] == ' ' {
+	for _, r := range str {
+		if r == ' ' {
 			spaces++
 		}
 
-		if str[i] < ' ' && !(str[i] == '\r' || str[i] == '\n') {
+		if r < ' ' && !(r == '\r' || r == '\n') {
+			return false
+		}
+
+		if r == 0x7f || r >= utf8.RuneSelf && !unicode.IsPrint(r) {
 			return false
 		}
+
+		if r >= utf8.RuneSelf {
+			wide = true
+		}
+
+		text = append(text, r)
 	}
 
-	if spaces == length {
+	if spaces == len(text) {
 		return false
 	}
 
+	if wide {
+		return UtilIsSingleScript(text)
+	}
+
 	return true
 }
 
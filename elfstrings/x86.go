@@ -0,0 +1,283 @@
+package elfstrings
+
+import (
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// utilXrefsX86 will decode the x86 code at addr with a linear sweep, and
+// pass each address that an instruction references to add. mode is 32 or
+// 64. On x86-64 these are the RIP relative operands, on i386 they are the
+// absolute operands and those relative to the GOT in position independent
+// code, where the GOT is found by following the get_pc_thunk calls
+func utilXrefsX86(code []byte, addr uint64, mode int, add func(from uint64, to uint64)) {
+	// known are the registers which hold an address, from the
+	// return address of a pc thunk and the adds that follow it
+	known := make(map[x86asm.Reg]uint64)
+
+	var popPC uint64
+	for off := 0; off < len(code); {
+		inst, err := x86asm.Decode(code[off:], mode)
+		if err != nil || inst.Len == 0 {
+			off++
+			continue
+		}
+
+		pc := addr + uint64(off)
+		next := pc + uint64(inst.Len)
+
+		for _, arg := range inst.Args {
+			switch a := arg.(type) {
+			case x86asm.Mem:
+				// the decoder leaves a displacement of four bytes
+				// unsigned, only one of a byte is sign extended
+				disp := uint64(int64(int32(a.Disp)))
+
+				switch {
+				case a.Base == x86asm.RIP:
+					add(pc, next+disp)
+				case a.Base == 0 && a.Index == 0:
+					add(pc, utilMask(uint64(a.Disp), mode))
+				case a.Index == 0:
+					if base, ok := known[a.Base]; ok {
+						add(pc, utilMask(base+disp, mode))
+					}
+				}
+			case x86asm.Imm:
+				add(pc, utilMask(uint64(a), mode))
+			}
+		}
+
+		if mode == 32 {
+			popPC = utilTrackPIC(&inst, code, addr, next, popPC, known)
+		}
+
+		off += inst.Len
+	}
+}
+
+// utilTrackPIC will follow the registers which are loaded with the program
+// counter by a pc thunk or a call and pop, and then moved to the GOT by an
+// add, anything else written to one of those registers forgets it. popPC is
+// the return address of a call to the next instruction, which is returned
+func utilTrackPIC(inst *x86asm.Inst, code []byte, addr uint64, next uint64, popPC uint64, known map[x86asm.Reg]uint64) uint64 {
+	dst, isReg := inst.Args[0].(x86asm.Reg)
+
+	switch inst.Op {
+	case x86asm.CALL:
+		// calls do not preserve the scratch registers
+		delete(known, x86asm.EAX)
+		delete(known, x86asm.ECX)
+		delete(known, x86asm.EDX)
+
+		rel, ok := inst.Args[0].(x86asm.Rel)
+		if !ok {
+			return 0
+		}
+
+		if rel == 0 {
+			return next
+		}
+
+		target := next + uint64(int64(rel))
+		if reg, ok := utilPCThunk(code, addr, target); ok {
+			known[reg] = next
+		}
+
+		return 0
+	case x86asm.POP:
+		if isReg && popPC != 0 {
+			known[dst] = popPC
+			return 0
+		}
+
+		// the register is restored in an epilogue, but the code that
+		// follows is still reached by the branches of the function
+		if isReg && next < addr+uint64(len(code)) {
+			ret, err := x86asm.Decode(code[next-addr:], 32)
+			if err == nil && ret.Op == x86asm.RET {
+				return 0
+			}
+		}
+	case x86asm.ADD:
+		if base, ok := known[dst]; ok && isReg {
+			if imm, ok := inst.Args[1].(x86asm.Imm); ok {
+				known[dst] = utilMask(base+uint64(imm), 32)
+				return 0
+			}
+		}
+	case x86asm.CMP, x86asm.TEST, x86asm.PUSH:
+		return 0
+	}
+
+	if isReg {
+		delete(known, dst)
+	}
+
+	return 0
+}
+
+// utilPCThunk will check if the function at target is a pc thunk, which
+// loads its return address into a register, returning that register
+func utilPCThunk(code []byte, addr uint64, target uint64) (x86asm.Reg, bool) {
+	if target < addr || target >= addr+uint64(len(code)) {
+		return 0, false
+	}
+
+	off := target - addr
+
+	inst, err := x86asm.Decode(code[off:], 32)
+	if err != nil || inst.Op != x86asm.MOV {
+		return 0, false
+	}
+
+	reg, ok := inst.Args[0].(x86asm.Reg)
+	mem, isMem := inst.Args[1].(x86asm.Mem)
+	if !ok || !isMem || mem.Base != x86asm.ESP || mem.Index != 0 || mem.Disp != 0 {
+		return 0, false
+	}
+
+	off += uint64(inst.Len)
+	if off >= uint64(len(code)) {
+		return 0, false
+	}
+
+	ret, err := x86asm.Decode(code[off:], 32)
+	if err != nil || ret.Op != x86asm.RET {
+		return 0, false
+	}
+
+	return reg, true
+}
+
+// utilMask will truncate the address to the width of the mode
+func utilMask(v uint64, mode int) uint64 {
+	if mode == 32 {
+		return v & 0xffffffff
+	}
+
+	return v
+}
+
+// utilStackX86 will follow the immediate stores to the stack in the x86
+// code at addr, along with the stores of registers that were just loaded
+// with an immediate, passing them to the block. mode is 32 or 64. The
+// block ends at every branch, and whenever the stack or frame pointer
+// moves as the offsets no longer line up
+func utilStackX86(code []byte, addr uint64, mode int, block *stackBlock) {
+	// known are the registers loaded with an immediate, by family
+	known := make(map[int]uint64)
+
+	for off := 0; off < len(code); {
+		inst, err := x86asm.Decode(code[off:], mode)
+		if err != nil || inst.Len == 0 {
+			block.flush()
+			off++
+			continue
+		}
+
+		pc := addr + uint64(off)
+		off += inst.Len
+
+		switch inst.Op {
+		case x86asm.JMP, x86asm.CALL, x86asm.RET, x86asm.PUSH, x86asm.POP, x86asm.LEAVE, x86asm.ENTER:
+			block.flush()
+			known = make(map[int]uint64)
+			continue
+		}
+
+		if _, ok := inst.Args[0].(x86asm.Rel); ok {
+			// the conditional jumps and loops
+			block.flush()
+			known = make(map[int]uint64)
+			continue
+		}
+
+		if mem, ok := inst.Args[0].(x86asm.Mem); ok {
+			base := utilRegFamily(mem.Base)
+			if (base != 4 && base != 5) || mem.Index != 0 || mem.Segment != 0 {
+				continue
+			}
+
+			// the decoder leaves a displacement of four bytes unsigned,
+			// which would put the slots below the frame pointer apart
+			// from those reached with a displacement of a byte
+			disp := int64(int32(mem.Disp))
+
+			if inst.Op == x86asm.MOV {
+				switch src := inst.Args[1].(type) {
+				case x86asm.Imm:
+					block.store(base, disp, uint64(src), inst.MemBytes, pc)
+					continue
+				case x86asm.Reg:
+					// the high bytes such as ah are not followed
+					if val, ok := known[utilRegFamily(src)]; ok && (src < x86asm.AH || src > x86asm.BH) {
+						block.store(base, disp, val, inst.MemBytes, pc)
+						continue
+					}
+				}
+			}
+
+			block.clobber(base, disp, inst.MemBytes)
+			continue
+		}
+
+		dst, ok := inst.Args[0].(x86asm.Reg)
+		if !ok {
+			continue
+		}
+
+		family := utilRegFamily(dst)
+		if family == -1 {
+			continue
+		}
+
+		if family == 4 || family == 5 {
+			block.flush()
+			delete(known, family)
+			continue
+		}
+
+		switch {
+		case inst.Op == x86asm.MOV && dst >= x86asm.EAX:
+			// a move to a 32 bit register clears the upper half
+			if imm, ok := inst.Args[1].(x86asm.Imm); ok {
+				known[family] = utilMask(uint64(imm), 32)
+				if dst >= x86asm.RAX {
+					known[family] = uint64(imm)
+				}
+
+				continue
+			}
+		case inst.Op == x86asm.XOR && inst.Args[1] == inst.Args[0]:
+			known[family] = 0
+			continue
+		case inst.Op == x86asm.CMP || inst.Op == x86asm.TEST:
+			continue
+		}
+
+		delete(known, family)
+	}
+}
+
+// utilRegFamily will return the number of the general purpose register
+// that the register is part of, such as 0 for al, ax, eax and rax, or -1
+// if it is not a general purpose register. The stack pointer is 4 and the
+// frame pointer is 5
+func utilRegFamily(reg x86asm.Reg) int {
+	switch {
+	case reg >= x86asm.RAX && reg <= x86asm.R15:
+		return int(reg - x86asm.RAX)
+	case reg >= x86asm.EAX && reg <= x86asm.R15L:
+		return int(reg - x86asm.EAX)
+	case reg >= x86asm.AX && reg <= x86asm.R15W:
+		return int(reg - x86asm.AX)
+	case reg >= x86asm.AL && reg <= x86asm.BL:
+		return int(reg - x86asm.AL)
+	case reg >= x86asm.AH && reg <= x86asm.BH:
+		return int(reg - x86asm.AH)
+	case reg >= x86asm.SPB && reg <= x86asm.R15B:
+		return int(reg-x86asm.SPB) + 4
+	}
+
+	return -1
+}
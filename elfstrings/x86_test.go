@@ -0,0 +1,45 @@
+package elfstrings
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestXrefsX86(t *testing.T) {
+	tests := []struct {
+		name string
+		code []byte
+		mode int
+		want []uint64
+	}{
+		// lea rax, [rip+0x1000]
+		{"rip forward", []byte{0x48, 0x8d, 0x05, 0x00, 0x10, 0x00, 0x00}, 64, []uint64{0x402007}},
+		// lea rax, [rip-0xc2fa], data before the code
+		{"rip backward", []byte{0x48, 0x8d, 0x05, 0x06, 0x3d, 0xff, 0xff}, 64, []uint64{0x3f4d0d}},
+		// mov eax, [rip-0x7]
+		{"rip backward near", []byte{0x8b, 0x05, 0xf9, 0xff, 0xff, 0xff}, 64, []uint64{0x400fff}},
+		// mov eax, [0x80402000]
+		{"absolute", []byte{0x8b, 0x05, 0x00, 0x20, 0x40, 0x80}, 32, []uint64{0x80402000}},
+		// call get_pc_thunk.cx; add ecx, 0x1ff9; mov eax, [ecx-0x10]; ret
+		// in the thunk, mov ecx, [esp]; ret
+		{"got backward", []byte{
+			0xe8, 0x0d, 0x00, 0x00, 0x00,
+			0x81, 0xc1, 0xf9, 0x1f, 0x00, 0x00,
+			0x8b, 0x81, 0xf0, 0xff, 0xff, 0xff,
+			0xc3,
+			0x8b, 0x0c, 0x24,
+			0xc3,
+		}, 32, []uint64{0x1ff9, 0x402fee}},
+	}
+
+	for _, tt := range tests {
+		var got []uint64
+		utilXrefsX86(tt.code, 0x401000, tt.mode, func(from uint64, to uint64) {
+			got = append(got, to)
+		})
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: references %#x, want %#x", tt.name, got, tt.want)
+		}
+	}
+}
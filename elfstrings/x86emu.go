@@ -0,0 +1,1046 @@
+package elfstrings
+
+import (
+	"debug/elf"
+	"errors"
+	"math/bits"
+	"time"
+
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// The errors which stop the emulation of a call
+var (
+	errEmuUnsupported = errors.New("the instruction is not emulated")
+	errEmuSteps       = errors.New("the step limit was reached")
+	errEmuTimeout     = errors.New("the time limit was reached")
+	errEmuMemory      = errors.New("the memory limit was reached")
+	errEmuFetch       = errors.New("the code is not executable")
+	errEmuDivide      = errors.New("division by zero")
+)
+
+// The layout of the memory that the emulator gives the code, the stack
+// and heap are far from where any binary is loaded
+const (
+	emuPageSize  = 0x1000
+	emuMaxPages  = 4096
+	emuStackBase = 0x7ff000000000
+	emuStackSize = 0x100000
+	emuHeapBase  = 0x7fe000000000
+	emuTLSBase   = 0x7fd000000000
+	// emuReturn is the return address that the emulated call returns to,
+	// which is never mapped
+	emuReturn = 0x7fcdead00000
+	// emuMaxDepth is how deep the calls within an emulated call may go
+	emuMaxDepth = 16
+)
+
+// emuFlags are the arithmetic flags that the conditions depend on
+type emuFlags struct {
+	cf, zf, sf, of, pf bool
+}
+
+// emuMemory is the sparse memory of the emulator, the pages of the binary
+// are loaded from its segments on first use and the rest start zeroed
+type emuMemory struct {
+	r     *ElfReader
+	pages map[uint64][]byte
+	// written are the addresses written to since they were last cleared
+	written map[uint64]bool
+	// heap is where the next allocation is made
+	heap uint64
+}
+
+// x86Emulator is a small x86-64 emulator, for the integer instructions
+// that string decoding routines are made of
+type x86Emulator struct {
+	regs  [16]uint64
+	rip   uint64
+	flags emuFlags
+	mem   *emuMemory
+	// stubs are the imported functions by the address of their PLT entry
+	stubs map[uint64]string
+	// exec are the executable ranges of the binary
+	exec  []dataRange
+	cache map[uint64]x86asm.Inst
+
+	steps    uint64
+	maxSteps uint64
+	deadline time.Time
+	depth    int
+}
+
+// newEmuMemory will create the memory of the binary
+func newEmuMemory(r *ElfReader) *emuMemory {
+	return &emuMemory{
+		r:       r,
+		pages:   make(map[uint64][]byte),
+		written: make(map[uint64]bool),
+		heap:    emuHeapBase,
+	}
+}
+
+// page will return the page containing the address, loading it from the
+// segments of the binary that overlap it
+func (m *emuMemory) page(addr uint64) ([]byte, error) {
+	base := addr &^ (emuPageSize - 1)
+	if p, ok := m.pages[base]; ok {
+		return p, nil
+	}
+
+	if len(m.pages) >= emuMaxPages {
+		return nil, errEmuMemory
+	}
+
+	p := make([]byte, emuPageSize)
+	for _, prog := range m.r.ExecReader.Progs {
+		if prog.Type != elf.PT_LOAD || prog.Vaddr+prog.Filesz <= base || prog.Vaddr >= base+emuPageSize {
+			continue
+		}
+
+		start, end := base, base+emuPageSize
+		if prog.Vaddr > start {
+			start = prog.Vaddr
+		}
+
+		if prog.Vaddr+prog.Filesz < end {
+			end = prog.Vaddr + prog.Filesz
+		}
+
+		buf, err := m.r.ReaderReadAt(prog.Off+start-prog.Vaddr, end-start)
+		if err == nil {
+			copy(p[start-base:], buf)
+		}
+	}
+
+	m.pages[base] = p
+
+	return p, nil
+}
+
+// read will read the little endian value of size bytes at the address
+func (m *emuMemory) read(addr uint64, size int) (uint64, error) {
+	var v uint64
+
+	for i := 0; i < size; i++ {
+		p, err := m.page(addr + uint64(i))
+		if err != nil {
+			return 0, err
+		}
+
+		v |= uint64(p[(addr+uint64(i))&(emuPageSize-1)]) << (8 * uint(i))
+	}
+
+	return v, nil
+}
+
+// write will write the little endian value of size bytes at the address
+func (m *emuMemory) write(addr uint64, v uint64, size int) error {
+	for i := 0; i < size; i++ {
+		p, err := m.page(addr + uint64(i))
+		if err != nil {
+			return err
+		}
+
+		p[(addr+uint64(i))&(emuPageSize-1)] = byte(v >> (8 * uint(i)))
+		m.written[addr+uint64(i)] = true
+	}
+
+	return nil
+}
+
+// alloc will return a fresh zeroed region of the heap
+func (m *emuMemory) alloc(size uint64) uint64 {
+	if size > emuStackSize {
+		size = emuStackSize
+	}
+
+	addr := m.heap
+	m.heap += (size + 0xf) &^ 0xf
+
+	return addr
+}
+
+// newX86Emulator will create an emulator for the binary, the memory is
+// shared with any emulator created before it so that the decoded data
+// stays decoded
+func newX86Emulator(r *ElfReader, mem *emuMemory, stubs map[uint64]string, maxSteps uint64, deadline time.Time) *x86Emulator {
+	e := &x86Emulator{
+		mem:      mem,
+		stubs:    stubs,
+		cache:    make(map[uint64]x86asm.Inst),
+		maxSteps: maxSteps,
+		deadline: deadline,
+	}
+
+	for _, prog := range r.ExecReader.Progs {
+		if prog.Type == elf.PT_LOAD && prog.Flags&elf.PF_X != 0 {
+			e.exec = append(e.exec, dataRange{start: prog.Vaddr, end: prog.Vaddr + prog.Memsz})
+		}
+	}
+
+	e.regs[utilRegFamily(x86asm.RSP)] = emuStackBase + emuStackSize/2
+	e.regs[utilRegFamily(x86asm.RBP)] = emuStackBase + emuStackSize/2 + 0x1000
+
+	return e
+}
+
+// run will emulate from rip until the code returns to stop, or until
+// anything stops it such as an instruction that is not emulated
+func (e *x86Emulator) run(stop uint64) error {
+	for e.rip != stop {
+		if err := e.step(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetch will decode the instruction at rip
+func (e *x86Emulator) fetch() (x86asm.Inst, error) {
+	if inst, ok := e.cache[e.rip]; ok {
+		return inst, nil
+	}
+
+	executable := false
+	for _, x := range e.exec {
+		if e.rip >= x.start && e.rip < x.end {
+			executable = true
+		}
+	}
+
+	if !executable {
+		return x86asm.Inst{}, errEmuFetch
+	}
+
+	code := make([]byte, 15)
+	for i := range code {
+		v, err := e.mem.read(e.rip+uint64(i), 1)
+		if err != nil {
+			return x86asm.Inst{}, err
+		}
+
+		code[i] = byte(v)
+	}
+
+	inst, err := x86asm.Decode(code, 64)
+	if err != nil {
+		return x86asm.Inst{}, errEmuUnsupported
+	}
+
+	e.cache[e.rip] = inst
+
+	return inst, nil
+}
+
+// step will emulate a single instruction
+func (e *x86Emulator) step() error {
+	e.steps++
+	if e.steps > e.maxSteps {
+		return errEmuSteps
+	}
+
+	if e.steps%1024 == 0 && time.Now().After(e.deadline) {
+		return errEmuTimeout
+	}
+
+	inst, err := e.fetch()
+	if err != nil {
+		return err
+	}
+
+	next := e.rip + uint64(inst.Len)
+	e.rip = next
+
+	return e.exec1(&inst, next)
+}
+
+// exec1 will carry out the instruction, rip is already past it
+func (e *x86Emulator) exec1(inst *x86asm.Inst, next uint64) error {
+	size := e.size(inst)
+
+	if cond, ok := emuConditions[inst.Op]; ok {
+		return e.conditional(inst, cond(&e.flags), next)
+	}
+
+	switch inst.Op {
+	case x86asm.NOP, x86asm.PAUSE, x86asm.PREFETCHNTA, x86asm.PREFETCHT0, x86asm.PREFETCHT1, x86asm.PREFETCHT2, x86asm.PREFETCHW, x86asm.CLD:
+		return nil
+	case x86asm.MOV, x86asm.MOVZX:
+		v, err := e.get(inst, inst.Args[1])
+		if err != nil {
+			return err
+		}
+
+		return e.set(inst, inst.Args[0], v)
+	case x86asm.MOVSX, x86asm.MOVSXD:
+		v, err := e.get(inst, inst.Args[1])
+		if err != nil {
+			return err
+		}
+
+		return e.set(inst, inst.Args[0], utilSignExtend(v, uint(e.argSize(inst, inst.Args[1])*8)))
+	case x86asm.LEA:
+		mem, ok := inst.Args[1].(x86asm.Mem)
+		if !ok {
+			return errEmuUnsupported
+		}
+
+		return e.set(inst, inst.Args[0], e.address(mem))
+	case x86asm.XCHG:
+		a, err := e.get(inst, inst.Args[0])
+		if err != nil {
+			return err
+		}
+
+		b, err := e.get(inst, inst.Args[1])
+		if err != nil {
+			return err
+		}
+
+		if err := e.set(inst, inst.Args[0], b); err != nil {
+			return err
+		}
+
+		return e.set(inst, inst.Args[1], a)
+	case x86asm.ADD, x86asm.ADC, x86asm.SUB, x86asm.SBB, x86asm.CMP, x86asm.AND, x86asm.OR, x86asm.XOR, x86asm.TEST:
+		return e.arith(inst, size)
+	case x86asm.INC, x86asm.DEC, x86asm.NEG, x86asm.NOT:
+		return e.unary(inst, size)
+	case x86asm.SHL, x86asm.SHR, x86asm.SAR, x86asm.ROL, x86asm.ROR:
+		return e.shift(inst, size)
+	case x86asm.IMUL, x86asm.MUL, x86asm.DIV, x86asm.IDIV:
+		return e.multiply(inst, size)
+	case x86asm.BSWAP:
+		v, err := e.get(inst, inst.Args[0])
+		if err != nil {
+			return err
+		}
+
+		if size == 4 {
+			return e.set(inst, inst.Args[0], uint64(bits.ReverseBytes32(uint32(v))))
+		}
+
+		return e.set(inst, inst.Args[0], bits.ReverseBytes64(v))
+	case x86asm.CDQE:
+		e.regs[0] = utilSignExtend(e.regs[0]&0xffffffff, 32)
+		return nil
+	case x86asm.CWDE:
+		e.regs[0] = utilSignExtend(e.regs[0]&0xffff, 16) & 0xffffffff
+		return nil
+	case x86asm.CDQ:
+		e.regs[2] = 0
+		if e.regs[0]&0x80000000 != 0 {
+			e.regs[2] = 0xffffffff
+		}
+
+		return nil
+	case x86asm.CQO:
+		e.regs[2] = 0
+		if e.regs[0]&(1<<63) != 0 {
+			e.regs[2] = ^uint64(0)
+		}
+
+		return nil
+	case x86asm.PUSH:
+		v, err := e.get(inst, inst.Args[0])
+		if err != nil {
+			return err
+		}
+
+		return e.push(v)
+	case x86asm.POP:
+		v, err := e.pop()
+		if err != nil {
+			return err
+		}
+
+		return e.set(inst, inst.Args[0], v)
+	case x86asm.LEAVE:
+		e.regs[4] = e.regs[5]
+
+		v, err := e.pop()
+		e.regs[5] = v
+
+		return err
+	case x86asm.JMP:
+		target, err := e.target(inst, next)
+		if err != nil {
+			return err
+		}
+
+		// a jump to an import is a call to it which returns for us
+		if name, ok := e.stubs[target]; ok {
+			if err := e.stub(name); err != nil {
+				return err
+			}
+
+			return e.ret()
+		}
+
+		e.rip = target
+		return nil
+	case x86asm.CALL:
+		target, err := e.target(inst, next)
+		if err != nil {
+			return err
+		}
+
+		if name, ok := e.stubs[target]; ok {
+			return e.stub(name)
+		}
+
+		if e.depth >= emuMaxDepth {
+			return errEmuUnsupported
+		}
+
+		e.depth++
+		e.rip = target
+
+		return e.push(next)
+	case x86asm.RET:
+		e.depth--
+		return e.ret()
+	case x86asm.STOSB, x86asm.STOSW, x86asm.STOSD, x86asm.STOSQ, x86asm.MOVSB, x86asm.MOVSW, x86asm.MOVSD, x86asm.MOVSQ:
+		return e.stringOp(inst)
+	case x86asm.JRCXZ, x86asm.JECXZ, x86asm.LOOP, x86asm.LOOPE, x86asm.LOOPNE:
+		return e.loop(inst, next)
+	}
+
+	return errEmuUnsupported
+}
+
+// emuConditions are the conditions of the conditional jumps, moves and sets
+var emuConditions = map[x86asm.Op]func(f *emuFlags) bool{}
+
+func init() {
+	conds := []struct {
+		ops  []x86asm.Op
+		cond func(f *emuFlags) bool
+	}{
+		{[]x86asm.Op{x86asm.JE, x86asm.CMOVE, x86asm.SETE}, func(f *emuFlags) bool { return f.zf }},
+		{[]x86asm.Op{x86asm.JNE, x86asm.CMOVNE, x86asm.SETNE}, func(f *emuFlags) bool { return !f.zf }},
+		{[]x86asm.Op{x86asm.JB, x86asm.CMOVB, x86asm.SETB}, func(f *emuFlags) bool { return f.cf }},
+		{[]x86asm.Op{x86asm.JAE, x86asm.CMOVAE, x86asm.SETAE}, func(f *emuFlags) bool { return !f.cf }},
+		{[]x86asm.Op{x86asm.JBE, x86asm.CMOVBE, x86asm.SETBE}, func(f *emuFlags) bool { return f.cf || f.zf }},
+		{[]x86asm.Op{x86asm.JA, x86asm.CMOVA, x86asm.SETA}, func(f *emuFlags) bool { return !f.cf && !f.zf }},
+		{[]x86asm.Op{x86asm.JL, x86asm.CMOVL, x86asm.SETL}, func(f *emuFlags) bool { return f.sf != f.of }},
+		{[]x86asm.Op{x86asm.JGE, x86asm.CMOVGE, x86asm.SETGE}, func(f *emuFlags) bool { return f.sf == f.of }},
+		{[]x86asm.Op{x86asm.JLE, x86asm.CMOVLE, x86asm.SETLE}, func(f *emuFlags) bool { return f.zf || f.sf != f.of }},
+		{[]x86asm.Op{x86asm.JG, x86asm.CMOVG, x86asm.SETG}, func(f *emuFlags) bool { return !f.zf && f.sf == f.of }},
+		{[]x86asm.Op{x86asm.JS, x86asm.CMOVS, x86asm.SETS}, func(f *emuFlags) bool { return f.sf }},
+		{[]x86asm.Op{x86asm.JNS, x86asm.CMOVNS, x86asm.SETNS}, func(f *emuFlags) bool { return !f.sf }},
+		{[]x86asm.Op{x86asm.JO, x86asm.CMOVO, x86asm.SETO}, func(f *emuFlags) bool { return f.of }},
+		{[]x86asm.Op{x86asm.JNO, x86asm.CMOVNO, x86asm.SETNO}, func(f *emuFlags) bool { return !f.of }},
+		{[]x86asm.Op{x86asm.JP, x86asm.CMOVP, x86asm.SETP}, func(f *emuFlags) bool { return f.pf }},
+		{[]x86asm.Op{x86asm.JNP, x86asm.CMOVNP, x86asm.SETNP}, func(f *emuFlags) bool { return !f.pf }},
+	}
+
+	for _, c := range conds {
+		for _, op := range c.ops {
+			emuConditions[op] = c.cond
+		}
+	}
+}
+
+// conditional will carry out a conditional jump, move or set
+func (e *x86Emulator) conditional(inst *x86asm.Inst, taken bool, next uint64) error {
+	switch inst.Args[0].(type) {
+	case x86asm.Rel:
+		if taken {
+			target, err := e.target(inst, next)
+			if err != nil {
+				return err
+			}
+
+			e.rip = target
+		}
+
+		return nil
+	}
+
+	if inst.Args[1] == nil {
+		// setcc
+		v := uint64(0)
+		if taken {
+			v = 1
+		}
+
+		return e.set(inst, inst.Args[0], v)
+	}
+
+	// cmovcc, which still clears the upper half of a 32 bit register
+	v, err := e.get(inst, inst.Args[0])
+	if taken {
+		v, err = e.get(inst, inst.Args[1])
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return e.set(inst, inst.Args[0], v)
+}
+
+// arith will carry out the two operand arithmetic and logic
+func (e *x86Emulator) arith(inst *x86asm.Inst, size int) error {
+	a, err := e.get(inst, inst.Args[0])
+	if err != nil {
+		return err
+	}
+
+	b, err := e.get(inst, inst.Args[1])
+	if err != nil {
+		return err
+	}
+
+	b &= utilSizeMask(size)
+
+	var v uint64
+	switch inst.Op {
+	case x86asm.ADD, x86asm.ADC:
+		carry := uint64(0)
+		if inst.Op == x86asm.ADC && e.flags.cf {
+			carry = 1
+		}
+
+		v = (a + b + carry) & utilSizeMask(size)
+		e.flags.cf = v < a || (carry == 1 && v == a)
+		e.flags.of = utilSignBit(a, size) == utilSignBit(b, size) && utilSignBit(v, size) != utilSignBit(a, size)
+	case x86asm.SUB, x86asm.SBB, x86asm.CMP:
+		borrow := uint64(0)
+		if inst.Op == x86asm.SBB && e.flags.cf {
+			borrow = 1
+		}
+
+		v = (a - b - borrow) & utilSizeMask(size)
+		e.flags.cf = a < b+borrow || (borrow == 1 && b == utilSizeMask(size))
+		e.flags.of = utilSignBit(a, size) != utilSignBit(b, size) && utilSignBit(v, size) != utilSignBit(a, size)
+	case x86asm.AND, x86asm.TEST:
+		v = a & b
+		e.flags.cf, e.flags.of = false, false
+	case x86asm.OR:
+		v = a | b
+		e.flags.cf, e.flags.of = false, false
+	case x86asm.XOR:
+		v = a ^ b
+		e.flags.cf, e.flags.of = false, false
+	}
+
+	e.result(v, size)
+
+	if inst.Op == x86asm.CMP || inst.Op == x86asm.TEST {
+		return nil
+	}
+
+	return e.set(inst, inst.Args[0], v)
+}
+
+// unary will carry out the single operand arithmetic
+func (e *x86Emulator) unary(inst *x86asm.Inst, size int) error {
+	a, err := e.get(inst, inst.Args[0])
+	if err != nil {
+		return err
+	}
+
+	var v uint64
+	switch inst.Op {
+	case x86asm.INC:
+		v = (a + 1) & utilSizeMask(size)
+		e.flags.of = v == 1<<(uint(size)*8-1)
+		e.result(v, size)
+	case x86asm.DEC:
+		v = (a - 1) & utilSizeMask(size)
+		e.flags.of = a == 1<<(uint(size)*8-1)
+		e.result(v, size)
+	case x86asm.NEG:
+		v = (0 - a) & utilSizeMask(size)
+		e.flags.cf = a != 0
+		e.flags.of = a == 1<<(uint(size)*8-1)
+		e.result(v, size)
+	case x86asm.NOT:
+		v = ^a & utilSizeMask(size)
+	}
+
+	return e.set(inst, inst.Args[0], v)
+}
+
+// shift will carry out the shifts and rotates
+func (e *x86Emulator) shift(inst *x86asm.Inst, size int) error {
+	a, err := e.get(inst, inst.Args[0])
+	if err != nil {
+		return err
+	}
+
+	n := uint64(1)
+	if inst.Args[1] != nil {
+		if n, err = e.get(inst, inst.Args[1]); err != nil {
+			return err
+		}
+	}
+
+	width := uint(size) * 8
+	if width == 64 {
+		n &= 63
+	} else {
+		n &= 31
+	}
+
+	if n == 0 {
+		return nil
+	}
+
+	var v uint64
+	switch inst.Op {
+	case x86asm.SHL:
+		v = (a << n) & utilSizeMask(size)
+		e.flags.cf = n <= uint64(width) && (a>>(uint64(width)-n))&1 != 0
+		e.result(v, size)
+	case x86asm.SHR:
+		v = a >> n
+		e.flags.cf = (a>>(n-1))&1 != 0
+		e.result(v, size)
+	case x86asm.SAR:
+		v = uint64(int64(utilSignExtend(a, width))>>n) & utilSizeMask(size)
+		e.flags.cf = (a>>(n-1))&1 != 0
+		e.result(v, size)
+	case x86asm.ROL:
+		n %= uint64(width)
+		v = (a<<n | a>>(uint64(width)-n)) & utilSizeMask(size)
+		e.flags.cf = v&1 != 0
+	case x86asm.ROR:
+		n %= uint64(width)
+		v = (a>>n | a<<(uint64(width)-n)) & utilSizeMask(size)
+		e.flags.cf = utilSignBit(v, size)
+	}
+
+	return e.set(inst, inst.Args[0], v)
+}
+
+// multiply will carry out the multiplications and divisions, the one
+// operand forms use rdx:rax
+func (e *x86Emulator) multiply(inst *x86asm.Inst, size int) error {
+	mask := utilSizeMask(size)
+	width := uint(size) * 8
+
+	if inst.Op == x86asm.IMUL && inst.Args[1] != nil {
+		a, err := e.get(inst, inst.Args[0])
+		if err != nil {
+			return err
+		}
+
+		b, err := e.get(inst, inst.Args[1])
+		if err != nil {
+			return err
+		}
+
+		if inst.Args[2] != nil {
+			a = b
+			if b, err = e.get(inst, inst.Args[2]); err != nil {
+				return err
+			}
+		}
+
+		return e.set(inst, inst.Args[0], (a*b)&mask)
+	}
+
+	src, err := e.get(inst, inst.Args[0])
+	if err != nil {
+		return err
+	}
+
+	if size == 1 {
+		return errEmuUnsupported
+	}
+
+	ax, dx := e.regs[0]&mask, e.regs[2]&mask
+
+	var lo, hi uint64
+	switch inst.Op {
+	case x86asm.MUL, x86asm.IMUL:
+		if size == 8 {
+			hi, lo = bits.Mul64(ax, src)
+			if inst.Op == x86asm.IMUL {
+				// the signed high half corrects for the negative operands
+				if int64(ax) < 0 {
+					hi -= src
+				}
+
+				if int64(src) < 0 {
+					hi -= ax
+				}
+			}
+		} else {
+			full := ax * src
+			if inst.Op == x86asm.IMUL {
+				full = uint64(int64(utilSignExtend(ax, width)) * int64(utilSignExtend(src, width)))
+			}
+
+			lo, hi = full&mask, (full>>width)&mask
+		}
+	case x86asm.DIV:
+		if src == 0 || (size == 8 && dx >= src) {
+			return errEmuDivide
+		}
+
+		if size == 8 {
+			lo, hi = bits.Div64(dx, ax, src)
+		} else {
+			n := dx<<width | ax
+			if n/src > mask {
+				return errEmuDivide
+			}
+
+			lo, hi = n/src, n%src
+		}
+	case x86asm.IDIV:
+		if src == 0 || size == 8 {
+			return errEmuDivide
+		}
+
+		n := int64(utilSignExtend(dx<<width|ax, 2*width))
+		d := int64(utilSignExtend(src, width))
+
+		lo, hi = uint64(n/d)&mask, uint64(n%d)&mask
+	}
+
+	e.setSized(0, lo, size)
+	e.setSized(2, hi, size)
+
+	return nil
+}
+
+// stringOp will carry out the string stores and moves, with a rep prefix
+// they repeat rcx times
+func (e *x86Emulator) stringOp(inst *x86asm.Inst) error {
+	size := 1
+	switch inst.Op {
+	case x86asm.STOSW, x86asm.MOVSW:
+		size = 2
+	case x86asm.STOSD, x86asm.MOVSD:
+		size = 4
+	case x86asm.STOSQ, x86asm.MOVSQ:
+		size = 8
+	}
+
+	count := uint64(1)
+	rep := false
+	for _, p := range inst.Prefix {
+		if p&0xff == x86asm.PrefixREP {
+			rep = true
+		}
+	}
+
+	if rep {
+		count = e.regs[1]
+	}
+
+	for ; count > 0; count-- {
+		e.steps++
+		if e.steps > e.maxSteps {
+			return errEmuSteps
+		}
+
+		v := e.regs[0]
+		if inst.Op == x86asm.MOVSB || inst.Op == x86asm.MOVSW || inst.Op == x86asm.MOVSD || inst.Op == x86asm.MOVSQ {
+			var err error
+			if v, err = e.mem.read(e.regs[6], size); err != nil {
+				return err
+			}
+
+			e.regs[6] += uint64(size)
+		}
+
+		if err := e.mem.write(e.regs[7], v, size); err != nil {
+			return err
+		}
+
+		e.regs[7] += uint64(size)
+		if rep {
+			e.regs[1]--
+		}
+	}
+
+	return nil
+}
+
+// loop will carry out the jumps which depend on rcx
+func (e *x86Emulator) loop(inst *x86asm.Inst, next uint64) error {
+	taken := false
+	switch inst.Op {
+	case x86asm.JRCXZ:
+		taken = e.regs[1] == 0
+	case x86asm.JECXZ:
+		taken = e.regs[1]&0xffffffff == 0
+	default:
+		e.regs[1]--
+		taken = e.regs[1] != 0
+		if inst.Op == x86asm.LOOPE {
+			taken = taken && e.flags.zf
+		} else if inst.Op == x86asm.LOOPNE {
+			taken = taken && !e.flags.zf
+		}
+	}
+
+	if !taken {
+		return nil
+	}
+
+	target, err := e.target(inst, next)
+	if err != nil {
+		return err
+	}
+
+	e.rip = target
+
+	return nil
+}
+
+// stub will stand in for an imported function, only those which are
+// needed by decoding routines do anything, the rest return zero
+func (e *x86Emulator) stub(name string) error {
+	rdi, rsi, rdx := e.regs[7], e.regs[6], e.regs[2]
+
+	switch name {
+	case "malloc", "_Znwm", "_Znam":
+		e.regs[0] = e.mem.alloc(rdi)
+	case "calloc":
+		e.regs[0] = e.mem.alloc(rdi * rsi)
+	case "strlen":
+		n := uint64(0)
+		for ; n < emuStackSize; n++ {
+			b, err := e.mem.read(rdi+n, 1)
+			if err != nil {
+				return err
+			}
+
+			if b == 0 {
+				break
+			}
+		}
+
+		e.regs[0] = n
+	case "memcpy", "memmove", "memset":
+		if rdx > emuStackSize {
+			return errEmuMemory
+		}
+
+		for i := uint64(0); i < rdx; i++ {
+			b := rsi
+			if name != "memset" {
+				var err error
+				if b, err = e.mem.read(rsi+i, 1); err != nil {
+					return err
+				}
+			}
+
+			if err := e.mem.write(rdi+i, b, 1); err != nil {
+				return err
+			}
+		}
+
+		e.regs[0] = rdi
+	default:
+		e.regs[0] = 0
+	}
+
+	return nil
+}
+
+// target will find where a jump or call goes
+func (e *x86Emulator) target(inst *x86asm.Inst, next uint64) (uint64, error) {
+	if rel, ok := inst.Args[0].(x86asm.Rel); ok {
+		return next + uint64(int64(rel)), nil
+	}
+
+	return e.get(inst, inst.Args[0])
+}
+
+// push will push the value onto the stack
+func (e *x86Emulator) push(v uint64) error {
+	e.regs[4] -= 8
+	return e.mem.write(e.regs[4], v, 8)
+}
+
+// pop will pop a value from the stack
+func (e *x86Emulator) pop() (uint64, error) {
+	v, err := e.mem.read(e.regs[4], 8)
+	e.regs[4] += 8
+
+	return v, err
+}
+
+// ret will return to the address on the stack
+func (e *x86Emulator) ret() error {
+	target, err := e.pop()
+	e.rip = target
+
+	return err
+}
+
+// result will set the zero, sign and parity flags from the result
+func (e *x86Emulator) result(v uint64, size int) {
+	e.flags.zf = v&utilSizeMask(size) == 0
+	e.flags.sf = utilSignBit(v, size)
+	e.flags.pf = bits.OnesCount8(uint8(v))%2 == 0
+}
+
+// size will return the operand size of the instruction in bytes
+func (e *x86Emulator) size(inst *x86asm.Inst) int {
+	if inst.Args[0] == nil {
+		return inst.DataSize / 8
+	}
+
+	return e.argSize(inst, inst.Args[0])
+}
+
+// argSize will return the size of the argument in bytes
+func (e *x86Emulator) argSize(inst *x86asm.Inst, arg x86asm.Arg) int {
+	switch a := arg.(type) {
+	case x86asm.Reg:
+		switch {
+		case a >= x86asm.AL && a <= x86asm.R15B:
+			return 1
+		case a >= x86asm.AX && a <= x86asm.R15W:
+			return 2
+		case a >= x86asm.EAX && a <= x86asm.R15L:
+			return 4
+		}
+
+		return 8
+	case x86asm.Mem:
+		return inst.MemBytes
+	}
+
+	return inst.DataSize / 8
+}
+
+// address will compute the address of the memory operand
+func (e *x86Emulator) address(mem x86asm.Mem) uint64 {
+	var addr uint64
+
+	switch {
+	case mem.Base == x86asm.RIP:
+		addr = e.rip
+	case mem.Base != 0:
+		addr, _ = e.reg(mem.Base)
+	}
+
+	if mem.Index != 0 {
+		index, _ := e.reg(mem.Index)
+		addr += index * uint64(mem.Scale)
+	}
+
+	// the thread pointer is only read for the stack protector
+	if mem.Segment == x86asm.FS || mem.Segment == x86asm.GS {
+		addr += emuTLSBase
+	}
+
+	// the decoder leaves a displacement of four bytes unsigned, only the
+	// absolute offsets of the moffs forms are wider than that
+	disp := mem.Disp
+	if disp == int64(uint32(disp)) {
+		disp = int64(int32(disp))
+	}
+
+	return addr + uint64(disp)
+}
+
+// get will read the value of the argument
+func (e *x86Emulator) get(inst *x86asm.Inst, arg x86asm.Arg) (uint64, error) {
+	switch a := arg.(type) {
+	case x86asm.Reg:
+		v, ok := e.reg(a)
+		if !ok {
+			return 0, errEmuUnsupported
+		}
+
+		return v, nil
+	case x86asm.Mem:
+		if inst.MemBytes > 8 {
+			return 0, errEmuUnsupported
+		}
+
+		return e.mem.read(e.address(a), inst.MemBytes)
+	case x86asm.Imm:
+		return uint64(a), nil
+	}
+
+	return 0, errEmuUnsupported
+}
+
+// set will write the value to the argument
+func (e *x86Emulator) set(inst *x86asm.Inst, arg x86asm.Arg, v uint64) error {
+	switch a := arg.(type) {
+	case x86asm.Reg:
+		family := utilRegFamily(a)
+		if family == -1 {
+			return errEmuUnsupported
+		}
+
+		if a >= x86asm.AH && a <= x86asm.BH {
+			e.regs[family] = e.regs[family]&^0xff00 | (v&0xff)<<8
+			return nil
+		}
+
+		e.setSized(family, v, e.argSize(inst, a))
+
+		return nil
+	case x86asm.Mem:
+		if inst.MemBytes > 8 {
+			return errEmuUnsupported
+		}
+
+		return e.mem.write(e.address(a), v, inst.MemBytes)
+	}
+
+	return errEmuUnsupported
+}
+
+// setSized will write the value to the register family, a write to the
+// 32 bit register clears the upper half while the smaller ones merge
+func (e *x86Emulator) setSized(family int, v uint64, size int) {
+	switch size {
+	case 1, 2:
+		mask := utilSizeMask(size)
+		e.regs[family] = e.regs[family]&^mask | v&mask
+	case 4:
+		e.regs[family] = v & 0xffffffff
+	default:
+		e.regs[family] = v
+	}
+}
+
+// reg will read the register
+func (e *x86Emulator) reg(reg x86asm.Reg) (uint64, bool) {
+	if reg == x86asm.RIP {
+		return e.rip, true
+	}
+
+	family := utilRegFamily(reg)
+	if family == -1 {
+		return 0, false
+	}
+
+	v := e.regs[family]
+	if reg >= x86asm.AH && reg <= x86asm.BH {
+		return (v >> 8) & 0xff, true
+	}
+
+	return v & utilSizeMask(e.argSize(nil, reg)), true
+}
+
+// utilSizeMask will return the mask of an operand of size bytes
+func utilSizeMask(size int) uint64 {
+	if size >= 8 {
+		return ^uint64(0)
+	}
+
+	return 1<<(uint(size)*8) - 1
+}
+
+// utilSignBit will check the sign bit of an operand of size bytes
+func utilSignBit(v uint64, size int) bool {
+	return v&(1<<(uint(size)*8-1)) != 0
+}
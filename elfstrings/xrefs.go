@@ -0,0 +1,302 @@
+package elfstrings
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"sort"
+)
+
+// Xref is an instruction which references a string
+type Xref struct {
+	// Address is the virtual address of the instruction
+	Address uint64
+	// Function is the name of the function containing the
+	// instruction, empty when there is no symbol for it
+	Function string
+}
+
+// xrefIndex is every address referenced by the code of the binary
+type xrefIndex struct {
+	// targets are the referenced addresses in ascending order
+	targets []uint64
+	// from are the instructions referencing each of the targets
+	from map[uint64][]uint64
+}
+
+// dataRange is an allocated section that code may reference data in
+type dataRange struct {
+	start uint64
+	end   uint64
+}
+
+// ReaderXrefs will walk the executable sections of the binary, decoding
+// each instruction, and return every address that the code references
+// along with the instructions that reference it. Only the references into
+// allocated sections which are not executable are kept, as those are where
+// strings are. Nothing is returned for machines that cannot be decoded
+func (r *ElfReader) ReaderXrefs() map[uint64][]uint64 {
+	return r.readerXrefIndex().from
+}
+
+// ReaderXrefsTo will return the instructions which reference any byte
+// of the string at addr, as code may reference the tail of a string that
+// has been merged with a longer one
+func (r *ElfReader) ReaderXrefsTo(addr uint64, size uint64) []Xref {
+	var xrefs []Xref
+
+	if addr == 0 {
+		return nil
+	}
+
+	if size == 0 {
+		size = 1
+	}
+
+	index := r.readerXrefIndex()
+
+	i := sort.Search(len(index.targets), func(i int) bool {
+		return index.targets[i] >= addr
+	})
+
+	for ; i < len(index.targets) && index.targets[i] < addr+size; i++ {
+		for _, from := range index.from[index.targets[i]] {
+			xrefs = append(xrefs, Xref{Address: from, Function: r.ReaderFunctionAt(from)})
+		}
+	}
+
+	sort.Slice(xrefs, func(i, j int) bool {
+		return xrefs[i].Address < xrefs[j].Address
+	})
+
+	return xrefs
+}
+
+// readerAttachXrefs will fill in the instructions referencing each record
+func (r *ElfReader) readerAttachXrefs(records []StringRecord) {
+	for i := range records {
+		rec := &records[i]
+		rec.Xrefs = r.ReaderXrefsTo(rec.Address, uint64(len(rec.Raw)))
+	}
+}
+
+// readerXrefIndex will build the index of references on first use
+func (r *ElfReader) readerXrefIndex() *xrefIndex {
+	if r.xrefs != nil {
+		return r.xrefs
+	}
+
+	index := &xrefIndex{from: make(map[uint64][]uint64)}
+	r.xrefs = index
+
+	if r.ExecReader.Type == elf.ET_REL {
+		return index
+	}
+
+	var data []dataRange
+	for _, s := range r.ExecReader.Sections {
+		if s.Flags&elf.SHF_ALLOC != 0 && s.Flags&elf.SHF_EXECINSTR == 0 && s.Addr != 0 {
+			data = append(data, dataRange{start: s.Addr, end: s.Addr + s.Size})
+		}
+	}
+
+	add := func(from uint64, to uint64) {
+		for _, d := range data {
+			if to >= d.start && to < d.end {
+				// an instruction may reach the same address twice,
+				// such as a literal load of a pointer to itself
+				prev := index.from[to]
+				if len(prev) == 0 || prev[len(prev)-1] != from {
+					index.from[to] = append(prev, from)
+				}
+
+				return
+			}
+		}
+	}
+
+	mem := r.readerMemory()
+
+	for _, s := range r.ExecReader.Sections {
+		if s.Type != elf.SHT_PROGBITS || s.Flags&elf.SHF_EXECINSTR == 0 {
+			continue
+		}
+
+		code, err := r.ReaderReadAt(s.Offset, s.Size)
+		if err != nil {
+			continue
+		}
+
+		switch r.ExecReader.Machine {
+		case elf.EM_X86_64:
+			utilXrefsX86(code, s.Addr, 64, add)
+		case elf.EM_386:
+			utilXrefsX86(code, s.Addr, 32, add)
+		case elf.EM_AARCH64:
+			utilXrefsARM64(code, s.Addr, mem, add)
+		case elf.EM_ARM:
+			for _, region := range r.readerARMRegions(s) {
+				start, end := region.start-s.Addr, region.end-s.Addr
+				utilXrefsARM(code[start:end], region.start, region.thumb, mem, add)
+			}
+		case elf.EM_RISCV:
+			utilXrefsRISCV(code, s.Addr, r.readerRISCVGP(), mem, add)
+		case elf.EM_MIPS, elf.EM_MIPS_RS3_LE:
+			utilXrefsMIPS(code, s.Addr, r.readerMIPSGP(), mem, add)
+		}
+	}
+
+	for target := range index.from {
+		index.targets = append(index.targets, target)
+	}
+
+	sort.Slice(index.targets, func(i, j int) bool {
+		return index.targets[i] < index.targets[j]
+	})
+
+	return index
+}
+
+// pairWindow is how many bytes of code the first half of an address is
+// remembered for, compilers keep the two halves of a pair close together
+const pairWindow = 256
+
+// pairs tracks the registers which have been loaded with the first half
+// of an address, such as the page from an adrp or the upper bits from a lui
+type pairs struct {
+	val [32]uint64
+	at  [32]uint64
+	ok  [32]bool
+}
+
+// set will remember the value of the register, loaded at pc
+func (p *pairs) set(reg uint32, val uint64, pc uint64) {
+	p.val[reg], p.at[reg], p.ok[reg] = val, pc, true
+}
+
+// get will return the value of the register if it is still known at pc
+func (p *pairs) get(reg uint32, pc uint64) (uint64, bool) {
+	if !p.ok[reg] || pc < p.at[reg] || pc-p.at[reg] > pairWindow {
+		return 0, false
+	}
+
+	return p.val[reg], true
+}
+
+// clear will forget the value of the register
+func (p *pairs) clear(reg uint32) {
+	p.ok[reg] = false
+}
+
+// memory reads the words that instructions load from the binary, so that
+// the pointers in literal pools and the GOT can be followed
+type memory struct {
+	r     *ElfReader
+	order binary.ByteOrder
+	// relocs are the addends of the relative relocations, which are what
+	// the pointers hold once loaded in position independent binaries
+	relocs map[uint64]uint64
+	data   map[*elf.Section][]byte
+}
+
+// readerMemory will create the memory of the binary
+func (r *ElfReader) readerMemory() *memory {
+	m := &memory{
+		r:      r,
+		order:  r.ExecReader.ByteOrder,
+		relocs: make(map[uint64]uint64),
+		data:   make(map[*elf.Section][]byte),
+	}
+
+	size := uint64(4)
+	if r.ExecReader.Class == elf.ELFCLASS64 {
+		size = 8
+	}
+
+	for _, s := range r.ExecReader.Sections {
+		if s.Type != elf.SHT_RELA {
+			continue
+		}
+
+		buf, err := r.ReaderReadAt(s.Offset, s.Size)
+		if err != nil {
+			continue
+		}
+
+		// relative relocations are those without a symbol
+		for i := uint64(0); i+size*3 <= uint64(len(buf)); i += size * 3 {
+			if size == 8 {
+				if m.order.Uint64(buf[i+8:])>>32 == 0 {
+					m.relocs[m.order.Uint64(buf[i:])] = m.order.Uint64(buf[i+16:])
+				}
+			} else if m.order.Uint32(buf[i+4:])>>8 == 0 {
+				m.relocs[uint64(m.order.Uint32(buf[i:]))] = uint64(m.order.Uint32(buf[i+8:]))
+			}
+		}
+	}
+
+	return m
+}
+
+// load will read the word of the given size in bytes at the address
+func (m *memory) load(addr uint64, size int) (uint64, bool) {
+	if v, ok := m.relocs[addr]; ok {
+		return v, true
+	}
+
+	buf := m.at(addr)
+	if len(buf) < size {
+		return 0, false
+	}
+
+	if size == 8 {
+		return m.order.Uint64(buf), true
+	}
+
+	return uint64(m.order.Uint32(buf)), true
+}
+
+// loadString will read the NUL terminated text at the address, false is
+// returned when it is not loaded, not terminated within max bytes or not
+// printable
+func (m *memory) loadString(addr uint64, max int) (string, bool) {
+	buf := m.at(addr)
+	if len(buf) > max+1 {
+		buf = buf[:max+1]
+	}
+
+	end := bytes.IndexByte(buf, 0)
+	if end <= 0 || !utilIsPrefixedText(buf[:end]) {
+		return "", false
+	}
+
+	return string(buf[:end]), true
+}
+
+// at will return the contents of the section which holds the address from
+// the address on, nil when it is not loaded from the file
+func (m *memory) at(addr uint64) []byte {
+	for _, s := range m.r.ExecReader.Sections {
+		if s.Type == elf.SHT_NOBITS || s.Flags&elf.SHF_ALLOC == 0 {
+			continue
+		}
+
+		if addr < s.Addr || addr-s.Addr >= s.Size {
+			continue
+		}
+
+		buf, ok := m.data[s]
+		if !ok {
+			buf, _ = m.r.ReaderReadAt(s.Offset, s.Size)
+			m.data[s] = buf
+		}
+
+		if off := addr - s.Addr; off < uint64(len(buf)) {
+			return buf[off:]
+		}
+
+		return nil
+	}
+
+	return nil
+}
@@ -1,189 +1,829 @@
-package main
-
-import (
-	"flag"
-	"fmt"
-	"log"
-	"os"
-	"strings"
-
-	humanize "github.com/dustin/go-humanize"
-	"github.com/fatih/color"
-	"github.com/shawnsmithdev/zermelo"
-)
-
-var (
-	demangleOpt = flag.Bool("demangle", false, "demangle C++ symbols into their original source identifiers, prettify found C++ symbols (optional)")
-	hexOpt      = flag.Bool("hex", false, "output the strings as a hexadecimal literal (optional)")
-	offsetOpt   = flag.Bool("offset", true, "show the offset of the string in the section (default, recommended)")
-	binaryOpt   = flag.String("binary", "", "the path to the ELF you wish to parse")
-	formatOpt   = flag.String("output-format", "plain", "the format you want to output as (optional, plain/json/xml)")
-	outputOpt   = flag.String("output-file", "", "the path of the output file that you want to output to (optional)")
-	maxOpt      = flag.Uint64("max-count", 0, "the maximum amount of strings that you wish to be output (optional)")
-	libOpt      = flag.Bool("libs", false, "show the linked libraries in the binary (optional)")
-	infoOpt     = flag.Bool("no-info", false, "don't show any information about the binary")
-	minOpt      = flag.Uint64("min", 0, "the minimum length of the string")
-	colorOpt    = flag.Bool("no-color", false, "disable color output in the results")
-	trimOpt     = flag.Bool("no-trim", false, "disable triming whitespace and trailing newlines")
-	humanOpt    = flag.Bool("no-human", false, "don't validate that its a human readable string, this could increase the amount of junk.")
-)
-
-// ReadSection is the main logic here
-// it combines all of the modules, etc.
-func ReadSection(reader *ElfReader, section string) {
-	var err error
-	var writer *OutWriter
-	var count uint64
-
-	sect := reader.ReaderParseSection(section)
-
-	if *outputOpt != "" {
-		writer, err = NewOutWriter(*outputOpt, OutParseTypeStr(*formatOpt))
-		if err != nil {
-			log.Fatal(err.Error())
-		}
-	}
-
-	if sect != nil {
-		nodes := reader.ReaderParseStrings(sect)
-
-		// Since maps in Go are unsorted, we're going to have to make
-		// a slice of keys, then iterate over this and just use the index
-		// from the map.
-		keys := make([]uint64, len(nodes))
-		for k, _ := range nodes {
-			keys = append(keys, k)
-		}
-
-		err = zermelo.Sort(keys)
-		if err != nil {
-			return
-		}
-
-		keys = UtilUniqueSlice(keys)
-
-		for _, off := range keys {
-			if *maxOpt != 0 {
-				if count == *maxOpt {
-					break
-				}
-			}
-
-			str := string(nodes[off])
-			if uint64(len(str)) < *minOpt {
-				continue
-			}
-
-			if !*humanOpt {
-				if !UtilIsNice(str) {
-					continue
-				}
-			}
-
-			str = strings.TrimSpace(str)
-
-			if !*trimOpt {
-				bad := []string{"\n", "\r"}
-				for _, char := range bad {
-					str = strings.Replace(str, char, "", -1)
-				}
-			}
-
-			if *demangleOpt {
-				demangled, err := UtilDemangle(&str)
-				if err == nil {
-					str = demangled
-				}
-			}
-
-			if *hexOpt {
-				str = UtilConvHex(str)
-			}
-
-			if *offsetOpt {
-				if os.Getenv("NO_COLOR") != "" || *colorOpt {
-					fmt.Printf("[%s+%#x]: %s\n",
-						section,
-						off,
-						str)
-				} else {
-					fmt.Printf("[%s%s]: %s\n",
-						color.BlueString(section),
-						color.GreenString("+%#x", off),
-						str)
-				}
-			} else {
-				fmt.Println(str)
-			}
-
-			if writer != nil {
-				writer.WriteResult(str, off)
-			}
-
-			count++
-		}
-	}
-}
-
-// ReadBasic will read the basic information
-// about the ELF
-func ReadBasic(reader *ElfReader) {
-	stat, err := reader.File.Stat()
-	if err != nil {
-		return
-	}
-
-	size := humanize.Bytes(uint64(stat.Size()))
-
-	fmt.Printf(
-		"[+] Size: %s\n"+
-			"[+] Arch: %s\n"+
-			"[+] Entry point: %#x\n"+
-			"[+] Class: %s\n"+
-			"[+] Byte order: %s\n",
-		size,
-		UtilConvertMachine(reader.ExecReader.Machine),
-		reader.ExecReader.Entry,
-		reader.ExecReader.Class.String(),
-		reader.ExecReader.ByteOrder.String(),
-	)
-
-	if *libOpt {
-		fmt.Println("[+] Libraries:")
-		libs, err := reader.ExecReader.ImportedLibraries()
-		if err == nil {
-			for _, lib := range libs {
-				fmt.Printf("\t [!] %s\n", lib)
-			}
-		}
-	}
-
-	fmt.Println(strings.Repeat("-", 16))
-}
-
-// main is the entrypoint for this program
-func main() {
-	flag.Parse()
-
-	if *binaryOpt == "" {
-		flag.PrintDefaults()
-		return
-	}
-
-	r, err := NewELFReader(*binaryOpt)
-	if err != nil {
-		log.Fatal(err.Error())
-	}
-
-	defer r.Close()
-
-	ReadBasic(r)
-
-	sections := []string{".dynstr", ".rodata", ".rdata",
-		".strtab", ".comment", ".note",
-		".stab", ".stabstr", ".note.ABI-tag", ".note.gnu.build-id"}
-
-	for _, section := range sections {
-		ReadSection(r, section)
-	}
-}
+package main
+
+import (
+	"debug/elf"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/LloydLabs/elf-strings/elfstrings"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+)
+
+var (
+	demangleOpt = flag.Bool("demangle", false, "demangle the C++, Rust, Swift, D, Java, Go and OCaml symbols, also those within longer strings, into their original source identifiers, and show the language of each (optional)")
+	noParamsOpt = flag.Bool("no-params", false, "leave out the parameters of the demangled functions, used with -demangle (optional)")
+	noTmplOpt   = flag.Bool("no-template-args", false, "leave out the arguments of the demangled templates and generics, used with -demangle (optional)")
+	simpleOpt   = flag.Bool("simplify-std", false, "write the types of the standard libraries as they are in the source, such as std::string, used with -demangle (optional)")
+	hexOpt      = flag.Bool("hex", false, "output the strings as a hexadecimal literal (optional)")
+	offsetOpt   = flag.Bool("offset", true, "show the offset of the string in the section (default, recommended)")
+	binaryOpt   = flag.String("binary", "", "the path to the ELF you wish to parse")
+	formatOpt   = flag.String("output-format", "plain", "the format you want to output as (optional, plain/json/xml)")
+	outputOpt   = flag.String("output-file", "", "the path of the output file that you want to output to (optional)")
+	maxOpt      = flag.Uint64("max-count", 0, "the maximum amount of strings that you wish to be output (optional)")
+	libOpt      = flag.Bool("libs", false, "show the linked libraries in the binary (optional)")
+	infoOpt     = flag.Bool("no-info", false, "don't show any information about the binary")
+	minOpt      = flag.Uint64("min", 0, "the minimum length of the string")
+	colorOpt    = flag.Bool("no-color", false, "disable color output in the results")
+	trimOpt     = flag.Bool("no-trim", false, "disable triming whitespace and trailing newlines")
+	humanOpt    = flag.Bool("no-human", false, "don't validate that its a human readable string, this could increase the amount of junk.")
+	sectionsOpt = flag.String("sections", "default", "how the sections to scan are selected (optional, default/all/match/flags/type)")
+	matchOpt    = flag.String("section-match", "", "comma separated globs of section names to scan, used with -sections=match (optional)")
+	regexOpt    = flag.String("section-regex", "", "regular expression of section names to scan, used with -sections=match (optional)")
+	flagsOpt    = flag.String("section-flags", "strings", "comma separated section flags that must be set, used with -sections=flags (optional, write/alloc/exec/merge/strings/tls/noexec)")
+	typeOpt     = flag.String("section-type", "progbits,strtab,note", "comma separated section types to scan, used with -sections=type (optional)")
+	skippedOpt  = flag.Bool("show-skipped", false, "show the sections that were skipped and why (optional)")
+	addressOpt  = flag.Bool("address", false, "show the virtual address, file offset and segment of the string (optional)")
+	scanOpt     = flag.String("scan", "sections", "what is scanned for strings (optional, sections/file/segments/range)")
+	rangeOpt    = flag.String("range", "", "the start-end file offsets to scan, used with -scan=range (optional)")
+	rangeVAOpt  = flag.Bool("range-va", false, "treat -range as virtual addresses rather than file offsets (optional)")
+	encodingOpt = flag.String("encodings", "ascii", "comma separated encodings of the strings to extract (optional, ascii/utf16le/utf16be/utf32le/utf32be/all)")
+	unalignOpt  = flag.Bool("unaligned", false, "look for wide strings at every byte offset, not only at their natural alignment (optional)")
+	scoreOpt    = flag.Float64("min-score", 0, "the minimum readability score of a string, from 0 for random bytes to 1 for text, 0.4 keeps most text and drops most junk (optional, off by default)")
+	rankOpt     = flag.Bool("rank", false, "order the strings by relevance with a breakdown of why, the code which references them only counts with -xrefs, -max-count then limits the total (optional)")
+	iocsOpt     = flag.Bool("iocs-only", false, "only print the deduplicated indicators of compromise found, and where each was found (optional)")
+	tagOpt      = flag.String("tag", "", "comma separated categories of strings to keep (optional, format/path/cmd/sql/message/env/registry/crypto/useragent/mangled/ioc)")
+	xrefsOpt    = flag.Bool("xrefs", false, "show the instructions which reference each string and the function they are in (optional)")
+	byFuncOpt   = flag.Bool("by-function", false, "list each function with the strings it references, found from the symbols or the exception frames when stripped, -max-count then limits the functions (optional)")
+	stackOpt    = flag.Bool("stack", false, "also recover the strings that x86 and AArch64 code builds on the stack with immediate stores (optional)")
+	bruteOpt    = flag.Bool("bruteforce", false, "also try every single byte XOR, ADD and ROL key and the repeating XOR keys found from known plaintext on the sections, and show the readable strings with their key (optional)")
+	unwrapOpt   = flag.Uint64("unwrap", 0, "how many layers of Base64, hex, URL encoding and ROT13 to decode from each string, along with the gzip, zlib, bzip2 and ELF within them, zero to not decode them (optional)")
+	decodeOpt   = flag.Bool("decode", false, "also emulate the x86-64 functions which look like they decode strings at runtime, and show what they decode (optional)")
+	stepsOpt    = flag.Uint64("emulate-steps", elfstrings.DefaultEmulateSteps, "the maximum amount of instructions each emulated call may run, used with -decode (optional)")
+	timeoutOpt  = flag.Duration("emulate-timeout", elfstrings.DefaultEmulateTimeout, "the maximum time that the emulation may take in all, used with -decode (optional)")
+	noSplitOpt  = flag.Bool("no-split", false, "don't split the string literals of Go and Rust binaries, which are stored back to back, at the ends found from their string headers (optional)")
+	goSymsOpt   = flag.Bool("go-symbols", false, "show every function name and source file path of Go binaries, read from the pclntab even when they are stripped (optional)")
+	panicsOpt   = flag.Bool("rust-panics", false, "show every panic location of Rust binaries, the source file, line and column that each panic reports (optional)")
+	prefixOpt   = flag.String("prefixed", "", "comma separated lengths that strings which are not terminated are recovered after, u8 for Free Pascal binaries when not given (optional, u8/u16le/u16be/u32le/u32be/all/none)")
+	classesOpt  = flag.String("classes", "", "show the C++ classes recovered from the run time type information, each under the classes it inherits from or as a DOT graph which is printed on its own, with their vtables (optional, tree/dot)")
+	swiftOpt    = flag.Bool("swift-types", false, "show the types of Swift binaries with their fields and the protocols they conform to, read from the reflection metadata (optional)")
+	legacyOpt   = flag.String("legacy", "", "comma separated legacy encodings to try on strings that are not UTF-8 (optional, sjis/gbk/koi8-r/cp1250-cp1258/all)")
+)
+
+// ReadSection will extract the strings from the section, along with
+// those brute forced from it if asked for, and print them, writing them
+// to the output file if one is given
+func ReadSection(reader *elfstrings.ElfReader, section *elf.Section, opts *elfstrings.Options, writer *elfstrings.OutWriter) {
+	PrintRecords(reader.ReaderExtract(section, opts), writer)
+
+	if *bruteOpt {
+		PrintRecords(reader.ReaderBruteForce(section, opts), writer)
+	}
+}
+
+// ReadRegion will scan the region of the file for printable runs
+// and print them, writing them to the output file if one is given
+func ReadRegion(reader *elfstrings.ElfReader, region elfstrings.ScanRegion, opts *elfstrings.Options, writer *elfstrings.OutWriter) {
+	PrintRecords(reader.ReaderScan(region, opts), writer)
+}
+
+// ReadRecovered will recover the strings built on the stack and those
+// decoded at runtime if asked for, adding them to the collected records
+// when those are printed later
+func ReadRecovered(reader *elfstrings.ElfReader, opts *elfstrings.Options, collected []elfstrings.StringRecord, writer *elfstrings.OutWriter) []elfstrings.StringRecord {
+	var records []elfstrings.StringRecord
+	if *stackOpt {
+		records = append(records, reader.ReaderStackStrings(opts)...)
+	}
+
+	if *decodeOpt {
+		records = append(records, reader.ReaderDecodedStrings(opts)...)
+	}
+
+	if *rankOpt || *iocsOpt || *byFuncOpt {
+		return append(collected, records...)
+	}
+
+	PrintRecords(records, writer)
+
+	return collected
+}
+
+// PrintCollected will print the records that were collected rather than
+// printed as they were found, either as indicators or ranked
+func PrintCollected(reader *elfstrings.ElfReader, records []elfstrings.StringRecord, writer *elfstrings.OutWriter) {
+	if *iocsOpt {
+		PrintIndicators(records, writer)
+	} else if *byFuncOpt {
+		PrintFunctions(reader, records, writer)
+	} else if *rankOpt {
+		RankRecords(reader, records, writer)
+	}
+}
+
+// PrintFunctions will print each function with the strings it references,
+// ranked first if asked for, writing them to the output file if one is given
+func PrintFunctions(reader *elfstrings.ElfReader, records []elfstrings.StringRecord, writer *elfstrings.OutWriter) {
+	if *rankOpt {
+		records = reader.ReaderRank(records)
+	}
+
+	functions := reader.ReaderGroupByFunction(records)
+	if *maxOpt != 0 && uint64(len(functions)) > *maxOpt {
+		functions = functions[:*maxOpt]
+	}
+
+	for i := range functions {
+		fn := &functions[i]
+
+		name := fn.Name
+		if name == "" {
+			name = "(no function)"
+		}
+
+		if os.Getenv("NO_COLOR") != "" || *colorOpt {
+			fmt.Printf("[+] %s %#x: %d strings\n", name, fn.Address, len(fn.Records))
+		} else {
+			fmt.Printf("[+] %s %s: %d strings\n",
+				color.BlueString(name),
+				color.GreenString("%#x", fn.Address),
+				len(fn.Records))
+		}
+
+		for j := range fn.Records {
+			fmt.Print("    ")
+			printRecord(&fn.Records[j])
+		}
+
+		if writer != nil {
+			writer.WriteFunction(fn)
+		}
+	}
+}
+
+// RankRecords will rank every record found by relevance, keeping
+// the most relevant up to the maximum amount of strings, then print them
+func RankRecords(reader *elfstrings.ElfReader, records []elfstrings.StringRecord, writer *elfstrings.OutWriter) {
+	records = reader.ReaderRank(records)
+	if *maxOpt != 0 && uint64(len(records)) > *maxOpt {
+		records = records[:*maxOpt]
+	}
+
+	PrintRecords(records, writer)
+}
+
+// PrintIndicators will print the deduplicated indicators found in the
+// records, writing them to the output file if one is given
+func PrintIndicators(records []elfstrings.StringRecord, writer *elfstrings.OutWriter) {
+	for _, ind := range elfstrings.UtilCollectIndicators(records) {
+		var sources []string
+		for _, src := range ind.Sources {
+			sources = append(sources, fmt.Sprintf("%s+%#x", src.Section, src.Offset))
+		}
+
+		defanged := ""
+		if ind.Defanged {
+			defanged = " (defanged)"
+		}
+
+		if os.Getenv("NO_COLOR") != "" || *colorOpt {
+			fmt.Printf("[%s] %s%s [%s]\n", ind.Type, ind.Value, defanged, strings.Join(sources, ", "))
+		} else {
+			fmt.Printf("[%s] %s%s [%s]\n",
+				color.BlueString(ind.Type.String()),
+				ind.Value,
+				defanged,
+				color.GreenString(strings.Join(sources, ", ")))
+		}
+
+		if writer != nil {
+			writer.WriteIndicator(&ind)
+		}
+	}
+}
+
+// PrintRecords will print the records found, writing them
+// to the output file if one is given
+func PrintRecords(records []elfstrings.StringRecord, writer *elfstrings.OutWriter) {
+	for i := range records {
+		rec := &records[i]
+
+		printRecord(rec)
+
+		if writer != nil {
+			writer.WriteResult(rec)
+		}
+	}
+}
+
+// printRecord will print the record with where it was found, the strings
+// decoded from another are indented by how many strings they are within
+func printRecord(rec *elfstrings.StringRecord) {
+	for parent := rec.Parent; parent != nil; parent = parent.Parent {
+		fmt.Print("    ")
+	}
+
+	if !*offsetOpt {
+		fmt.Println(rec.Text)
+		return
+	}
+
+	if os.Getenv("NO_COLOR") != "" || *colorOpt {
+		fmt.Printf("[%s+%#x%s]: %s\n",
+			rec.Section,
+			rec.Offset,
+			recordLocation(rec),
+			rec.Text)
+	} else {
+		fmt.Printf("[%s%s%s]: %s\n",
+			color.BlueString(rec.Section),
+			color.GreenString("+%#x", rec.Offset),
+			color.YellowString(recordLocation(rec)),
+			rec.Text)
+	}
+}
+
+// recordLocation will format the encoding of the string when it is
+// wide, and where it resides in the file and memory if asked for
+func recordLocation(rec *elfstrings.StringRecord) string {
+	loc := ""
+	if rec.Encoding != elfstrings.EncodingASCII {
+		loc += " " + rec.Encoding.String()
+	}
+
+	if rec.Source != elfstrings.SourceSection {
+		loc += " " + rec.Source.String()
+	}
+
+	if rec.Prefix != elfstrings.PrefixNone {
+		loc += " prefix:" + rec.Prefix.String()
+	}
+
+	if rec.Language != elfstrings.LanguageNone {
+		loc += " lang:" + rec.Language.String()
+	}
+
+	if rec.FunctionAddress != 0 {
+		loc += fmt.Sprintf(" func:%#x", rec.FunctionAddress)
+	}
+
+	if len(rec.Decodings) != 0 {
+		var decodings []string
+		for _, decoding := range rec.Decodings {
+			decodings = append(decodings, decoding.String())
+		}
+
+		loc += " via:" + strings.Join(decodings, ">")
+	}
+
+	if len(rec.Tags) != 0 {
+		var tags []string
+		for _, tag := range rec.Tags {
+			tags = append(tags, tag.String())
+		}
+
+		loc += " tags:" + strings.Join(tags, ",")
+	}
+
+	if len(rec.IOCs) != 0 {
+		var types []string
+		for _, ioc := range rec.IOCs {
+			types = append(types, ioc.Type.String())
+		}
+
+		loc += " ioc:" + strings.Join(types, ",")
+	}
+
+	if len(rec.Xrefs) != 0 {
+		var xrefs []string
+		for _, xref := range rec.Xrefs {
+			if xref.Function != "" {
+				xrefs = append(xrefs, fmt.Sprintf("%#x(%s)", xref.Address, xref.Function))
+			} else {
+				xrefs = append(xrefs, fmt.Sprintf("%#x", xref.Address))
+			}
+		}
+
+		loc += " xrefs:" + strings.Join(xrefs, ",")
+	}
+
+	if rec.Rank != nil {
+		loc += fmt.Sprintf(" rank:%.2f (read %.2f len %.2f uniq %.2f ioc %.2f fmt %.2f ref %.2f boiler %.2f)",
+			rec.Rank.Total,
+			rec.Rank.Readability,
+			rec.Rank.Length,
+			rec.Rank.Uniqueness,
+			rec.Rank.IOC,
+			rec.Rank.Format,
+			rec.Rank.Referenced,
+			rec.Rank.Boilerplate)
+	}
+
+	if !*addressOpt {
+		return loc
+	}
+
+	loc += fmt.Sprintf(" file:%#x", rec.FileOffset)
+	if rec.Address != 0 {
+		loc += fmt.Sprintf(" va:%#x", rec.Address)
+	}
+
+	if rec.Segment != nil {
+		loc += fmt.Sprintf(" %s %s", rec.Segment.Name(), rec.Segment.Perms())
+	}
+
+	return loc
+}
+
+// ReadBasic will read the basic information
+// about the ELF
+func ReadBasic(reader *elfstrings.ElfReader, writer *elfstrings.OutWriter) {
+	stat, err := reader.File.Stat()
+	if err != nil {
+		return
+	}
+
+	size := humanize.Bytes(uint64(stat.Size()))
+
+	fmt.Printf(
+		"[+] Size: %s\n"+
+			"[+] Arch: %s\n"+
+			"[+] Entry point: %#x\n"+
+			"[+] Class: %s\n"+
+			"[+] Byte order: %s\n",
+		size,
+		elfstrings.UtilConvertMachine(reader.ExecReader.Machine),
+		reader.ExecReader.Entry,
+		reader.ExecReader.Class.String(),
+		reader.ExecReader.ByteOrder.String(),
+	)
+
+	if reader.ReaderIsFPC() {
+		fmt.Println("[+] Compiler: Free Pascal")
+	} else if info, err := reader.ReaderGoInfo(); err == nil {
+		ReadGoInfo(info)
+		if writer != nil {
+			writer.WriteGoInfo(info)
+		}
+	} else if info, err := reader.ReaderRustInfo(); err == nil {
+		ReadRustInfo(info)
+		if writer != nil {
+			writer.WriteRustInfo(info)
+		}
+	}
+
+	if *libOpt {
+		fmt.Println("[+] Libraries:")
+		libs, err := reader.ExecReader.ImportedLibraries()
+		if err == nil {
+			for _, lib := range libs {
+				fmt.Printf("\t [!] %s\n", lib)
+			}
+		}
+	}
+
+	fmt.Println(strings.Repeat("-", 16))
+}
+
+// ReadGoInfo will print the version of Go, the modules and the build
+// settings of a Go binary, along with its functions and source files
+// when they were asked for
+func ReadGoInfo(info *elfstrings.GoInfo) {
+	// the version is written as go1.22.1, so it already names Go
+	if info.Version != "" {
+		fmt.Printf("[+] Compiler: %s\n", info.Version)
+	} else {
+		fmt.Println("[+] Compiler: Go")
+	}
+
+	if info.Main.Path != "" {
+		fmt.Printf("[+] Go module: %s\n", goModule(&info.Main))
+	} else if info.Path != "" {
+		fmt.Printf("[+] Go package: %s\n", info.Path)
+	}
+
+	if len(info.Deps) != 0 {
+		fmt.Println("[+] Go dependencies:")
+		for i := range info.Deps {
+			fmt.Printf("\t [!] %s\n", goModule(&info.Deps[i]))
+		}
+	}
+
+	if len(info.Settings) != 0 {
+		fmt.Println("[+] Go build settings:")
+		for _, setting := range info.Settings {
+			fmt.Printf("\t [!] %s=%s\n", setting.Key, setting.Value)
+		}
+	}
+
+	if !*goSymsOpt {
+		fmt.Printf("[+] Go functions: %d, source files: %d\n", len(info.Functions), len(info.Files))
+		return
+	}
+
+	fmt.Println("[+] Go functions:")
+	for _, fn := range info.Functions {
+		fmt.Printf("\t [!] %s\n", fn)
+	}
+
+	fmt.Println("[+] Go source files:")
+	for _, file := range info.Files {
+		fmt.Printf("\t [!] %s\n", file)
+	}
+}
+
+// ReadRustInfo will print the version and commit of rustc and the crates
+// of a Rust binary, along with its panic locations when they were asked for
+func ReadRustInfo(info *elfstrings.RustInfo) {
+	fmt.Println(strings.TrimSpace("[+] Compiler: Rust " + info.Version))
+
+	if info.Commit != "" {
+		fmt.Printf("[+] Rust commit: %s\n", info.Commit)
+	}
+
+	if len(info.Crates) != 0 {
+		fmt.Println("[+] Rust crates:")
+		for _, crate := range info.Crates {
+			fmt.Printf("\t [!] %s (%s)\n", strings.TrimSpace(crate.Name+" "+crate.Version), crate.Source)
+		}
+	}
+
+	if !*panicsOpt {
+		fmt.Printf("[+] Rust panic locations: %d\n", len(info.Locations))
+		return
+	}
+
+	fmt.Println("[+] Rust panic locations:")
+	for _, loc := range info.Locations {
+		fmt.Printf("\t [!] %#x %s:%d:%d\n", loc.Address, loc.File, loc.Line, loc.Column)
+	}
+}
+
+// goModule will format the module with its version and sum, and with the
+// module that replaced it
+func goModule(mod *elfstrings.GoModule) string {
+	text := strings.TrimSpace(strings.Join([]string{mod.Path, mod.Version, mod.Sum}, " "))
+	if mod.Replace != nil {
+		text += " => " + goModule(mod.Replace)
+	}
+
+	return text
+}
+
+// ReadClasses will print the C++ classes recovered from the run time type
+// information, either each under the classes it inherits from or as a DOT
+// graph, writing them to the output file if one is given. The graph is all
+// that is printed so that it can be piped into dot
+func ReadClasses(reader *elfstrings.ElfReader, opts *elfstrings.DemangleOptions, writer *elfstrings.OutWriter) {
+	classes, err := reader.ReaderClasses(opts)
+	if err != nil {
+		if *classesOpt == "dot" {
+			fmt.Fprintln(os.Stderr, "[!] No C++ run time type information found")
+		} else {
+			fmt.Println("[!] No C++ run time type information found")
+		}
+
+		return
+	}
+
+	if *classesOpt == "dot" {
+		fmt.Print(elfstrings.UtilClassGraph(classes))
+	} else {
+		byName := make(map[string]*elfstrings.CxxClass)
+		for i := range classes {
+			byName[classes[i].Name] = &classes[i]
+		}
+
+		fmt.Printf("[+] C++ classes: %d\n", len(classes))
+		for i := range classes {
+			if len(classes[i].Bases) == 0 {
+				printClass(&classes[i], nil, byName, 0, make(map[string]bool))
+			}
+		}
+	}
+
+	if writer != nil {
+		for i := range classes {
+			writer.WriteClass(&classes[i])
+		}
+	}
+}
+
+// printClass will print the class with its typeinfo and vtables, and how
+// it inherits from the base it is printed under, then each class derived
+// from it indented under it
+func printClass(cls *elfstrings.CxxClass, base *elfstrings.ClassBase, byName map[string]*elfstrings.CxxClass, depth int, path map[string]bool) {
+	line := cls.Name
+	if base != nil {
+		if spec := elfstrings.UtilBaseSpecifier(base); spec != "" {
+			line += " (" + spec + ")"
+		}
+	}
+
+	if cls.TypeInfo != 0 {
+		line += fmt.Sprintf(" typeinfo:%#x", cls.TypeInfo)
+	} else {
+		line += " " + cls.Kind.String()
+	}
+
+	for i, vt := range cls.VTables {
+		if i == 0 {
+			line += " vtables:"
+		} else {
+			line += ","
+		}
+
+		line += fmt.Sprintf("%#x", vt.Address)
+	}
+
+	fmt.Printf("\t%s [!] %s\n", strings.Repeat("    ", depth), line)
+
+	// a class is only expanded once along each path, which holds back
+	// the typeinfo that is malformed into a cycle
+	path[cls.Name] = true
+	defer delete(path, cls.Name)
+
+	for _, name := range cls.Derived {
+		derived, ok := byName[name]
+		if !ok || path[name] {
+			continue
+		}
+
+		for i := range derived.Bases {
+			if derived.Bases[i].Name == cls.Name {
+				printClass(derived, &derived.Bases[i], byName, depth+1, path)
+				break
+			}
+		}
+	}
+}
+
+// ReadSwiftTypes will print the types of a Swift binary, each with what it
+// inherits from and conforms to and then its fields, writing them to the
+// output file if one is given
+func ReadSwiftTypes(reader *elfstrings.ElfReader, opts *elfstrings.DemangleOptions, writer *elfstrings.OutWriter) {
+	types, err := reader.ReaderSwiftTypes(opts)
+	if err != nil {
+		fmt.Println("[!] No Swift reflection metadata found")
+		return
+	}
+
+	fmt.Printf("[+] Swift types: %d\n", len(types))
+	for i := range types {
+		ty := &types[i]
+
+		inherits := ty.Conformances
+		if ty.Superclass != "" {
+			inherits = append([]string{ty.Superclass}, inherits...)
+		}
+
+		line := ty.Kind.String() + " " + ty.Name
+		if len(inherits) != 0 {
+			line += " : " + strings.Join(inherits, ", ")
+		}
+
+		fmt.Printf("\t [!] %s\n", line)
+		for j := range ty.Fields {
+			fmt.Printf("\t     %s\n", swiftField(ty.Kind, &ty.Fields[j]))
+		}
+
+		if writer != nil {
+			writer.WriteSwiftType(ty)
+		}
+	}
+}
+
+// swiftField will write the field the way it is declared, as a property or
+// as a case of an enum with its payload
+func swiftField(kind elfstrings.SwiftTypeKind, field *elfstrings.SwiftField) string {
+	if !kind.IsEnum() {
+		keyword := "let"
+		if field.Var {
+			keyword = "var"
+		}
+
+		return keyword + " " + field.Name + ": " + field.Type
+	}
+
+	text := "case " + field.Name
+	if field.Indirect {
+		text = "indirect " + text
+	}
+
+	if strings.HasPrefix(field.Type, "(") {
+		text += field.Type
+	} else if field.Type != "" {
+		text += "(" + field.Type + ")"
+	}
+
+	return text
+}
+
+// selectorFromFlags will build the section selector
+// from the command line arguments
+func selectorFromFlags() (*elfstrings.SectionSelector, error) {
+	var sel elfstrings.SectionSelector
+	var err error
+
+	sel.Mode, err = elfstrings.SectionParseModeStr(*sectionsOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	// the names of the fields of the Swift types are only scanned along
+	// with the types themselves
+	if *swiftOpt {
+		sel.Names = append(append([]string{}, elfstrings.DefaultSections...), "swift5_reflstr")
+	}
+
+	if *matchOpt != "" {
+		sel.Globs = strings.Split(*matchOpt, ",")
+	}
+
+	if *regexOpt != "" {
+		sel.Regex, err = regexp.Compile(*regexOpt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sel.Flags, sel.NoExec, err = elfstrings.SectionParseFlagsStr(*flagsOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	sel.Types, err = elfstrings.SectionParseTypesStr(*typeOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sel, nil
+}
+
+// main is the entrypoint for this program
+func main() {
+	flag.Parse()
+
+	if *binaryOpt == "" {
+		flag.PrintDefaults()
+		return
+	}
+
+	r, err := elfstrings.NewELFReader(*binaryOpt)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	defer r.Close()
+
+	var writer *elfstrings.OutWriter
+	if *outputOpt != "" {
+		writer, err = elfstrings.NewOutWriter(*outputOpt, elfstrings.OutParseTypeStr(*formatOpt))
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+
+		defer writer.Close()
+	}
+
+	encodings, err := elfstrings.EncodingParseStr(*encodingOpt)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	legacy, err := elfstrings.EncodingParseLegacyStr(*legacyOpt)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	tags, err := elfstrings.TagParseStr(*tagOpt)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	prefixes, err := elfstrings.PrefixParseStr(*prefixOpt)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	if *classesOpt != "" && *classesOpt != "tree" && *classesOpt != "dot" {
+		log.Fatal("this class hierarchy format does not exist")
+	}
+
+	opts := &elfstrings.Options{
+		MinLength: *minOpt,
+		MaxCount:  *maxOpt,
+		Demangle:  *demangleOpt,
+		Demangling: elfstrings.DemangleOptions{
+			NoParams:       *noParamsOpt,
+			NoTemplateArgs: *noTmplOpt,
+			SimplifyStd:    *simpleOpt,
+			GoNames:        r.ReaderIsGo(),
+		},
+		Hex:            *hexOpt,
+		NoTrim:         *trimOpt,
+		NoHuman:        *humanOpt,
+		NoSplit:        *noSplitOpt,
+		Encodings:      encodings,
+		Unaligned:      *unalignOpt,
+		Legacy:         legacy,
+		MinScore:       *scoreOpt,
+		Tags:           tags,
+		Xrefs:          *xrefsOpt || *byFuncOpt,
+		EmulateSteps:   *stepsOpt,
+		EmulateTimeout: *timeoutOpt,
+		UnwrapDepth:    *unwrapOpt,
+		Prefixes:       prefixes,
+	}
+
+	// every string has to be found before any can be ranked or its
+	// indicators merged, so the maximum amount is applied afterwards
+	var collected []elfstrings.StringRecord
+	collect := *rankOpt || *iocsOpt || *byFuncOpt
+	if collect {
+		opts.MaxCount = 0
+	}
+
+	sel, err := selectorFromFlags()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	mode, err := elfstrings.ScanParseModeStr(*scanOpt)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	// the graph is printed on its own so that it can be piped into dot
+	if *classesOpt == "dot" {
+		ReadClasses(r, &opts.Demangling, writer)
+		return
+	}
+
+	ReadBasic(r, writer)
+
+	if *classesOpt != "" {
+		ReadClasses(r, &opts.Demangling, writer)
+	}
+
+	if *swiftOpt {
+		ReadSwiftTypes(r, &opts.Demangling, writer)
+	}
+
+	// without any section headers there is nothing to parse, so fall
+	// back to scanning what is actually loaded into memory
+	if mode == elfstrings.ScanSections && len(r.ExecReader.Sections) <= 1 {
+		fmt.Println("[!] No section headers, scanning the segments instead")
+		mode = elfstrings.ScanSegments
+
+		if len(r.ExecReader.Progs) == 0 {
+			mode = elfstrings.ScanFile
+		}
+	}
+
+	if mode != elfstrings.ScanSections {
+		var start, end uint64
+		if mode == elfstrings.ScanRange {
+			start, end, err = elfstrings.ScanParseRangeStr(*rangeOpt)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+		}
+
+		regions, err := r.ReaderScanRegions(mode, start, end, *rangeVAOpt)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+
+		for _, region := range regions {
+			if collect {
+				collected = append(collected, r.ReaderScan(region, opts)...)
+				continue
+			}
+
+			ReadRegion(r, region, opts, writer)
+		}
+
+		collected = ReadRecovered(r, opts, collected, writer)
+		PrintCollected(r, collected, writer)
+
+		return
+	}
+
+	sections, skipped := r.ReaderSelectSections(sel)
+	if *skippedOpt {
+		for _, skip := range skipped {
+			fmt.Printf("[-] Skipped %s: %s\n", skip.Name, skip.Reason)
+		}
+	}
+
+	for _, section := range sections {
+		if collect {
+			collected = append(collected, r.ReaderExtract(section, opts)...)
+			if *bruteOpt {
+				collected = append(collected, r.ReaderBruteForce(section, opts)...)
+			}
+
+			continue
+		}
+
+		ReadSection(r, section, opts, writer)
+	}
+
+	collected = ReadRecovered(r, opts, collected, writer)
+	PrintCollected(r, collected, writer)
+}